@@ -0,0 +1,272 @@
+package fsextender
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/rekby/gpt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version of partition table backup format
+// Версия формата резервной копии таблицы разделов
+const backup_VERSION = 1
+
+/*
+Backup of partition table: raw sectors of disk, which contain partition table.
+msdos: MBR and EBRs of logical partitions. gpt: protective MBR, primary and backup GPT, end of disk (place of backup GPT
+after disk extend).
+
+Резервная копия таблицы разделов: сырые сектора диска, содержащие таблицу разделов.
+msdos: MBR и EBR логических разделов. gpt: защитный MBR, основная и резервная GPT, конец диска (место резервной GPT
+после расширения диска).
+*/
+type tableBackup struct {
+	Version    int            `json:"version"`
+	Time       time.Time      `json:"time"`
+	Disk       string         `json:"disk"`
+	DiskSize   uint64         `json:"disk_size"`   // Bytes
+	SectorSize uint64         `json:"sector_size"` // Bytes
+	PartTable  string         `json:"part_table"`
+	DiskGUID   string         `json:"disk_guid,omitempty"` // gpt only
+	Regions    []backupRegion `json:"regions"`
+}
+
+type backupRegion struct {
+	Name   string `json:"name"`
+	Offset uint64 `json:"offset"` // Bytes from start of disk. Байты от начала диска
+	Data   []byte `json:"data"`
+}
+
+/*
+Read partition table sectors of disk.
+extraOffsets - offsets of sectors, which will be written in addition to current partition table (for example EBR of
+new logical partition).
+
+Читает сектора таблицы разделов диска.
+extraOffsets - смещения секторов, которые будут записаны в дополнение к текущей таблице разделов (например EBR
+нового логического раздела).
+*/
+func makeTableBackup(diskIO io.ReadSeeker, disk diskInfo, extraOffsets []uint64) (res tableBackup, err error) {
+	res = tableBackup{Version: backup_VERSION, Time: time.Now(), Disk: disk.Path, DiskSize: disk.Size,
+		SectorSize: disk.SectorSizeLogical, PartTable: disk.PartTable}
+	sectorSize := disk.SectorSizeLogical
+	if sectorSize == 0 {
+		return res, fmt.Errorf("Unknown sector size of disk: %v", disk.Path)
+	}
+
+	added := make(map[uint64]bool)
+	add := func(name string, offset, size uint64) error {
+		if added[offset] {
+			return nil
+		}
+		if offset+size > disk.Size {
+			return fmt.Errorf("Region %v out of disk: %v+%v > %v", name, offset, size, disk.Size)
+		}
+		data := make([]byte, size)
+		if _, err := diskIO.Seek(int64(offset), 0); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(diskIO, data); err != nil {
+			return fmt.Errorf("Can't read %v: %v", name, err)
+		}
+		added[offset] = true
+		res.Regions = append(res.Regions, backupRegion{Name: name, Offset: offset, Data: data})
+		return nil
+	}
+
+	switch disk.PartTable {
+	case "msdos":
+		if err = add("mbr", 0, sectorSize); err != nil {
+			return res, err
+		}
+		if extended, _, ok := disk.extendedPartition(); ok {
+			if err = add("ebr", extended.FirstByte, sectorSize); err != nil {
+				return res, err
+			}
+		}
+		for _, logical := range disk.LogicalPartitions {
+			if logical.IsFreeSpace() {
+				continue
+			}
+			if err = add("ebr", logical.EBRByte, sectorSize); err != nil {
+				return res, err
+			}
+		}
+	case "gpt":
+		if err = add("protective mbr", 0, sectorSize); err != nil {
+			return res, err
+		}
+		if _, err = diskIO.Seek(int64(sectorSize), 0); err != nil {
+			return res, err
+		}
+		table, err := gpt.ReadTable(diskIO, sectorSize)
+		if err != nil {
+			return res, fmt.Errorf("Can't read gpt table: %v", err)
+		}
+		res.DiskGUID = table.Header.DiskGUID.String()
+		entriesSize := uint64(table.Header.PartitionsArrLen) * uint64(table.Header.PartitionEntrySize)
+		entriesSectors := (entriesSize + sectorSize - 1) / sectorSize
+		if err = add("gpt primary header", table.Header.HeaderStartLBA*sectorSize, sectorSize); err != nil {
+			return res, err
+		}
+		if err = add("gpt primary entries", table.Header.PartitionsTableStartLBA*sectorSize, entriesSectors*sectorSize); err != nil {
+			return res, err
+		}
+		// Backup GPT entries placed just before backup header
+		// Записи резервной GPT расположены сразу перед резервным заголовком
+		if table.Header.HeaderCopyStartLBA > entriesSectors && (table.Header.HeaderCopyStartLBA+1)*sectorSize <= disk.Size {
+			if err = add("gpt backup", (table.Header.HeaderCopyStartLBA-entriesSectors)*sectorSize, (entriesSectors+1)*sectorSize); err != nil {
+				return res, err
+			}
+		}
+		if err = add("end of disk", disk.Size/sectorSize*sectorSize-(entriesSectors+1)*sectorSize, (entriesSectors+1)*sectorSize); err != nil {
+			return res, err
+		}
+	default:
+		return res, fmt.Errorf("I don't know partition table: %v(%v)", disk.PartTable, disk.Path)
+	}
+
+	for _, offset := range extraOffsets {
+		if err = add("new", offset, sectorSize); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+/*
+Check, that backup was made from the disk: size, sector size and GUID of gpt disk (from primary or backup header).
+Проверяет, что копия снята с этого диска: размер, размер сектора и GUID диска gpt (из основного или резервного заголовка).
+*/
+func (backup tableBackup) Check(diskIO io.ReadSeeker, diskSize, sectorSize uint64) error {
+	if backup.DiskSize != diskSize {
+		return fmt.Errorf("Disk size changed: %v -> %v", backup.DiskSize, diskSize)
+	}
+	if backup.SectorSize != sectorSize {
+		return fmt.Errorf("Sector size changed: %v -> %v", backup.SectorSize, sectorSize)
+	}
+	if backup.PartTable != "gpt" {
+		return nil
+	}
+	headers := []uint64{1}
+	for _, region := range backup.Regions {
+		if region.Name == "gpt backup" {
+			headers = append(headers, (region.Offset+uint64(len(region.Data)))/sectorSize-1)
+		}
+	}
+	for _, lba := range headers {
+		if _, err := diskIO.Seek(int64(lba*sectorSize), 0); err != nil {
+			return err
+		}
+		table, err := gpt.ReadTable(diskIO, sectorSize)
+		if err != nil {
+			continue
+		}
+		if guid := table.Header.DiskGUID.String(); guid != backup.DiskGUID {
+			return fmt.Errorf("Disk GUID differ: %v -> %v", backup.DiskGUID, guid)
+		}
+		return nil
+	}
+	return fmt.Errorf("Can't read GPT header of disk for check disk GUID")
+}
+
+// Write saved sectors back to disk
+// Записывает сохранённые сектора обратно на диск
+func (backup tableBackup) Restore(diskIO io.WriteSeeker) error {
+	for _, region := range backup.Regions {
+		if _, err := diskIO.Seek(int64(region.Offset), 0); err != nil {
+			return err
+		}
+		if _, err := diskIO.Write(region.Data); err != nil {
+			return fmt.Errorf("Can't write %v at %v: %v", region.Name, region.Offset, err)
+		}
+	}
+	return nil
+}
+
+/*
+Save partition table of disk to new timestamped file in backupDir. Return path of the file.
+Сохраняет таблицу разделов диска в новый файл с отметкой времени в backupDir. Возвращает путь к файлу.
+*/
+func backupPartitionTable(diskPath, backupDir string, extraOffsets ...uint64) (backupPath string, err error) {
+	disk, err := readDiskInfo(diskPath)
+	if err != nil {
+		return "", err
+	}
+	diskIO, err := os.Open(diskPath)
+	if err != nil {
+		return "", err
+	}
+	defer diskIO.Close()
+	backup, err := makeTableBackup(diskIO, disk, extraOffsets)
+	if err != nil {
+		return "", err
+	}
+	content, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(backupDir, 0700); err != nil {
+		return "", err
+	}
+	name := strings.Replace(strings.TrimPrefix(diskPath, "/dev/"), "/", "_", -1)
+	backupPath = filepath.Join(backupDir, name+"-"+backup.Time.Format("20060102-150405.000000000")+".json")
+	return backupPath, ioutil.WriteFile(backupPath, append(content, '\n'), 0600)
+}
+
+func readTableBackup(path string) (backup tableBackup, err error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return backup, err
+	}
+	if err = json.Unmarshal(content, &backup); err != nil {
+		return backup, fmt.Errorf("Can't parse backup file %v: %v", path, err)
+	}
+	if backup.Version != backup_VERSION {
+		return backup, fmt.Errorf("Unsupported version of backup file %v: %v (need %v)", path, backup.Version, backup_VERSION)
+	}
+	return backup, nil
+}
+
+/*
+Restore partition table from backup file to disk, which the backup was made from.
+Восстанавливает таблицу разделов из резервной копии на диск, с которого была снята копия.
+*/
+func restorePartitionTable(backupPath string) error {
+	backup, err := readTableBackup(backupPath)
+	if err != nil {
+		return usageError{err}
+	}
+	diskSize := getDiskSize(backup.Disk)
+	sectorSize := uint64(0)
+	if dev, err := blockDeviceByPath(backup.Disk); err == nil {
+		sectorSize = dev.LogicalBlockSize
+	}
+	if sectorSize == 0 {
+		blockSizeString, _, _ := cmd("blockdev", "--getss", backup.Disk)
+		sectorSize, _ = strconv.ParseUint(strings.TrimSpace(blockSizeString), 10, 64)
+	}
+
+	diskIO, err := os.OpenFile(backup.Disk, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return usageError{err}
+	}
+	defer diskIO.Close()
+	if err = backup.Check(diskIO, diskSize, sectorSize); err != nil {
+		return usageError{fmt.Errorf("Backup doesn't match disk %v: %v", backup.Disk, err)}
+	}
+	if err = backup.Restore(diskIO); err != nil {
+		return doError{fmt.Errorf("WARNING!!! Error while restore partition table. Disk partition table can be damaged check it. %v: %v", backup.Disk, err)}
+	}
+	diskIO.Close()
+	log.Printf("Partition table restored: %v from %v (%v)\n", backup.Disk, backupPath, backup.Time.Format(time.RFC3339))
+	kernelRereadPartitionTable(backup.Disk)
+	return nil
+}
@@ -69,7 +69,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _readmeMd = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xa4\x54\xdd\x6e\x1b\x45\x14\xbe\x9f\xa7\x38\xdc\xa0\x5a\xb2\xbd\x17\x70\x65\xa9\x42\xa1\x89\xaa\x4a\xa9\x12\x91\x52\x09\x45\x51\x35\x5e\x8f\xed\x21\xeb\xdd\xd5\xce\xac\x6b\x73\x95\x9f\xb6\x80\x5a\x51\x89\x2b\x2e\x90\xe0\x11\x4c\x1a\x27\xa6\x69\xcc\x2b\xcc\xbc\x11\xdf\x99\xad\xeb\x24\x0e\x50\x89\x0b\x27\xb3\xe7\xe7\x3b\xdf\x39\x73\xe6\xdb\xfd\x64\xf7\xcb\x52\x27\x1d\xda\xb1\xd2\x96\x66\xef\x4e\xdf\xda\xdc\xb4\xa2\xc8\x16\x72\xa8\x4d\x23\xd6\xcd\xac\xe8\x45\x85\xda\x6f\x8f\xa3\xae\x51\x23\xab\xd2\x8e\x2a\x9a\x66\xd8\xab\x7d\x6c\x70\x4d\xa0\xca\xbd\x6c\xa8\x0a\xd9\x53\x2b\x85\xe2\xe0\x48\x12\xd3\xd4\x19\x52\xf3\xcc\xac\x00\x44\x6d\xd9\xe9\x29\xae\xf9\x45\xbb\x90\x69\xdc\xbf\x3b\x90\xc6\xaa\xe2\x53\xa3\x8a\xa1\x8e\xd5\xdd\x9e\xb6\xfd\xb2\x5d\xfb\x07\xd0\xca\xbb\x82\x7a\x1d\xab\x26\xc4\x46\x70\x50\x57\x27\xca\x8c\x61\x1b\x90\xcd\x68\x20\x47\x64\xf4\x77\x8a\x9e\x02\x85\x4a\x4e\x4c\xb4\x4e\x7b\x94\xc8\xb1\x2a\x4c\x53\x3c\xb0\x14\xcb\x94\x2a\xd4\x16\xff\xff\xac\xce\x7f\x3f\xaf\xd3\xa8\x6b\xea\xb4\xf9\xf8\x21\x6d\x66\x3d\x1d\xcb\x84\x86\x59\x52\x0e\x54\x65\xdb\xee\x8f\xcd\x8a\xf1\x71\x38\xd3\xfd\x22\x2b\x73\xba\x13\x4a\xa6\xea\x29\x65\x05\x75\x0b\xa5\x28\x1f\xd6\x44\x9d\x72\x59\x58\x6d\x75\x96\x1a\xd2\x29\x3d\xdc\x59\xdf\xda\x21\x09\xe2\xf7\xb7\x1f\x2d\x7d\x64\x65\x1b\x7d\x7c\xe0\x17\x17\x4a\x5a\x15\xd0\xae\xe4\x73\xda\x2d\x64\x0c\x01\xa0\xa3\xcd\x7e\xd5\xf5\x7f\x95\x10\xee\x77\x37\xf1\x87\xfe\x07\x37\xf3\x07\xfe\xb5\x9b\xfa\x23\xf2\xcf\xdc\xc4\xfd\xe9\x2e\xdc\xdc\x9d\xf8\x63\xff\x13\xf9\x43\x78\x0f\xfd\x91\x9b\xba\x77\xfe\x98\xdc\xa9\x9b\x93\x7b\x87\xa0\xb7\xec\x09\xa7\x0b\xff\xca\x5d\x22\xe1\x0d\x5c\xfe\x00\x86\x73\x98\xa7\x7c\xaa\x93\x3b\x09\xe7\x00\x00\x2c\x42\xe0\xcc\x9d\x01\xec\x02\xbf\x33\x94\xff\x31\x80\xcc\xb8\x0e\x8a\x82\x05\x3e\x9a\xc2\xfd\x0a\xbc\xb3\x8a\xd1\xc1\x55\x92\xfe\xc8\xbf\xba\xe5\xb6\x02\xe1\x37\x08\xf9\x9e\x8b\xb9\xb7\x00\x9d\x12\xa3\x3e\xc3\xe9\xfc\x86\x1d\x5c\xe6\x4c\x9c\x47\x78\xdb\xfd\x21\x61\x02\x3e\x21\xe7\x88\x89\xcd\x61\x39\x45\x5f\xe0\xee\x5f\x53\xe8\xf5\xc4\xbf\xf4\xcf\xc5\x2a\xbc\x7f\xbe\x80\x47\x0c\x33\xe0\xe9\xb9\xbf\xf0\xc5\x53\x3a\x67\xeb\x07\x20\x7f\xcc\x2d\x5e\x2f\x70\xc9\xb8\xf5\x50\x83\x1d\x27\x70\xfd\x81\xdf\x69\xe5\xa8\xd5\x17\x03\x3e\xe5\x11\xfa\x97\x1c\x38\xe1\x4b\x99\x85\xf2\x13\x2e\x7f\xc8\x0c\x26\x48\xbb\x80\xf5\x05\x4e\x61\xbc\x57\xd2\x02\xb5\xb0\x1c\x02\x1e\xec\xc6\xf5\x79\x2f\xe9\x80\x6a\x35\xef\x45\xcb\xcc\xf6\x46\xfd\x8f\x98\xf1\xff\x24\x49\x0b\x92\xe2\x6b\xc3\x4a\xa4\x46\x72\x90\x27\xaa\x25\xdc\x6f\x48\x98\x55\xbb\xf6\x2f\x73\x6e\x89\xa5\x78\xd0\x6e\xa3\x01\xa1\x80\x70\xdc\x05\xb1\x27\x6b\x9b\x5f\x6d\xac\xad\x7f\xf3\x64\x7b\x73\xed\xde\xc6\xfa\x1e\x45\xfd\x0c\x6b\x80\x98\x4e\xb6\x27\xc4\x83\xd4\xd8\xa2\x8c\xc3\x9b\x31\x78\xc6\x78\xb5\x25\x33\x68\xda\x91\x15\xee\x17\x5c\x09\xd6\x1a\x4b\x79\x8c\xb7\xf0\x02\x95\xc2\x63\xc1\xc5\xb3\x11\x9f\xbc\xf1\xe8\x61\x99\x22\x98\x45\x91\xe2\xb9\x76\x54\xce\x74\xd2\x58\x2b\x83\x3e\x7e\x06\xd1\x29\x36\xfc\xb2\x5a\xdb\xf3\x30\xfa\xd9\xfb\xf7\x35\x0f\x8f\x67\xd6\x12\x22\xca\x8b\x2c\x8e\x06\x59\x99\x5a\x43\x0d\x80\x58\x15\x5b\x0a\xdf\x94\x67\x1a\x56\x11\x41\x01\x23\x21\xda\xc9\xbe\xee\x2c\x43\x58\x1a\x69\xa1\x8d\xe3\x5c\x09\x03\x31\xbf\x82\x20\xbf\xcd\x8a\xfa\x40\xa7\xd0\xab\xb4\x1c\xb4\x31\xa6\xac\x0b\x27\x6b\x34\xa0\xb2\x78\x1f\x67\x84\xf7\x94\xc5\x1c\x62\x8b\xb0\x20\xad\x1c\xc4\x62\xd3\x80\x3c\x29\x08\x30\xec\x03\x99\xea\xbc\x4c\x58\xb2\x82\x04\xad\x8a\x0e\x5b\xd0\x47\x5b\x21\xad\x50\x50\xb7\xce\xcd\x20\x92\x5d\x8c\x89\xe2\xbe\x4c\x7b\xc8\xa0\x47\x5b\xeb\x5b\x2d\xc4\xe6\x89\x8c\xdf\xc3\x2e\x49\x35\x2a\x0c\xd9\xc9\xad\xf8\x3b\x00\x00\xff\xff\x37\x8d\x38\x74\x15\x07\x00\x00")
+var _readmeMd = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xa4\x56\xdd\x6e\x1b\xbb\x11\xbe\xe7\x53\x4c\x71\x80\x1c\x0b\x58\x49\x27\xa8\xdb\x0b\x9d\xba\x85\xcf\x71\x60\x04\x71\x50\x23\x4e\x02\x14\x86\x11\x50\xbb\x23\x99\x35\x77\xb9\x25\xb9\xb2\xd5\x2b\xff\xc4\x49\x8a\x04\x0d\xd0\xab\x5e\x14\x68\x1f\x41\x51\xac\x58\xf1\x8f\xf2\x0a\xc3\x37\x2a\xc8\x95\x2c\xd9\x52\x82\x16\xe7\x46\x4b\x0d\xe7\xe7\x9b\x6f\x86\x1c\x6e\xc7\x2a\x4d\x31\xb3\x3b\x0d\xf8\xdd\xef\x61\x69\xfb\x57\xdb\x3f\xab\x0e\x6a\xde\x46\xd8\xb2\xdc\x16\x66\xe7\xde\x77\xcb\x3f\xfc\xb8\x6b\x6d\x6e\x1a\xf5\x7a\x1c\x36\xa5\x34\x35\xa1\xea\x1a\x73\x65\xea\x1a\xf7\x9a\xdd\x7a\xcb\xe0\x81\xc5\x2c\x41\x5d\x6f\xf2\xa4\x8d\x35\xd3\x69\xff\xa1\xa9\x79\x16\xef\xae\xa4\xdc\x58\xd4\xf7\x0c\xea\x8e\x88\x71\xa5\x2d\xec\x6e\xd1\xbc\xf7\xdd\xf2\xfd\x1f\xbf\xe1\xbc\xd4\x9a\xf3\x7e\xc7\xa7\x77\x52\x61\x6c\x3e\x8b\x9f\x0a\x21\x93\x85\x29\x58\xcd\x3b\xc2\x54\x63\x51\x53\xba\x3d\xe7\xdf\xe3\x5e\x00\xed\xdb\x46\x5f\x81\xf1\x14\x8d\x05\x13\x20\x40\xa2\xd0\x64\xdf\x5b\xe0\xb1\x2d\xb8\x84\x26\xc6\xbc\x30\x08\x4d\x6e\x30\x01\x95\x81\x92\x09\x14\x99\x29\xf2\x5c\x69\x8b\x09\x14\xcd\x22\xb3\x05\x74\x50\x1b\xa1\xb2\x1a\x54\x18\x7b\x10\xa2\x41\x4b\x48\x34\x5d\x63\x31\x05\xab\x20\xe5\x07\x60\xc4\x5f\x11\xf6\x85\xdd\x85\xc2\xa3\x91\x42\x64\x6d\x90\xbc\x8b\xda\xd4\xd8\x43\x0b\x31\xcf\xa0\x84\xda\xf0\xdf\x5f\x47\xfe\x77\x39\x82\x83\x96\x89\xa0\x69\x75\xcb\xc0\x92\xc8\x62\x59\x24\x08\x69\x21\xad\xa8\x26\xe8\x2b\x55\x89\x60\xe3\xf9\x63\xd8\x50\x6d\x11\x73\x09\x1d\x25\x8b\x14\x4b\xd9\xe6\x6e\xd7\xcc\x09\x9f\x87\x35\xac\x6b\x55\xe4\xb0\x14\x00\x65\xb8\x0f\x4a\x43\x4b\x23\x42\xde\xa9\xb0\x08\x72\xae\xad\xb0\x42\x65\x06\x44\x06\x8f\xb7\xd6\xfe\xb8\x35\x8d\x2e\xc7\xa1\x6e\x2b\x8d\x69\x4e\xa6\xe2\x0a\xf0\x2c\x81\xf5\xcd\xa7\x53\x11\x58\xde\x94\x68\x22\x86\x59\xac\xbb\xb9\xe7\xb0\xcc\xc2\xc0\x52\x92\x56\x83\x2c\x82\x8d\x67\x8f\xb6\x2a\x11\x48\x91\x15\x07\x60\x54\xcb\xee\x73\x8d\xf0\x64\xf5\xe1\x1a\x2c\xa5\x09\x4f\x52\xd0\x5c\x24\xf7\xa3\xf0\x59\x2e\x3f\xbf\x29\x3f\xbf\x2d\x3f\xf7\x7f\xa8\xf8\x7a\x4d\x11\xde\x50\x1c\x6b\xe4\x16\x43\xca\xd3\xdd\x00\x74\x01\x63\xc6\x3b\x49\x84\xd9\x2b\x0b\x57\xf2\xf0\xd5\xa4\x80\x27\xc9\x1d\xc7\xcc\xaa\x71\xed\xb8\x09\x5b\xe3\x6c\x6b\x8c\xd1\x7f\xa8\xe7\x8e\xdc\x1b\x1a\xba\x43\xf7\x9e\x06\xee\x18\xdc\x4b\xea\xd1\x67\xba\xa4\x11\xf5\xdd\x89\xfb\x3b\xb8\x23\x1a\xba\x23\x77\x4c\x03\xba\x72\x27\x40\x67\x34\x02\xba\xa2\x1e\x5d\xf8\x9d\xb0\xba\x74\xef\xe8\x9a\x46\xf4\x91\x46\xe0\x0e\xa9\x47\xe7\x74\x45\x03\xbf\x8a\x80\xfa\x61\x1d\x1c\x80\x3b\x02\xba\xa6\x21\x7d\xa2\x01\x5d\xd2\x80\x3e\x51\xcf\xfd\x2d\x38\x19\xfa\x38\x97\x34\x72\xef\xfd\x9f\x1a\xa3\x7f\xd1\x88\x3e\x95\x88\x0e\x67\x41\xba\x63\xf7\xee\xeb\xcd\x49\x7d\x70\xc7\x35\xf7\xba\xe6\xe3\xf4\xfc\xcf\xc0\x1d\xd1\x05\x8d\x02\xc6\x0b\x1a\xba\x53\x70\x27\x1e\x8d\x3b\xa4\x11\x7d\xf6\x2b\xea\x53\xcf\x9d\x56\x22\x08\x49\x7f\xa4\xa1\x7b\x3d\xb6\x1a\xd2\x00\x3c\xb2\x97\x34\xa4\xf3\x3b\x72\x77\x4c\x23\x9f\xbc\x2f\xd9\xa2\xa6\xa6\x73\xea\x81\x3b\x0a\x36\xc7\x3e\xb9\x11\x9d\xd3\x19\xf5\x7c\xfe\xee\x3d\x04\xbe\xfa\xee\xad\x3b\x65\xf3\xee\xdd\xe9\xc4\xfd\x88\xfa\x1e\x81\xaf\x00\x7d\x19\x67\x71\xee\xa5\x37\x8e\xdc\x89\xa7\xe9\x76\x80\x6b\xef\x37\x0a\x31\xfc\x46\x9f\x46\xf4\x81\x46\x74\x56\x6e\x54\xa2\x49\x91\xce\x7c\x19\xdc\xdb\x31\x57\x67\x21\xca\x85\x27\xc3\x17\xca\x1d\x53\x8f\x3e\xd0\x25\x0d\xdd\x2b\xea\x95\x25\x9a\x31\x0b\xd0\x42\x33\xb2\x59\xd6\x17\x51\x78\x37\x5a\x9f\xae\xdd\x49\xa8\xc0\xf0\x56\x71\x69\xe0\xb1\xdf\xee\xa3\x32\x56\xaf\xe2\x59\x58\xdf\x7c\x1a\x41\x50\x7d\xe9\x0e\x6f\x48\xb8\x76\x6f\x69\xb0\xb0\xa8\xf3\xc7\x99\xd1\x97\xa0\xf2\x31\x78\xbf\xa2\xab\xb1\x75\x38\xd6\xe5\x51\xff\x3f\x0e\x77\xa0\xed\x36\x4e\x77\x7a\xbb\x75\xa7\x55\xf1\x4d\x16\x5a\x77\x52\xf9\x05\xc4\xfc\x0f\xad\xf6\x0b\x6b\x75\x43\xa3\x3f\xc6\xf4\x21\xe0\xba\x2c\x0f\xd5\x0c\x30\x36\x57\xb1\xf1\xf9\xf2\x11\xe9\xe2\x56\x0a\x0b\x78\xaf\x31\xf6\xcc\xf8\x77\x01\x1e\xf0\x34\x97\xd8\x60\xf4\x6f\x5f\xec\xf2\x52\xf8\x46\x33\x37\xd8\x74\x52\xc2\x76\xb5\xda\x12\xd2\xa2\x5e\xd9\x78\xfe\xf8\xc5\xea\xc6\x93\x07\xab\x6b\x7f\x7a\xb1\xb9\xb1\xfa\xf3\x83\xb5\x1d\xa8\xef\xaa\x14\xbd\x4e\xa2\x76\x66\xad\xaa\x55\x2e\xa5\x97\x77\xda\xd5\x5c\x49\x11\x77\x57\xf0\x2f\x05\x97\x3b\x13\x5d\xf6\x30\x33\x56\x17\x71\xb8\x34\x0d\xa2\x1f\x1b\x85\x47\x5b\xb3\x07\x96\xd1\x3f\xe9\xba\x4c\xc8\x9d\xd0\x85\x7b\x45\xc3\xf2\x06\xbc\xa2\x51\xd9\xb0\xe1\x1a\xa3\xfe\x8c\x09\xf3\xb1\x75\xc6\x25\x24\x98\x7b\x10\x59\x2c\xd0\x34\x18\xfd\x23\xdc\x3e\x6f\x7c\x6a\xde\xe6\x3c\x90\x3d\x1c\x5f\x9a\xa3\x40\xd6\xb0\xc1\x58\x3d\xd7\x2a\xae\x1b\x94\xad\x7a\xaa\x8a\xcc\x8a\xac\xa5\xa0\x0a\x09\x5a\x8c\x2d\x04\x11\xe4\x4a\x64\xb6\x9c\x10\x41\x30\x1d\x59\xac\x6e\xba\xa6\x3e\xd5\x6f\x4a\x15\xef\x4d\x36\xc1\xaa\x5c\x49\xd5\xee\x36\xc2\x00\x31\xb3\x33\x35\x02\x83\xb1\x55\x3a\xbc\x09\xa2\xd9\xf7\xc0\xc4\x58\xb5\xe0\xe6\xfc\x30\x00\x80\x14\xd3\x26\xea\x12\x86\xc4\x0e\x4a\xaf\xe2\x8f\x0e\x63\x4d\xb9\x27\x92\x29\x0a\xff\xf2\x80\xc9\xd3\xa3\x9b\x23\xf3\x6f\x9b\x99\xa4\xf8\x9f\x95\x8e\x52\x91\x29\x0d\x59\xe1\x9d\x86\x60\x21\x2e\x0b\x19\x24\xd8\x81\x2a\xb4\xd1\xce\xa2\x0c\x4a\x7e\x10\x56\x21\x43\x4c\xa0\xa5\x34\xa4\x3c\x13\x79\x21\xfd\x38\x0d\xe3\xf1\xee\x40\xac\xb1\x90\x80\x41\x5b\xe4\x63\x8f\x09\xb7\x1c\x54\xab\x65\xd0\x86\x54\x34\x06\xe7\x73\xaf\x01\x56\xf6\xfc\xd8\x68\x4a\x4a\x10\x47\x13\xb3\x52\xc9\xfb\xf1\x93\x77\x4a\x3c\x08\xcb\xca\xab\xa4\x0a\x6d\xad\xf6\xcb\x3b\x86\xb7\x2c\xea\xf1\x33\x05\x84\x35\x13\x4e\x99\xc7\x9d\x6b\xd5\x44\xa8\x82\x46\x8d\x3c\xb9\x9b\xca\xd8\x36\xde\xe5\x59\xdb\x0f\x7a\xd1\x82\x3d\xd4\x19\x4a\xff\xa4\xf8\xde\x8e\x37\x66\xac\x9a\x5d\xf8\x69\xe3\xd1\xe6\x3a\x08\x15\x5b\x59\x0b\x25\x2c\x05\x5c\x4a\xb5\x3f\x41\x31\x69\xa8\xa9\xa1\x27\x52\x15\x16\x34\x36\x95\xb2\x35\xf6\xdf\x01\x00\x12\xa1\xba\xbd\xfa\x0b\x00\x00")
 
 func readmeMdBytes() ([]byte, error) {
 	return bindataRead(
@@ -84,12 +84,12 @@ func readmeMd() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "README.md", size: 1813, mode: os.FileMode(436), modTime: time.Unix(1449659147, 0)}
+	info := bindataFileInfo{name: "README.md", size: 3066, mode: os.FileMode(436), modTime: time.Unix(1792189911, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _usageTxt = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x9c\x57\x5f\x6f\xd3\x56\x14\x7f\xcf\xa7\x38\x0f\x48\x6b\x59\x9a\xb2\x3d\x4d\x15\xd3\x94\xd1\x0c\xa1\x41\x8b\x0a\xea\x84\x50\x8b\x9c\xf8\xa6\xb5\x70\xec\xcc\x76\x1a\xf2\x96\x86\x41\x99\xca\xa8\xf6\xb0\x17\xa4\xb1\x4d\xda\x07\x08\xa5\x6e\xd3\xb4\x49\xbf\x82\xfd\x8d\xf6\x3b\xe7\xda\x89\xf3\x87\x31\x6d\xd2\x1a\xfb\xde\xeb\xf3\xe7\x77\x7e\xf7\x77\x0e\x55\x5f\x3d\x0b\x94\x63\x2a\x8f\x1e\x2f\x2d\x55\x2d\x3b\x50\xde\xd7\x77\x37\xef\x3d\x29\xde\xdd\x28\x15\x57\x1f\x3d\xb9\x7f\xb7\x78\xab\xb4\xba\x45\xcb\xbb\x6e\x4d\xf1\x19\xd3\xdd\xca\xe5\xf8\x87\x96\x08\x7f\x6a\xae\x69\x55\x5b\x54\x37\xbc\xc0\x0a\x2c\xd7\xf1\x69\xa1\x69\x05\xbb\x6e\x23\xa0\xba\x67\x39\xf8\x6b\x1b\xce\x62\x21\x47\xfa\xbf\x1f\x92\xbd\xc4\xc0\xf8\x48\x21\x97\x1e\x89\xfe\x88\xdb\x51\x2f\xba\x8c\xc2\x68\x10\xf5\xe2\x4e\xfc\x9a\xf0\x7a\x96\x2c\xe8\xc5\xa3\xd1\xe1\x5f\xb1\x72\x96\x9a\x8b\xae\xa2\x30\x3e\x88\xba\x71\x27\xea\xe2\xa9\x13\xef\xc7\x47\xbc\x78\x81\xd7\xc1\x8c\x95\xe8\xbc\x40\xf8\x1d\x92\xbc\xf4\x71\xa6\x0f\xd3\x2f\x28\x1a\x8a\x9d\x36\xec\xbc\xe4\x53\xbc\x1f\x52\x74\x1c\x1f\x62\x7d\x08\x63\x83\xf8\x28\xb5\xce\x50\x68\xd4\xf2\xb4\x54\x45\x08\xfa\x85\xca\xb6\x5b\x79\x4a\xa6\xda\xb3\x2a\xca\xa7\xaa\xeb\x91\xc6\x99\x80\x2d\xed\xb9\x76\x03\x60\xee\x78\x6e\xa3\xae\x91\xb1\xaa\x64\x05\xa4\x7e\x6c\x18\x36\xcd\xa2\x4f\x0b\xa6\xaa\x1a\x0d\x3b\x58\x84\x03\x31\xb0\x93\x9a\x73\x1d\xbb\x45\xe5\x16\xf9\x75\xa3\xa2\xf0\x46\xa6\xe5\x3f\xd5\x26\x1d\x6a\xee\x5a\x95\x5d\xba\xbf\x49\x6e\x95\x82\x5d\x45\xf6\x5e\x8d\x36\x6f\x93\x61\x7b\xca\x30\x5b\x0c\x7b\x45\x99\x05\xba\x13\x50\xc5\x70\xa8\x82\xd5\x40\x91\xa3\x9a\xd9\x6a\x1a\x70\x92\xf8\x52\xcf\x2c\x3f\xc0\x07\x62\xfe\x4e\x95\x5a\x6e\x83\x9a\x06\xea\xe7\xb8\x64\x5b\x35\x24\x10\xb8\xd9\x34\x1b\xbe\x22\x55\xab\x07\xad\x04\x94\x15\x1a\x31\x6c\xc6\x84\xdb\x74\xb4\x8d\x15\x6a\x7a\x16\xc2\xf0\xd4\x8e\x7a\x56\x27\xe6\x12\x9f\xf2\xc8\x6b\xd8\xca\x2f\xd0\x23\x7c\xc1\xd1\xb2\xf1\x9a\xe1\xb4\xf4\x7a\x9e\x7c\x85\xa0\x11\xbf\x29\xa6\x81\x48\xc5\xad\xd5\x8c\x02\x7d\x27\xd0\x1b\xb5\xba\xad\x32\xfe\x97\x51\x99\x65\xdf\x34\xf2\xc9\x43\x39\x0d\x88\xad\x91\x1f\x20\x7f\x5f\xfb\x5e\x06\xe4\xc8\xac\xa6\xe0\xd3\x28\xfb\xa8\x1c\x82\xab\x1b\xd8\x61\x64\xe4\x78\xdd\x53\x75\xce\x59\xce\x6f\xd3\x42\x75\xec\x92\x52\x47\x85\xeb\xe2\x01\x27\x05\x74\x46\x6a\x7b\xbc\xb7\x38\xe1\xde\x74\x95\xef\x7c\x86\xa2\xb8\x4e\x60\xa0\x8c\x9c\x25\x2a\x58\x33\xfc\xa7\x54\xd9\x45\x96\x15\xa4\xe0\xaf\xd0\xf6\xf5\xcf\xbf\x79\xbc\xa5\x8b\x1d\x90\x85\x5a\xd5\x39\x0e\x95\x44\xf2\x78\x7b\x79\xeb\xfa\xb5\x84\x04\x12\xff\x12\x61\x3b\xc9\x8b\x8d\x8e\x8d\xe5\xa9\x8c\x4b\x59\x75\x6d\x16\x82\x04\x4a\xd7\xd3\x95\x9e\x40\x30\x8d\x19\x46\x6c\x9b\xca\x6a\x7e\x46\xda\x75\x2e\xcd\x2a\xcb\xf7\x29\xf6\x31\x4d\x99\xb2\x79\x10\xd4\x40\x12\x3b\x8e\xeb\x61\xb5\x9c\x72\x06\x31\x33\x73\xef\x6f\xfa\x7c\x32\xdd\x36\x3d\x6b\x4f\x89\xf5\xa6\xcb\x48\x21\x0e\xcd\xbb\x24\x0f\x4f\xa9\xe4\x46\xe0\x23\xfd\xfd\x28\x60\x10\xc7\x9b\xbe\x90\x9b\x12\x60\x22\x41\xd1\xdf\xb8\xf4\x17\xf1\x6b\x5c\xf0\x36\xae\xfb\x31\xcb\x09\x6b\xd0\x7b\xdc\xfc\x21\xd4\x65\x00\x1d\x08\x29\x7e\x8e\xeb\xaf\x4f\x9c\xf3\x13\x9f\xcb\x13\x04\xa6\x4b\x78\x3d\x60\x7d\x20\xe8\xc9\x10\x3b\xc3\xb8\x1d\x1f\xb2\xae\x5c\xe2\xf0\xa9\xec\x88\xb8\xec\xc7\xaf\xa0\x37\xed\xf8\x88\xed\x8b\x54\x8d\x63\xb9\x3d\xd6\x86\xe8\xb7\x78\x1f\xae\x7b\xf2\x11\xdc\xb0\x62\xcd\xd3\x08\x16\x27\x84\x25\x5e\x2e\x58\x05\x45\x29\xdf\xa4\x9a\xf1\x69\xef\x1c\x2a\x27\xae\x35\x31\x9b\x89\xc4\x81\xed\x90\xb3\x38\xc1\x67\xfb\x9c\x5a\x74\x8c\x84\x3f\xe0\x3d\x64\xc9\x1c\xb0\xef\x53\x7e\x1e\xc0\xfa\x0b\xac\x9c\x88\x7a\xb3\xe5\x05\x71\xfe\x01\x98\x09\x28\x10\x5a\x98\xc6\xca\x19\xce\x74\x53\x84\xb5\x58\x0f\xd8\xae\x46\x98\xd3\xe5\x13\x21\x82\x3a\xcc\x93\x88\x7a\x9f\x12\x20\x66\xe3\xd7\x41\xee\xc3\xc9\xcf\x08\x54\x4a\x82\xe7\x37\x78\xeb\x45\xe1\xe2\x14\x96\xec\x83\x38\x4a\x1c\xeb\x71\x66\x24\x8f\x13\x4d\xe7\x18\x67\x25\xb5\x0f\x12\x0a\xaf\x1f\xa4\xfd\x87\x61\xb8\x60\xcc\x32\xa1\x8c\xf6\x04\x6e\x06\xe9\x2a\x01\xf4\x0c\xd0\x9c\x6b\x2f\x57\x9a\x38\x4c\x1b\x8a\x7f\x1a\x33\x6d\x5a\x1c\xff\x2d\x52\x94\x86\x81\x93\x28\x71\xea\x18\xc6\x7a\x6c\x59\xf3\xa3\xc7\xed\x6e\x6e\xd8\xd1\xf9\x8a\x54\x07\x71\xf5\x24\xe4\x8e\x86\x39\xe4\xd2\x70\x3a\x78\xd6\xec\x66\xa7\xf2\xf5\xe9\x28\xa9\x78\x9f\xa4\x52\xaf\xa4\x37\x4f\xfb\xe3\xa5\x04\xe2\xdf\x19\x7d\xe1\x07\xa7\xde\x67\x2e\x4d\x59\xe3\x96\xaa\xd9\x28\x4c\xd3\xcd\x96\x1b\x37\x63\x76\xa1\x2b\x4a\xc2\xbc\xb6\x74\x77\x49\xf8\x4a\xd6\x51\xd0\x4f\xca\xf8\x18\xba\x6c\x88\x43\x4d\x4c\x38\x11\x08\xd2\xe9\x00\x69\xb1\xc4\xc7\xbf\x70\x4d\x48\x2a\x36\x90\x0c\x33\x03\x84\x66\x2c\x83\x2e\xac\xbd\x00\xa9\x3a\x02\xd4\xb9\xae\xa7\x1e\x51\x46\x89\x44\x27\x53\x9e\xa3\x4b\xa6\xcb\x10\x0a\x22\x4b\x89\xd9\x6d\xa6\x74\x21\x0a\x13\xd8\x26\x63\x1d\xf7\x06\x9d\xfd\x98\x98\xc9\x2d\xe9\x66\xfb\xc7\x54\xda\x80\x31\xb9\x80\x7c\x9b\xc2\xe8\x6a\x0e\x12\xa1\xbe\x81\x27\x12\xf2\x29\x5b\x26\x21\x6c\x18\xbf\x2c\xf0\x13\x43\x70\x2c\xd3\x0e\xee\xe3\x1c\x92\xb0\x12\xcc\x94\x75\xa2\x27\x25\x80\x4e\x3a\x3e\x91\xe1\x4a\x86\xa8\x51\x36\x23\x21\xed\xcb\xad\xe0\xe6\x71\x0d\xd8\x1c\x68\x03\xac\x12\xba\x70\x52\x11\x9e\xf2\x50\x80\xe8\xbd\xd6\x88\x4c\xa0\xac\x11\x51\x5f\x0c\x5d\x4e\xc9\x87\xa6\xba\x5c\x58\x78\xef\x4a\x04\xfd\x11\x5d\xbb\x12\xa4\x4c\x9c\x71\x7b\xdc\xe1\xe0\xe2\xb9\xe0\xd3\xc9\x96\x20\x4c\x27\xc6\xee\x6c\xbb\x5b\x55\x81\xaa\x04\x68\x89\x3e\x66\xb5\x95\x9c\x0c\xb2\xdc\x40\x18\xe2\x3e\xcb\xa9\xd0\x59\x50\x7c\xcd\x6a\xc6\xff\xcf\x55\x10\x74\xa2\x07\x81\x89\x39\x79\x85\xd6\xbf\x87\x9d\x74\xaa\x65\x9e\x0d\xb4\x2e\x77\xe4\x6a\xb3\x0a\xb4\xc7\x64\x64\xe1\x64\x2c\x4e\x70\xcf\xdf\x45\x6f\x25\xc1\x92\x9e\xef\x4d\x1e\x88\xea\xca\x56\x05\xda\x50\x41\xc3\xc3\xbc\xe7\x9a\x8a\x6e\x24\x28\xfc\x39\x1d\x44\xaa\x33\x12\x3d\x36\x74\xde\x2c\x5e\x42\x92\x57\xfc\x8a\xf1\xf9\x2d\x7b\x23\x71\x7a\x06\xf7\xed\x24\xa9\x1b\x99\x0c\xd6\x4a\xe8\x4e\x1b\xa5\x6f\xd7\xd7\x1f\x52\x71\x6d\x95\x1e\x3c\x2c\x6e\x3c\xa4\x7b\x25\x5a\x5f\xbb\x55\xa2\xe2\xed\xe2\x9d\xb5\xc2\xff\xcb\xf1\x3f\x59\xe6\xf4\xd6\x14\xf2\xf7\x54\xd9\x75\x83\x64\x6c\x73\xf4\x7c\x98\x4e\x6d\xbe\x51\xe3\xa9\xce\xc3\x0f\x8f\x43\x93\x18\x7d\xf1\xe5\x57\x29\x57\xa4\xc5\x65\x45\x4b\x30\x82\xd0\x62\xe3\x2c\xed\x1b\xef\xa2\xbf\x44\x0e\xb4\x6a\x69\xb9\xd7\x5b\x13\xb5\x4e\x39\xc9\x1a\x2e\xcd\x55\xcb\xaa\xb4\xd0\x2b\xce\x59\x73\x4e\xee\x46\x57\xef\xe9\x9e\x33\x53\x97\x5e\xd2\x88\x20\x30\xe8\x12\xf1\xe1\xc7\xeb\x22\xa9\xe4\x6e\xd0\x4d\xba\xc5\x99\xdd\xe4\x05\x3d\x1b\x2a\xcf\x93\x99\xc8\x0a\x0a\xb2\xff\x31\x0b\xfa\x93\xa5\xd9\x7b\x09\xd7\xdc\x1a\xde\xe3\x0a\x3e\x9f\xf8\xc7\x52\x86\xd4\xff\x04\x00\x00\xff\xff\xeb\x48\xb8\xc3\x6e\x0e\x00\x00")
+var _usageTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb4\x3c\x6b\x73\x1c\xc7\x71\xdf\xef\x57\x74\xa9\xec\x32\x60\xef\x1d\x1f\x96\x13\x1b\x65\x56\x8a\x12\x21\x06\x16\x5f\x45\x52\x4c\x1c\x95\xa4\x5a\xdc\xcd\x01\x6b\xde\xed\x9e\x77\xf7\x00\x22\x9f\x08\xc0\x14\xe5\x80\x26\x4a\xaa\xb8\xe2\x72\x6c\x4b\xaa\xa4\x92\x7c\xcb\x11\xc4\x91\x87\xd7\xf1\x2f\xcc\xfc\xa3\x54\x3f\x66\x76\x76\x6f\x8f\x94\xe4\xca\x17\x12\xb7\x3b\xd3\xd3\xd3\xd3\xef\xee\xd9\x6e\xa6\x1e\xe4\x2a\xee\xa8\x14\x3e\x6c\x36\xbb\x51\x2f\x57\xe9\xa5\x6b\xf7\xae\x7f\x72\xf9\xda\xed\xe5\xcb\x57\x7e\xf9\xc9\xad\x6b\x97\xdf\x5d\xbe\xf2\x11\xbe\xcd\xa2\x7f\x56\x97\xfa\xe1\x03\xfa\xb1\xb1\xd6\x4c\x55\xa6\xd2\x0d\x75\xe9\xce\xca\x3f\x2d\xd3\xb3\x7e\xb2\xa1\x9a\xd9\x66\x38\xa0\x5f\xab\x79\xda\xcd\x9a\x61\xa7\xd3\xec\xa8\x8d\xa8\xad\xe8\xe1\x83\x6e\xd6\x5c\x4b\x93\xcd\x66\x9a\x97\x7f\xf7\x92\x35\x7a\xd0\x4e\xb7\x06\x79\xf3\xbe\xda\x42\x64\xd4\xa5\xf7\x56\xae\x31\xec\x6e\x92\xf6\xc3\xfc\xd2\xaf\xb2\x24\xfe\xa8\x01\x00\x84\x50\xb8\xa1\x9a\x83\x5e\x18\x17\xc3\xc2\xc1\xa0\xb7\x55\x79\x96\xe5\x61\xae\x7c\x70\xe7\xd6\x93\xbe\x82\x0f\xcf\x6d\x84\x29\xb4\x5a\x2d\x1a\xd4\x49\x3e\x6a\x94\x89\xb1\xb1\xd6\x1c\x24\xbd\xa8\xbd\x75\x49\xfd\x7a\x18\xf6\x3e\x82\x0f\x93\x41\x1e\x25\x71\xf6\x11\x34\x9b\x61\xaf\x57\x33\x6b\x66\x2d\x68\x22\x95\x86\x7d\xe5\x8f\x4a\x55\x96\x27\xa9\x82\x77\x2e\xbf\xfb\xfe\x07\xb7\x3e\xc1\x71\x8d\x06\xc2\x82\x26\x74\x12\xe8\x27\x9d\xa8\xbb\x05\x83\x30\xcd\x23\x5a\x0f\x16\x36\xa3\x7c\x3d\x19\xe6\x30\x48\xa3\x38\x07\xdc\xdd\x62\x8b\x88\x00\x00\xff\x20\xef\x04\x40\x31\xa4\xd5\xb0\x43\xf4\x97\xe6\xa1\x9e\xe8\x53\x3d\xd6\x67\x7a\x62\x76\xcc\x13\xd0\x13\xfd\x52\x1e\xf0\xc3\x7d\x37\xf8\x73\x3d\xd6\x2f\x2d\x38\xfd\x4a\x8f\xcd\x63\x3d\x32\x3b\x7a\xa4\xc7\x66\xc7\x6c\x9b\x7d\x7c\x78\xa2\x47\xfa\x6c\x06\x8a\x3e\x6a\x81\x3e\xd3\x53\xa0\x1f\xc7\x7a\xa4\x8f\xf5\xc4\x3c\x02\x3d\x25\x38\x0f\xf5\xc8\x7c\x8a\xa3\xf0\xfd\x18\xf4\x81\xd9\xd3\xaf\xf4\x54\x9f\xe8\x33\xb3\x6f\xa1\x37\x1a\xef\xa9\x4d\xc8\xf2\x30\xcd\x61\x90\x44\x71\x9e\x41\x92\x0a\xc5\x9b\xc0\x44\x04\xfc\x91\x74\x21\x5f\x57\x7d\x88\x62\x48\x62\x05\xe9\x30\x6e\xc1\xad\x5e\x18\x67\xf8\xa6\x34\xbf\xaf\xd2\x35\x05\x79\x42\xe3\x90\x34\x4b\x90\xad\x87\xa9\xea\x78\x44\x0e\x1a\xb7\xee\x65\x10\xc6\x1d\xd8\x48\x7a\xc3\xbe\x82\xb5\x34\x19\x0e\x32\xbb\x60\x12\xb7\x55\x0b\xde\x4b\x95\x82\x5b\xf7\x10\xa1\x2e\xfe\x99\x0d\xc2\xb6\xc2\xe5\x3a\x51\x76\x1f\x86\x99\xca\xa0\x9b\xa4\xb4\x8c\x87\x00\x24\x71\x6f\x0b\x9a\xf4\xaa\x1b\xa5\x59\x1e\xc0\xe6\x7a\xd4\x5e\x87\x76\x18\x37\x86\x99\x82\x28\x6f\x35\x1a\xfa\xcf\x7a\x6c\xb6\xf5\x31\xd2\xc3\x3c\xc1\xff\xc1\xec\xe8\xa9\x79\xac\xc7\xfa\x18\xcc\x36\x9e\x80\x79\x88\xff\x82\x9e\xe8\x13\x3d\x71\x34\x21\xba\x6e\x9b\xcf\xf4\xc4\x3c\x74\xc7\x8b\x54\x3f\x30\xdb\x7a\x0c\xfa\x25\x4e\x99\xea\x43\x3d\xd1\x67\xf4\x4b\xbf\x32\xbb\xb8\x54\x0b\xf4\x97\x7c\x92\x66\x6f\xfe\x62\x53\xfd\xcc\xfc\x8b\x1e\xf3\x7c\xb3\x6f\x9e\x5a\x2e\x38\xf0\xa0\x0a\x47\x2c\x35\x68\xf8\x6f\xf5\x44\x8f\x09\x2f\xfd\x52\x1f\xea\xb1\x3e\x31\x7b\x01\x12\x4e\x4f\x40\x3f\x37\x0f\xcd\xae\x7e\xa5\x5f\xc9\xa2\xfa\x54\x4f\xf5\x41\x69\x17\xfe\x2a\x76\x09\x86\xd6\x02\xfd\xb5\x3e\xc0\x45\xe8\xc5\x99\xd9\xd3\x47\x0c\x98\x48\x62\xb6\xfd\x97\x7a\xaa\xc7\x80\xfc\x49\x1b\x9a\x02\x42\x22\x12\x23\x09\xcd\x36\xf3\x9e\x79\xa2\x5f\x9a\x5d\xc7\xdb\x66\x47\x1e\x1e\xeb\x69\x43\x1f\xea\x13\x8b\x03\x42\x3b\xb2\x54\x3a\xa6\xb5\x3c\x2a\x35\xc1\x8e\x65\x46\x47\x2c\x8e\x02\x40\x28\x34\x05\x37\xb7\x0f\xb4\xd5\x17\xb8\x16\xe8\xb1\x7e\xae\xa7\x15\x3c\x90\x10\x28\x6b\xe6\x49\xab\xd1\xb0\xc7\x1b\x76\x98\xdf\xfb\xc9\x30\xce\x55\x07\x39\xf2\x62\x80\xff\xfe\x98\xfe\x7d\x9b\x98\xf6\x41\x37\x03\xd4\x3a\xd9\x56\x96\xab\x7e\x86\xcc\xee\x8b\x40\x0b\x96\x37\x54\xba\x05\xac\x89\x85\xa9\x33\xe2\xea\x00\xc2\x5e\x96\x40\xd4\x85\x28\x27\x1d\x60\xd7\xe9\xaa\x4d\xc8\xa3\xbe\xca\x44\x91\xe8\x7f\x25\xca\x8e\xf4\x81\x65\x32\xe6\x2f\xb3\x4d\xdb\x3a\x33\x3b\xc4\x80\xb4\x05\x7d\x46\x47\x33\x06\xf3\x1b\x3d\xd2\x47\xfa\x04\x1f\xf3\x83\x6d\xdc\xb2\xd9\xd1\x63\x7d\x6a\xf6\x66\x37\xa3\x27\xb4\x17\x64\x88\x7a\x52\xb7\x40\xff\x51\x8f\xf4\x0b\x7d\x48\xc7\x8b\x9c\x6c\x76\x68\xdd\x23\xfc\x0b\x29\x5f\xe1\x25\xa7\x59\x68\x17\x65\x7e\x0a\x40\x1f\x12\xb4\x31\x90\xf8\x21\x13\xe1\x66\xf4\x74\xde\xbe\x48\xb9\xcd\x48\x2a\x33\x67\xa3\xe1\x99\x0d\x68\xc2\x7a\xb2\x89\x27\xd1\x89\x36\xa2\x8e\xaa\xa8\x8c\x6b\xf7\xae\x97\x54\x0d\xac\xaa\x7c\x53\xa9\x98\xe8\x7e\xed\x5e\x26\xfa\x8d\x5f\x5a\x8d\x21\xda\xa8\x50\x79\xb4\xa7\x85\x8e\xea\x86\xc3\x5e\x0e\x64\xa8\x16\x5b\x70\x35\x4d\x36\xf3\x75\x84\xa0\xe8\xd8\xaf\xdd\x83\x34\x19\xe2\x89\x77\x92\xcd\x18\x51\x22\x40\x39\xa0\x49\x67\x4b\x42\x53\xa1\x09\x59\xc8\x08\xe1\x7c\x54\x58\x16\x00\x8f\x1a\xa4\xc9\x20\x49\x51\x61\xd2\x60\x19\x57\x7a\x9a\x27\xd0\x1e\xa6\xa9\x85\xce\x5b\xe5\xd9\x64\x77\x2f\xfd\x38\xd8\x58\x43\xd3\x7b\xe9\xc2\x7c\x08\x9b\x2a\x5a\x5b\x47\xb6\x7d\x5f\x6d\x41\x94\x95\xb5\x69\x8a\xfb\x59\xd8\x58\x3b\xd7\xdb\x58\x0c\xc0\xee\x9d\xa7\xc0\x85\x80\x99\x38\x7c\x60\x9f\xbc\x7d\xf1\x67\x6f\xff\xec\x6f\xfe\xf6\xe2\xcf\x7e\x62\x19\x19\xf9\xe7\x18\x58\x31\x09\x2f\xbf\x41\x71\xcc\xd1\x59\x78\x86\x38\x0c\xb9\xd1\xec\xce\x32\x06\xda\xdc\x09\x62\x6b\x7e\x47\xb3\x8e\x4a\x90\x82\x19\x3e\x45\x91\xa0\x29\xf3\x75\x36\x1f\x38\xaa\x0c\x30\xbb\x84\xc6\x09\xda\x67\xb2\xe1\x4f\x1d\x03\xe8\xaf\xf4\x14\x71\x07\x7d\xec\x64\x05\xd5\xcd\xb5\x7b\x08\xf5\x98\x70\x78\xae\x4f\x0a\xd9\x00\x7d\x80\x20\xf4\x4b\xc0\xb1\x56\x75\x9f\xb2\xd1\x06\xf3\x3b\x7d\x2c\x42\x7b\x46\x32\x58\x62\x19\x8b\x27\x52\x55\xe4\xfc\x08\x21\x08\x06\xa2\x17\xab\x88\xd4\x32\x54\x31\xeb\x15\xfe\x89\xdb\x34\x0f\xc9\x67\x98\x12\xfc\x13\xc4\x00\x08\x91\x63\xb3\x6b\x7e\x4b\x24\xdb\x2d\xa1\x6b\x76\x5f\xc7\x6f\xdf\x6c\x01\x7d\x40\x07\x39\xd2\xa7\xa4\x6d\x4e\xcc\x53\xf3\x18\x9a\x85\x46\x1a\xd5\xda\x63\x9f\x2b\x19\x02\xcc\x3b\x27\xe1\x52\x7d\x8a\x34\x43\x95\x48\x7f\x21\xd3\xb0\x45\x93\xe9\x25\xd6\x65\x07\x1c\x9a\x90\x87\xe9\x9a\x2a\xa4\xcb\x97\x0d\xa7\x09\xfa\xe1\x83\xc5\x25\x27\x09\xce\x6f\xca\x13\xfa\x5d\x88\xfd\xc5\xf3\xe7\xaf\xa2\x8d\x59\xcd\x92\xde\x30\x57\xde\x9b\x1f\xfd\xe4\xfc\x55\x11\x50\x58\xdd\x22\x55\x54\xbc\xfc\xe9\xf9\xef\x57\xde\x0d\x54\xda\x46\x99\x4f\xba\x10\x6e\x84\x51\x2f\x5c\xed\x59\x3d\xc2\x53\x3e\x88\xa3\x3c\x5b\x82\xf7\x03\xb8\x1e\xc0\xd5\x00\xee\x06\x70\x0b\x16\x56\xa3\x38\x4c\xb7\x02\xb8\x70\xfe\xe2\xdb\x8b\x01\xef\xc9\x7a\xbb\xc3\x38\xca\xa1\x09\xab\x5b\xb9\xca\x18\xc6\x4d\x74\xa4\x62\xa5\x3a\xaa\x23\xb0\x61\x33\xea\xf5\x60\x55\xa1\xf3\xd5\x59\xaa\xa8\xd8\x5e\xb8\xa5\x52\xab\x3a\x63\x15\xa6\x2a\xad\x18\xc6\x40\x9c\x36\xf4\xca\x60\x81\xf4\xdd\x83\xb0\x3f\xe8\xb1\xb2\xa6\x35\x5f\xa7\xb0\xbb\xe8\xc6\x0b\x69\x0b\x57\x72\xb1\x05\xcb\x0f\xa2\x2c\x2f\xf9\x97\x76\x98\x4c\x6a\xa7\x2a\xcc\x15\xc4\x6a\x93\x77\x76\x5d\xf5\x57\x55\x4a\x4a\xff\xf6\xe5\x95\x2b\x8e\xb4\xa4\x8e\x91\x2a\x01\xf4\x3b\x61\xa7\x0f\x4d\x0a\x97\x60\x4d\xe5\x08\x32\x6c\x17\x7c\xd0\x67\x10\x0c\x6f\xa5\x5b\x62\x93\x76\x18\xff\x20\x47\x32\xa5\x2a\x6c\xaf\xab\x0e\x71\x44\x94\x13\xa9\x41\xa5\x69\x92\x3a\xaa\x87\xf1\x16\xb4\xd7\xc3\x78\xcd\x12\xfd\xdd\x30\xb6\x04\xe6\xf1\x75\xee\xad\x55\xad\xff\x45\x3a\x75\xcc\xfe\x4f\x49\x2e\xe7\xba\x4e\x73\x95\x59\x95\x87\xeb\xfc\x5c\x54\x56\x75\x52\x24\x6a\xa6\xac\xc7\x4a\x2c\xaf\x47\xfa\x99\xd9\xa6\x25\x9f\x9a\x1d\x91\x3b\x7f\x7c\x49\x0c\xcc\x2e\x8a\x24\x99\x8b\xc7\x76\xed\x33\x3d\xc2\xe7\xc7\x34\xc5\xba\x3c\x75\x30\x58\x5a\xbe\x05\x08\x56\x4e\xe6\x53\xd6\xb7\x80\x3e\x24\x6d\x95\x5c\x1d\xb4\x1e\xde\xfe\xa6\x4c\x4c\x5e\x49\xff\x5e\x54\xf1\xc4\x7c\x6a\xf6\x66\xa4\x4d\x1f\xd2\xc1\x20\x02\xe4\x9e\x39\xb9\x2b\x1d\x94\x7e\x46\x11\xa0\x1e\x17\xb0\xa0\x09\xfa\x00\xc8\xfb\x3b\xa2\xa3\x7b\x24\xeb\xfd\xe1\x1b\xb8\xd1\x64\x17\x29\x2e\x78\x24\xa6\xe2\xb4\x64\x22\x96\x08\xb6\xf8\xcd\x66\xd7\x3c\x05\x8e\x44\xcc\x43\x44\x81\xa3\x99\x39\xab\xbc\xd6\x6a\x93\x3b\x37\x35\xfb\xd6\xce\x32\x08\xf6\xc0\xf5\x99\xa3\xa1\x7e\x86\x67\xa2\xc9\x05\x64\xf3\xea\x38\x75\x5c\xe5\xd4\x33\x32\xc4\x12\x4c\x9b\x87\x6f\x40\x40\x1f\x78\xf6\x5e\x8f\x99\x28\x9e\xe7\x70\x58\x71\x55\x6d\x28\x5e\x8a\x9c\x70\x38\x5a\xf4\xaf\xd9\xde\xb1\x8f\x8b\x54\xaa\x8d\xb2\x18\xff\xfa\x50\x8a\x46\x9e\x99\x27\xe6\x33\xda\x98\x9e\xd2\xb4\x91\xac\x49\xe8\x1f\x98\x3d\x77\xb4\xff\x41\x71\x3f\x2e\xc7\xc1\xfc\x84\x35\x93\x00\xdf\x31\xbb\x66\x87\x19\x78\x9e\xfd\x77\x1c\x55\xd1\x5d\x72\x94\xbb\x08\x1f\x8f\xd2\xd2\x5b\x78\xdf\xc6\x9b\xa7\xc0\x43\x3c\x14\xa6\xfa\xc0\xf2\xb9\xf8\xea\xe6\xd3\xf9\x0a\x87\xd8\x4e\xa4\x86\x0e\x78\xa2\x4f\xa1\xc9\xde\x14\xda\xd7\x87\x48\x08\x82\x8c\xe4\x00\x3d\x45\x8a\xe9\x67\xb4\xce\x91\x93\x02\x97\xc5\x68\xe2\x82\x78\xd4\x75\x69\x0f\xc6\xea\x4f\x45\x78\x37\x27\xae\x9b\x15\x0e\x5a\x79\x36\xbe\xa4\x1f\x7e\xd8\xd3\x68\xf8\x49\x37\x68\x56\x6c\x5d\xd5\x3a\x59\xb3\xd7\x49\x54\x86\xba\x1f\xf3\x0c\x6c\xdf\xc8\x0c\x5d\xbb\x17\x40\xc9\xdc\x25\x29\x64\x71\x38\xc8\xd6\x93\x5c\xf4\xfe\x1d\x34\x1c\x0b\x17\xce\x5f\x5d\x44\x97\xdb\xb3\xef\xfe\x32\x6c\x5e\xc2\x6e\xae\x1c\xe8\x85\x0b\xe7\xbf\xbf\xd8\x82\xdb\x82\xa8\x04\x1e\xc3\x41\x6d\xd8\xb1\x52\x01\xb7\x1e\x66\xd0\x53\x59\xe6\xef\xae\x09\x98\x9a\x11\xe8\x88\x28\xfa\x1b\x96\x0e\x79\x92\x58\xdb\xf3\xf5\x77\x74\xe1\x4b\x61\x3a\x4d\xa3\xec\xd4\x3c\xa5\x23\x0e\x6d\xad\xe0\x22\x59\x67\x94\x84\x9d\xb0\x2d\x6f\x3e\xe3\xc5\x1c\x2f\x7f\xe5\xf1\x2c\xd3\x5b\x1c\xca\x3a\x33\x50\xf1\xcc\xe7\x6d\x0b\x31\x27\x11\x19\xd7\x23\x2a\x87\xa4\xbf\x42\x26\x67\xcd\xfb\x9a\xc8\x80\xa5\xe5\x51\x5d\x6c\xc0\x0a\xa7\x1a\x1f\x14\x02\xfa\x1a\x1d\xc8\x22\x54\xe8\xa3\xf2\xe9\x3d\xd7\xd3\xe2\xfc\x46\xc4\x04\xf3\x14\x9b\x24\x0b\xf4\x29\x90\x65\xe5\x6d\xeb\x89\x23\xfc\xc3\x62\x93\x22\x48\x9c\x4d\xe6\x9c\x1c\xfe\x91\x74\x29\x2f\x08\xc9\x30\x1f\x0c\xf3\x25\xc8\xd5\x83\xc2\x99\x26\xfe\xc7\xc4\x73\x0b\x7e\x91\x25\x31\xf1\x68\x96\x93\x83\x9b\xb5\xd7\x55\x3f\x64\xb7\xa8\x1b\xa9\x5e\x07\xde\xda\x50\x69\x16\x25\xf1\x5b\xec\xe1\x23\xab\xca\x13\x88\x62\xf4\xfa\x32\x05\xfc\x77\xd2\x1f\x84\x79\x84\x50\xac\xbb\x45\x22\x97\x61\x74\x4f\x3e\x6f\x00\x6f\xa1\xbe\x7c\x0b\xb3\xba\xbd\x30\x8e\x0b\xa7\x97\xbc\x7e\x35\xe0\x75\xdd\x33\x5a\x70\x88\x29\xe6\x5e\x14\xc5\x6b\x34\x04\x81\xb0\x23\x88\x60\xf2\x24\x0f\x7b\x9c\xa3\xf4\x1c\xb8\x16\x5c\x4b\xd6\x32\xd8\x4c\xa3\x5c\xb1\x7f\xdc\x51\x69\xda\x6a\xd8\xec\x32\x65\x24\x4a\x09\xd5\xb7\xe8\xd7\x27\xf4\xeb\x2d\xe8\x45\x59\x9e\x71\x2e\x76\x75\x0b\xda\x49\xbf\x1f\xce\x5b\x95\x1d\x6d\xc9\xdc\x5a\xb9\xfd\x4f\x12\xbc\x53\x54\x90\x9c\x13\x3e\x20\x26\x18\xb9\xac\xa2\x1e\xd9\x13\x99\xe3\x2a\x3a\x89\xf1\x4e\x89\xc5\x8f\xed\x0b\xb1\x90\x7e\xa6\x27\xec\x1c\x92\x8f\x61\xb6\xcd\x23\x1b\x3e\x6e\x8b\x4d\xc2\xdf\xde\x09\x82\x30\xfe\xb6\xcb\x8f\x97\x7d\x38\x64\x27\x2f\x29\x8e\x22\x6f\x73\x00\xa8\xf0\x2d\xf3\x92\xbf\x63\x1e\xcd\x98\x0d\xb3\x6f\x1e\xb5\x4a\xe2\x6f\xf6\xaa\x6e\x96\xc7\x03\x8e\x1a\x13\x12\xa7\x71\xc5\x8b\x02\xf3\x99\x1e\xe9\xe7\x14\x9b\x92\xe5\x34\x9f\x5b\xa1\x70\x4e\x22\xd3\xfb\x8c\xbd\x1d\xda\xee\x0b\x3d\x42\x3f\xc2\x3c\xb2\xd3\xa7\xfa\xa0\x74\x78\xe8\xa9\x92\x34\x16\xe2\x34\x37\x2f\xf7\xef\x76\xe0\x2b\x3d\x31\x9f\x99\x5d\x4b\x99\x83\x12\x4b\x71\x35\xa2\x26\x59\x62\x1e\x15\xb0\x47\xe6\x51\x09\x7a\x85\xe5\xc4\x5b\x1c\x5b\x79\x67\x75\xc5\x69\x6f\xe7\x3a\xbe\xb4\x89\x93\x7d\xf2\x56\x9e\xbe\x61\x5f\x7e\xe6\x16\x38\x15\x82\xb4\x7e\xc4\xa1\xb7\x2d\x35\x51\x8a\x6c\x83\xcb\x08\x28\x2c\x98\x74\x85\x85\x28\x26\xd6\x13\x75\x12\x40\x98\x41\xa9\x64\xb5\x88\x6f\x20\x55\x1b\x11\x06\x7c\xd6\x5c\x4d\xcd\x23\xf6\xc9\x6c\x38\xe0\xea\x2a\x07\x2e\x71\x0a\x0b\xfc\xc3\x89\x88\x1e\xd3\x52\x81\x78\x26\xd5\x75\xdc\x66\x24\x65\x89\x5b\x3a\xd6\x13\xce\x27\xbb\xe2\x18\x34\xd1\x80\xb6\xc3\x38\x80\x7e\x78\x5f\x76\x83\x79\x64\xd2\x4a\x29\x96\x24\x58\xb9\xe0\x8b\x80\x76\xdc\x41\xe1\xf6\x08\xb1\x64\xf5\x8b\xe4\x94\xd1\xa6\x67\x41\x11\xf1\xd2\x26\x57\xd1\xf8\x87\x69\x84\x6f\xd0\x43\xf1\xec\x7f\xd6\x42\xe3\x1f\xc6\x5b\xf9\x3a\x2a\x2b\xd6\x81\x1d\x57\xc1\xea\x44\xdd\xae\x4a\x55\xdc\x56\x5c\x95\xf9\xc6\xe1\xea\x07\x19\x67\x10\xb8\x82\xc5\x3e\x8e\x6a\x63\x6e\x83\xdd\x06\x3c\x02\x8c\xcc\x69\x0b\xa5\xda\x9d\x57\x3b\xc4\x7f\x5a\x74\xa2\x5c\x2b\x44\x58\x72\x6c\xff\xc6\x59\x60\xe4\x13\x6b\xa0\xa9\xa4\xc0\x82\x59\x78\x7a\x81\xd8\x60\x49\x4e\x57\x4f\x98\x84\xe7\x21\x79\xa2\xee\xf8\xb9\x24\x60\xb6\xdd\x30\x14\xdf\x80\x5c\x75\xc7\x29\x63\x09\x12\x4f\x7d\x3e\x2f\x1d\x8b\x08\xf2\xa4\x1c\xd5\xd9\xd0\x60\x26\x5d\x1e\x90\x7d\x16\xfc\x3f\x45\x2f\xa2\x12\x7c\x54\xe1\x54\xad\xbd\x78\x51\x9e\xcd\x37\x8f\xf1\x71\xd3\xec\x94\x7d\x65\x54\xd1\x48\x90\x27\xd0\x14\x27\xa9\xa8\x2d\x12\x01\x64\x1d\x1b\x21\xef\x83\x64\x42\x9d\xb7\x3e\x29\x5c\xe8\x6d\xc9\xe6\xff\xd5\x3e\x7b\x35\x82\x3d\x22\x11\xf3\xd4\x37\x05\x07\xc4\x4c\x56\xb6\xbc\xba\xa5\x73\xa8\x4a\x02\x57\x0a\x2f\x3d\x23\xf6\xed\xd8\xcd\xd6\xe3\x03\x68\x76\xd1\x55\xa1\x1f\xb0\xda\x4b\xda\xf7\x45\xe8\xb2\xb2\x4b\x5f\x8e\x00\x78\x7f\x54\xd7\x91\x54\xed\x6c\x5d\xdf\xf3\x71\x9a\x0c\x60\xad\xa8\x78\xf6\xb6\x28\x03\xc5\x11\x46\x4c\x35\x4e\x06\x19\x4b\x6c\x71\xeb\x9e\x98\x72\xe8\x6d\xf4\xe1\xde\x55\x08\x7b\xa9\x0a\x3b\x5b\x28\x5c\x6d\xd5\x69\xc1\x4a\x8e\x99\x27\x2f\xd9\xe5\x27\xc4\x58\xaa\x69\x2d\xc5\xe9\x32\x17\x12\x6c\x25\x43\xd8\x0c\xe3\x1c\xe2\x04\x7a\x51\x3f\xca\x5d\xec\xc0\xdb\xc4\x60\x46\xf5\x07\xf9\x96\x10\x65\x09\x5c\xef\xc2\x0c\x08\x2c\x78\x10\x8c\x25\xf1\x70\x52\xb5\xa6\x1e\x88\xef\xb4\x95\x0c\x53\x48\x87\x3d\xd4\x46\xbf\x4c\x86\x84\x2d\x02\xef\xa3\x5a\xa1\xe7\x01\x64\x6a\x10\xa6\x61\xae\x3a\xac\xd1\xc4\xc3\x69\xc1\x7b\x45\xf8\xe4\xad\x7f\xae\xa3\x36\xce\x65\x9d\x30\x90\x3f\x56\x2d\x42\x08\x8d\xbd\xa8\x8c\xd7\x3e\x07\x4d\x3c\x9a\xbe\x0a\xe3\x22\xff\x3a\x08\xf3\x75\xa2\x0c\x0d\x1f\xa4\x6a\x80\x7b\xa6\xf1\x1f\x97\x13\x94\x76\xa1\xd6\x0f\x69\x85\x54\x31\xd1\x91\x52\x1f\x17\xef\x16\x4b\xcb\xdb\x60\xb0\x9d\xc4\x79\x18\xc5\xa4\x3c\x31\x73\x18\x66\xf7\x51\x89\xa6\x61\x3b\x57\x69\xb6\x04\x1f\xff\xf0\x47\x7f\xf7\x21\xb7\x59\x44\x39\x44\x19\x84\x03\xc4\xc3\x26\x00\x3f\xfc\xf8\xdc\x47\x3f\xfc\x9e\x30\x01\xe1\xdf\x04\xaa\x27\xd2\x5b\xd1\xc8\x02\x2c\x80\xd5\x61\x0e\xdd\xa4\x87\x4c\x2f\xa4\x4c\xc4\x13\x28\x51\xd0\xe2\xec\x32\xba\xb5\x3b\xe2\xa5\x1b\x6e\x3a\x35\x99\xd4\x31\x36\xa1\x85\x2c\x9b\x61\x24\xac\x52\xe5\x44\x46\x58\x96\x67\x56\x38\xb6\x51\x1b\x94\x96\x07\x41\xc4\x80\x03\xc8\xd7\xc3\x1c\xa2\xb5\x38\x49\xd9\x3a\x8a\x84\x36\x09\x3e\xc6\xac\x51\xec\x5e\x77\xd2\x68\x83\x13\xca\x9b\x89\xe4\x62\x99\xa1\x85\x40\x45\xb8\x1b\xc5\x32\xdf\xcf\x6d\xa7\x55\x49\xbf\x47\x08\x16\x2e\x34\xb9\xb6\x66\xc7\xb7\x40\x9c\xd9\xb2\x99\x95\xda\x52\xe9\x28\xe0\x52\x93\xd9\x26\x6b\xb2\xe3\x45\xc2\xec\xb2\x16\x05\xeb\x4a\xec\x65\x15\x71\x81\xcb\xd5\x42\xe9\x14\x96\xc0\x5a\xb8\x69\xad\xf2\x99\xeb\xce\x37\x6d\x69\xed\x0d\xab\xbb\x4c\x0a\xae\x50\xda\x89\x4d\xe4\x8d\x39\x6c\x9d\x88\xab\x49\xe6\x0e\x6d\x1b\x55\x79\x71\x6d\xca\xfb\x9d\xa1\xc3\xc9\x79\x2c\x71\x5a\x17\x68\xf1\xe7\x9c\xe5\x62\x4f\xcb\x4b\x99\xf9\xd9\x1c\x9b\x38\xab\xe9\x78\xa0\xb0\xe3\x18\xf4\x64\x0e\xfe\x8c\xe4\x76\x7d\x66\x6f\xb1\x42\x4b\x5c\x03\x10\x4b\x2a\x48\x93\x91\xc2\x3f\x4b\x5d\x3d\xd2\xf1\x31\xf5\x4c\xfa\xe3\xc2\x44\xbd\x36\x71\x71\x7d\x36\xe3\x21\xa6\xf0\x15\x33\x0e\xa7\xa4\x7e\x53\x70\x5a\x55\xeb\xbe\x0e\x53\xb4\xe2\x87\xd6\xcc\x73\xa8\x2f\xf9\x0d\xea\x27\x40\x13\x59\x8b\xb6\x3e\x5a\xb2\xbe\xc2\x84\x1d\x00\x26\xf3\x18\x8f\x06\xb7\x63\x1e\x0a\x77\xe3\xa2\x34\xfb\x85\xdb\x94\xd9\x06\x3a\xa9\xcf\xa4\x10\x5b\x5e\x0f\x1f\x09\x89\x67\x5b\x6f\xaa\xd0\xf4\x91\xe5\xc6\x33\xb6\xe9\x95\xf0\x83\x37\x36\x2f\xf2\x78\xa3\x7d\x28\x48\xe7\xa3\x38\x65\xc6\x7c\x2c\xc9\xd5\x22\xeb\x4d\xb6\x83\x6b\xca\x1c\xb9\x9e\x49\xed\xc3\x0e\x11\x8e\x45\xa2\xcf\xd4\x38\xe8\x3c\xb9\x49\xc8\x6d\x44\x1f\x56\x56\xd6\xa7\x94\xec\xf1\xfa\x3d\x10\xec\xc7\xc8\xd2\x2d\x3d\x16\xb2\x95\x71\x2d\x8c\x0e\xef\xbe\x60\x4c\x91\x92\x91\x6f\x98\x2a\xdb\x9e\x98\x6d\x11\x40\xca\xdc\xe9\x57\x35\x94\x90\xa4\xf5\x21\xa1\xfc\x02\x21\x03\x31\xec\xd8\x7c\xda\x02\x29\xff\x1c\x48\x8a\xff\xa0\x86\x49\xcc\xa3\x9a\x63\x2d\x19\x3b\x21\x68\x79\xe1\x43\x3d\x75\xde\xde\xc4\x1d\x81\x28\x52\xf6\x3b\xd1\x2a\x7d\x2f\x10\xbf\x17\x48\x4b\xf0\xc1\xd1\x89\x40\x13\xf0\x00\xf4\x33\xd7\x00\x63\x11\x45\x1d\x81\x39\x37\xa4\x77\x45\x7d\x30\xab\xdb\xc0\x74\x44\x18\x1c\x3b\x76\x2d\x27\x18\x9d\xe9\xd4\xcf\xcc\x2e\xd1\x67\xc7\x3f\x82\xb1\xed\x5a\x19\xcd\xd8\x51\xe9\xeb\xc1\x55\xe6\x5a\xd2\x99\xed\x38\x75\x3a\xe1\x64\x67\xd5\x68\xcc\x28\x55\xb3\x6f\xc9\x56\x63\x81\x8a\xde\x08\xc2\x80\x02\x55\xd7\x48\x0a\x4d\x6c\x7d\x4a\x36\x01\x9f\x00\x3d\x71\x1e\xa4\xcd\x70\x8b\x55\x0e\x73\x20\xb7\x55\x9a\xf1\x7e\x35\xcc\xf2\x52\x5e\x1a\x83\x4c\x37\x57\x32\xdc\x08\xcf\x1a\xd9\x4e\x94\x61\x4a\xaf\x13\xd0\x5a\xe4\x75\xf8\xf0\xa8\x07\xb3\x28\xc7\x92\x8f\xf6\xc1\x07\x2b\x57\x16\xe9\x2f\x15\xd3\x5c\x08\xd7\xc2\x48\x80\xdf\xc2\x50\x33\x19\x66\xc5\xa2\x6e\x29\x09\x02\x68\x8d\xc2\xf0\xb7\xe0\x9c\xca\xdb\xe7\xba\x98\x5a\x24\xa0\x49\xbe\xae\x52\xf4\xd5\xba\xd1\x1a\x76\xee\x90\xe7\x46\xa1\xae\x35\xfb\x94\x3a\x22\x45\xf4\x59\x11\x60\xb2\x40\x23\x67\xfc\xb6\x28\x6f\xf8\x9c\x35\x62\x9d\x71\x48\x69\x96\xc7\x72\x8e\xde\x11\x4a\x1d\xa5\x5a\x30\x03\x7d\x40\xc3\xf4\x19\x66\xa4\x4b\xcd\x74\xf5\xc9\x66\xee\x68\x29\x57\x62\x05\x83\x52\xe6\x9b\x9e\xcd\xe0\x54\x62\x66\xc4\xed\x98\xfb\x30\x10\x95\xa0\xb2\x4f\xf3\xb9\x8f\x1e\x2a\x04\x1f\xbd\x05\xb3\x0d\xdc\x70\x06\xc4\x98\xa5\x04\x36\x69\xba\x89\x1c\x25\x45\xe5\x12\xdd\x8f\x40\x1f\xf8\x6b\x7a\xc9\x2b\xac\x4a\xee\x61\xc7\x0f\x19\xea\xa3\xd2\xde\x7c\xb4\xab\x96\xd6\x96\xcb\x4a\xb9\xee\x89\x3e\xa0\x3a\xd4\x98\xd5\xa4\xab\x58\x94\xd8\x81\xb2\x54\x92\x9b\x9f\x58\xd9\x72\xa2\xc4\xe4\x3a\x93\x82\x87\xdf\xa4\x49\xd2\x54\x6d\xc4\x76\x42\x55\x1f\x97\x25\xb1\xc7\x94\xc4\x88\xd8\x81\x48\xe9\xdd\x3c\xb1\x6e\x73\x46\xb3\xc4\xa5\x2e\x52\xc5\x98\x91\x41\xe7\x95\x3b\xa5\x65\x70\xd1\x6d\xc8\x11\x25\xff\x86\x28\xcf\x5c\xa7\x98\x03\x34\x8f\xaf\x67\x7d\xaf\xf9\x7e\x17\x93\x79\xa6\xa0\x70\x0a\x5e\x48\x4f\x04\x75\xf6\xcd\x1a\x70\x69\x9b\x15\xbc\xad\x11\xf5\xd6\xa9\xd1\x5f\xad\x86\xd7\x35\xed\x74\x19\xda\x0c\x6a\x2b\x9a\x08\xb0\xfa\x96\xc4\x4a\x9d\xdc\x3a\xb0\x98\xba\x9d\x53\x19\x16\xdd\xca\xaa\xff\x29\xe7\x6e\xe7\xe8\x56\x3a\x7c\xaf\xe1\xde\x76\xec\xa4\x2a\xec\x61\x63\x27\x64\xaa\x4d\xc7\x94\x74\xa9\xe7\x32\x4f\x58\xb5\x25\x5d\x3a\x1b\x61\x96\x85\x34\xef\xa8\x0d\x7b\xa2\x49\x97\xfb\x43\x17\x5b\x34\x05\xe1\x65\x78\xa6\xf7\x55\x1a\xab\x1e\x47\x8c\x49\x3b\xef\x05\xf8\x7a\x90\x26\x6b\x99\x0b\x3a\xb1\x6f\x87\x67\xd9\xdc\xdd\x0c\x22\xd8\xe9\x77\x3f\x1a\x0c\x6c\xa0\xd9\x57\x59\x16\x96\xd4\x5d\xa5\xff\xc3\x41\x28\x89\x20\xae\x51\xd7\xc6\x66\x13\x6c\x75\x56\x68\xc1\x9e\x98\x6d\xd3\xad\xf6\x7d\xe2\x0b\x22\x85\x6c\x7d\xde\x81\x16\x3e\x20\x11\x82\x30\x37\xfb\x28\xbd\x7a\xe4\x51\x85\xc5\xf5\x8c\xce\xf3\xcc\xec\x31\x4c\xc9\x5f\xd5\xee\x8a\xf4\x2c\xf7\xac\x71\x27\x60\xd9\x23\x24\x01\xe1\xde\x6b\x51\xfa\xfa\xb4\xcc\x00\xbd\x64\xcd\x72\x00\x4a\x60\x8a\x1d\x77\xf8\xec\xf5\xa7\xdf\x4b\xd6\xea\x8f\x7f\x25\xf6\x60\xb8\xfe\x22\x04\x1f\x07\x5e\xe6\xa0\xf6\x3c\xe1\x46\xb2\x09\xbd\x28\x1e\x3e\x10\xc6\x11\x00\x6c\xdd\x1c\x1e\x88\x16\x42\x5f\x88\xec\x5a\x12\x03\xe3\xdf\x6c\x78\x53\xd5\xa5\x0e\x2e\xe9\x5d\x5e\xdd\x82\xe5\x1b\x37\xef\xfc\xf2\x0e\xd6\x9f\xf1\x0d\x82\x91\x25\x16\xf8\x0d\xc2\x58\x5e\xb9\x71\xef\xf2\x35\x4e\xca\xe3\x02\x0c\x69\x96\x19\x53\x85\xcd\x89\x68\xd7\xe3\x8e\xdd\x49\xc0\x1b\x8b\x7f\xc0\x2b\x72\x36\x5a\x0a\x72\xf3\x19\x95\x82\x60\x54\x67\x12\x4b\xbc\x30\xbb\xe8\x94\xea\xd1\xff\x27\xbb\xf2\xd9\x61\x99\xf7\x0b\x2a\x7a\xed\xb8\xd4\xe4\x0c\x0e\xb3\xbd\x55\xb6\x56\x34\x96\x70\x70\x3f\xe0\x90\xf9\x5b\x33\x21\x56\x3a\xc6\xfa\x88\x5b\x39\xac\x28\x4c\xe5\xf8\xd9\xad\xdf\x75\xa5\x3a\x67\xc0\xbc\x7c\xb0\x54\xd1\x3d\x74\xf5\x88\x88\xb6\xa0\x0f\x2a\xfb\xb2\x4d\xf5\x8c\x7c\x41\x73\x7a\x4e\xf6\x5d\x5c\x09\xb4\x2f\x7b\xb6\x7e\xe7\x0b\xad\x65\x1f\xfd\x17\x3b\xce\x49\xaf\xe3\x20\x29\x36\x5a\x2e\xb2\x69\xe1\x32\x82\x7a\x32\x5f\x8e\x8b\x22\xe7\xc4\x6b\x4d\xaf\xa3\x1e\xe8\xc9\x5c\x8a\x07\x2e\x04\x3c\xe3\x64\x80\x4d\x85\x8f\x5c\x2d\x90\x34\x40\xf9\x4a\x15\x34\xe1\xbe\xda\xe2\xba\x15\xf2\x3f\xbd\xcd\x54\x3e\x1c\x60\x49\x08\x1b\x33\xe0\xda\x07\xef\xdf\xb9\xe8\xf2\x65\xfd\x70\x0b\x52\xf5\xeb\x61\x94\x62\x7a\x32\xcb\x06\xeb\x69\x28\xcd\x24\x3c\x21\xb0\x59\xe1\x7c\x3d\xca\xa0\x8d\x2f\xf3\xf5\xd2\xd8\x22\xa5\x17\x76\xa0\x9b\x26\x7d\x1a\x80\x28\x14\x09\x2c\xae\x76\x39\x8f\x6b\x64\xc9\x5a\x83\x9f\x44\x2c\xb3\xf5\xd4\xaa\x04\xf1\x46\x6a\x05\xc8\xcf\x2d\x31\xff\x3c\xab\x74\xe9\xe8\x57\x7a\x44\x93\x4e\xcc\x93\x40\x18\x4a\x28\xae\x4f\xa9\xb9\xcc\xb6\x30\x95\x86\x96\xfc\x56\x3a\x39\x6e\xf7\x73\x17\xa6\x8a\x3b\x11\x7c\x3c\xc5\x95\x31\x68\x7a\x95\x2b\x4e\x56\xd3\x4b\x6a\xe4\x77\x0d\xbc\x96\x74\x4b\x52\x9c\xeb\x46\x71\x94\x61\x4f\xa7\xd4\xe3\xa8\xb8\x86\x53\x3c\x9f\x8e\x23\x25\xcf\x48\x67\xca\x76\xed\x20\x38\x9a\xd9\x82\xbb\x02\x19\x86\x83\x4e\x98\xab\xcc\x36\xab\x92\x0f\x48\x83\xf9\x36\x00\xab\x3a\xc9\x94\xe2\x0f\xb2\xed\x90\xaa\xd5\x24\xc9\x8b\x66\xe3\x2c\x4f\x06\x59\x65\x95\x00\x62\x2c\xe4\xd3\x82\x45\xe8\x85\x1d\xa5\x54\x5d\xe4\xcb\x72\x32\x87\xe1\x59\x0e\xf9\xa2\xae\x20\xf3\x52\x32\x43\xdb\x66\xcf\x9e\x1d\x97\xcb\xb6\x29\x98\xd9\xf7\xc2\x9f\x19\x57\xca\x2b\xb2\x2e\xb9\x2a\x4d\x30\xdb\x1e\x26\xc9\xd3\x39\x55\xb5\x9a\x72\x59\x29\x96\xa2\x52\xd2\x84\x25\xdd\x29\x3f\x49\x9f\x14\x51\x93\xab\xbd\xb7\x3c\x51\x40\xef\x94\x63\x91\x52\x3b\x8e\x97\xb2\x99\xf8\x40\x2a\x1d\xf6\x85\x0a\x28\x67\x64\xe9\xb1\xd9\x75\xbe\x87\x1e\x15\x20\x5f\xd2\x3b\x0c\x33\x5e\x22\xb4\x6a\xcf\xad\x8d\x26\x6d\x3b\x01\x4e\x46\xec\x66\x3a\x21\x8a\x8d\xfd\xce\xec\x54\xf0\xa1\x7a\xe6\x09\xe2\xef\x37\x31\x0a\x75\x2b\x77\x02\x8b\xd6\x45\xa7\xa0\x69\x37\x8e\x51\xfc\xed\xd7\x6f\x82\x2b\xdf\x32\xbc\x49\x95\x8f\x28\x1e\xba\xae\x6d\x52\x46\x25\x19\x2c\xf1\x1e\xbc\x57\x12\x2e\x09\xc2\xb1\x2a\x13\xe6\x2d\x78\x47\x7a\xb9\x2d\xcc\xf6\xba\x6a\x4b\x59\x80\xd0\x14\xc7\x03\x65\x0d\xa3\x25\xeb\xd6\xcc\x64\x30\xa4\xa3\xdd\xae\xb9\xd2\x85\x81\x4d\x1e\xa4\xc3\x18\x36\x43\xac\x24\xe4\x2a\x4d\x87\x83\x9c\xeb\x0f\xfd\xa8\xd3\xe9\x29\xd7\x07\xe4\x75\x73\x7b\x4e\xc9\x42\x3b\xe9\x28\xb8\x70\x31\x80\x38\xc9\xe1\xc2\xc5\x9f\x2e\x06\x4e\x0e\x61\x3d\xa4\xe6\x39\x58\x15\xb4\x55\x07\x8b\x5e\xc3\xb0\x57\xf4\x72\x7f\x49\x2a\xed\x90\x0e\xe3\x85\x8d\xc3\x6a\xf9\x61\xa2\x5f\x96\x89\xf8\x4d\xce\x85\x4b\xe9\xf3\x25\x4d\xcc\xda\x2b\x8a\xbe\x58\x9c\x8a\xb8\x16\xf4\x97\xe5\xcc\x65\x81\xa8\xb3\x9d\x45\x92\x52\xea\xb2\x85\x14\xb9\x5c\x9d\x73\x47\x3c\x29\x75\x01\x5f\xb5\x81\xbd\x64\xc5\x2b\x5d\x83\xd8\x47\x44\x19\x3e\x32\xf0\x6f\x96\xad\x56\x25\xe7\x5b\xcd\x29\xf8\x77\x7e\x04\x70\xb1\x1f\x69\x65\x93\x4e\x91\x6d\x4b\x09\x3d\xd1\x67\x05\x01\x47\xdf\xbc\xd7\x75\x81\xfe\x3f\x24\x5e\x21\x10\xcc\x2c\x5c\xbe\xa0\x11\x0c\xb3\xd8\xd8\xb4\x44\xd8\xa2\xfc\xf0\x90\x6c\x22\x75\x58\x71\xee\x21\x6c\xdf\x1f\x0e\x9a\x9d\x28\x85\x26\x74\xa2\x54\xb5\xf3\x24\xdd\x22\xe7\x81\x5f\x95\x2d\x15\x50\x5b\x5d\x56\xdc\x63\xc1\x8b\x3b\xe7\x64\xe4\xb9\xa2\x7c\xbe\xe8\x84\x8f\x2d\x12\x1b\x4b\x69\x81\xab\x40\xf3\xab\xee\xd4\xba\x93\x11\x12\xae\x10\xc8\x83\x16\xae\xbf\x73\x9b\x0c\xdd\xf2\x3b\xb7\xe9\xc6\xf1\x20\x4d\x72\x8c\x04\x36\x14\x5c\x7f\xe7\x76\x80\x6d\x29\xfd\x30\xdd\xa2\x31\x8c\x10\x5c\xbd\x75\x77\x11\xf2\x84\x16\x45\x01\x27\xd6\xbf\xb2\x72\xe7\xfd\xe6\xdd\x95\xeb\xcb\x5c\xd0\x97\x6a\x9e\xdb\x3a\x89\xb7\xcc\x77\x51\x13\xf7\xd7\x34\xc5\x6d\xb2\xd8\x93\x9c\x4a\xd4\xe1\x64\x92\x0c\xde\x71\xe1\x1e\x95\x9a\x1b\x25\xc9\x7d\x4c\x19\xeb\x09\xb7\x13\x8f\xb8\xbf\xdd\x7c\x3a\x63\xa9\xe6\xdf\xbc\x98\x4f\x75\x5f\xec\x0a\x7b\x73\xe4\x5b\xe2\x27\xe6\x69\x69\x5d\xaf\x29\xbd\xb4\x7a\x01\xb7\xd4\xe1\xc2\x42\x4a\x5c\xad\x8f\xed\xdd\xd9\x0a\x40\x3e\x2d\x74\xc1\xe9\x3f\x7d\x22\x8d\x22\x94\x0e\xb1\x75\x0e\x3a\x35\x52\x43\x6c\x44\xcf\xf8\x6a\xc1\xa4\x4a\x32\x7c\x4a\x27\xa9\x0f\x4a\xa2\x2f\xbe\x81\xcd\x91\xff\x41\x7f\xad\xff\xd8\xd4\x5f\xe8\xaf\xf4\xef\xf5\x9f\xf4\xff\xf2\xf1\x16\x2e\xe1\x91\xcd\xd3\x1f\xeb\xb1\x2f\xdc\x72\x18\xe6\x69\x69\x97\xc5\x05\x10\x0a\x7f\x0e\x29\x68\xb0\xcd\x31\x22\x6c\x66\xbb\x4a\xb4\xd3\xb9\xb1\x80\x58\xb9\x76\x92\x76\x9a\xd4\x9f\x80\x09\xb7\xa6\xc8\x05\xcb\x88\x0b\xfa\xe5\x3d\x2c\xf4\x36\xb2\x80\xfb\x49\x3a\x6a\x23\x10\xf7\xfa\x62\x37\x0b\xf0\xab\x08\x8b\xec\x8b\x62\x2e\x80\xbb\x62\xd1\x5a\xfc\xe2\xce\xcd\x1b\xec\xb7\xa3\xb3\x48\xa4\xc1\x5f\x18\x2b\x77\xa3\x07\xf9\x30\xe5\xb8\x20\x57\x19\x5e\xde\xcc\xb6\x32\x04\x76\x6e\x90\x26\xed\x73\x99\xea\x75\xcf\x51\xf2\x20\x8a\xbb\x09\x89\xd1\xba\x0a\x3b\x72\xe9\xc9\x06\x1b\x61\xaa\xb8\x41\x41\xb6\x80\xc3\xe4\x19\xef\x4e\x75\xd8\x17\xcf\x12\x6e\x10\xd8\xb2\x62\xec\xa6\xa4\x6a\x90\x26\x9d\x21\x42\xc3\x0e\x37\x02\x21\xdd\x7a\x6b\x8a\x52\xea\xd2\xae\xcb\xf8\xe6\x98\xeb\x64\x18\x19\xbb\xbf\xf6\xde\x93\x05\x98\xe5\xe8\xa7\x4a\x17\x59\x80\x2b\xf3\xa5\x7d\x97\xc7\x72\x9d\x66\x8c\x22\x27\x3a\x71\xed\x96\xd7\x33\x56\x75\x53\xad\xf8\x30\x57\xba\x50\xd5\x3c\x35\x4f\x99\x69\x4e\x89\x4f\xf0\x5e\xe9\x9b\x0e\xca\x6c\x83\x1e\x9b\xcf\xfd\xb8\x92\x78\xe5\x80\xce\xab\x69\x39\xd9\xf7\x2d\xe7\x36\xb7\x1f\x14\x85\xf3\x6d\xb9\xe9\xf3\xda\x73\x14\xc9\x23\xef\x95\xe4\x9a\x1b\x43\x67\x42\x2e\x61\xf5\x7d\xc9\xb6\x3e\x2d\x0a\x95\x6e\xa7\x5c\x92\x05\x67\xf9\xab\xce\xfd\x53\x6b\xbb\x11\x73\x1b\x88\x99\x5d\x1b\xeb\xa3\xa4\xb3\x74\x4c\xf4\x4b\xb4\x4b\xfa\xd0\x99\x3c\x7f\x99\x8a\x4b\x61\xb5\x4d\xa5\x85\xcf\x4e\x9c\xf8\x5d\x99\xe5\x10\x44\x2a\x16\xcf\x6d\x3b\x3f\x5f\x1e\x95\xee\x3c\x29\x53\xf2\xc5\x94\x53\x56\x12\xd4\x9d\xea\x5d\x88\x6f\xd9\xeb\xc8\xc5\x61\xef\xd9\x3e\x62\xbb\xbf\x39\x1e\x71\x95\x08\xd6\x1c\x14\x44\x43\x18\xe5\x7b\x26\x75\x5b\xc4\x29\x73\xca\xfe\xcf\xdc\x77\x40\x5a\x8d\x46\xcd\x37\x4b\x9c\x7a\x99\xb1\xb7\xe8\x53\x8b\x89\xc3\xff\x6c\xd0\x8a\x75\x34\x67\xb7\x79\xaa\xe7\x31\xcb\x2d\x47\x36\xce\xf4\x83\xa8\x83\xd2\x7b\xf5\x83\x95\x2b\xdc\xfc\x44\x7a\x3a\xe9\x3a\x70\x5c\x90\x0b\xb9\x67\x9e\x56\x6c\xc1\x65\x71\xe0\x19\x61\xf1\xc1\x53\x45\xb9\x87\x0a\xaa\x33\xf2\x69\x43\x48\xdf\xda\xec\xce\x9a\x2e\x0e\xe3\x4b\x66\x84\x2d\x80\xb5\xbc\x13\xbe\xc6\x84\xce\xf2\x4e\x51\x7d\xb5\x35\xa9\xb2\x19\x2d\x5b\xce\x37\xf9\xab\xa5\x6b\x55\xfe\xaf\x8a\xc5\xe4\x8d\x4d\x84\x76\xc2\x1d\x44\x3e\xaf\x32\x26\x0e\x1e\x31\xfa\x0b\x3d\x76\x3d\xc5\xfa\xc0\xdb\x0a\x61\xeb\xbc\x79\x92\x33\x17\xfe\x15\x5c\x4f\xd9\x17\xe7\x4c\xfb\xfd\x11\x3b\x7e\xa2\xed\xf5\x84\x6d\x35\x1a\x57\x14\x7a\x5d\x78\x7c\xc3\x5e\xbe\xd4\xd0\x5f\x16\xe4\x60\x94\x89\xea\x54\xd6\x7f\xc2\x8d\xa3\x7a\x54\x13\x95\x90\x45\xbc\x93\x77\x12\xbc\xcd\x71\xf3\xfd\x06\x11\xfc\x71\x51\x39\xb2\x37\xb8\x59\xef\x50\x8f\xb9\xbd\x79\x6d\x75\xe8\x12\xe8\xbf\xe8\x3f\x12\x1d\x97\x6d\xc1\x16\xdb\xa3\x15\x5a\xbf\xdb\x2a\x1f\xa6\x31\x50\x84\x75\xbe\x35\x9b\xf5\x75\x8a\xa7\xea\x80\x73\x3e\x57\xbf\x92\xc4\xe4\xb4\xc5\xf2\x7f\x48\x94\x45\xbd\x25\x4c\x33\x82\xf3\xde\x0e\x6e\x2c\x2f\x5f\x81\xdb\xcb\xef\xdc\xbc\x79\x17\x2e\xdf\xb8\x02\x77\xee\x5e\xbe\x7d\x17\xae\x2f\xc3\xcd\x1b\xef\x2e\xc3\xe5\xab\x97\x57\x6e\xb4\xbe\xdb\x1e\xbf\x11\x64\x00\x80\x1b\x5e\x8a\x87\x1b\x14\x63\xe7\x49\x14\xb5\x71\xec\xef\xeb\x2b\x6c\xfc\x83\x05\xe9\xd2\x2e\xa2\xf5\xa8\x5b\x8e\x12\xf1\x4a\xf1\x62\x99\x96\x17\x2e\xfe\xd4\x36\x39\x78\x91\x46\x4d\x08\x25\xfe\xd3\x5f\xf4\xd7\xce\x04\xd9\x3e\xa5\x9a\x48\xd5\x36\x53\x3c\x01\x57\x0b\xc6\x59\x2f\x8a\xdc\x1d\x35\x4b\x98\x1d\xf9\x6b\x02\x0b\xee\x8b\x32\x0e\xff\xc0\xfb\x44\x48\xcd\xf5\x3e\x7d\xe2\x32\xb9\xfe\x2e\x17\x4b\xaa\xb9\x14\x8b\xd9\x84\xe5\x33\xb4\x20\x66\x6f\x3e\x2b\x10\x55\x1c\x33\xbc\x77\x79\xe5\xda\xf2\x95\xef\x76\xdc\x32\x97\xba\x1f\xb0\xd3\x98\xd3\x1a\xdd\x30\xea\x61\x71\xee\x0e\x67\x10\xe5\xe6\x20\xf7\x9e\x26\xb1\xbc\x96\xb1\xa5\x26\x54\xf6\x44\x50\x23\x17\x1f\x9f\x91\x0c\x8a\x4c\x72\x23\xca\xa1\xd9\x62\x60\x73\x29\xf8\xd9\xa6\x95\x98\x17\xa3\xbb\x01\xb3\xab\x70\x23\x44\xe9\xca\x79\x97\xbf\x7e\xb4\x88\xf3\xbd\x14\x23\x2e\x3a\x4c\xc9\x49\x13\x2f\xb1\x5f\x34\x7e\xb6\xf9\x22\xbb\xed\xbe\x68\xc1\x9d\x61\x9f\x42\x3a\x49\xa3\xc8\x15\x28\x2a\x85\xf5\x92\xb5\x2a\x63\x16\xbd\x62\x95\x04\x5f\x91\xb0\x28\xf5\xaa\x93\x96\xdb\x36\x4f\x66\xe3\xed\x16\xe8\xff\xb1\xd9\x44\x99\x83\x23\xf7\x25\x19\xa6\xa7\x9c\x84\x40\x77\x61\xf6\xca\xf1\x4c\x23\xfb\x78\xe6\x6b\x1d\x9e\x8b\x21\xf7\x48\x4b\xdf\xdc\x29\x92\x33\xd5\x1e\xfa\xc9\x37\xc8\xa9\x97\xb3\x20\x8b\x41\xdd\xb7\xbc\xbc\x34\xcd\x9f\xf5\xb8\xd8\xa2\x5c\xfd\xf6\x48\x56\xb3\x3f\xdb\xe7\xf0\xba\xcf\x2d\xf1\x0c\xe1\x01\x71\x00\xeb\x10\xa8\xa6\x48\xbd\xad\xce\x78\x50\xf6\xae\x31\x9e\x58\x8d\xfb\x5a\xd4\x0b\x30\x1b\x53\x9f\x0c\x1b\xb5\xf0\x3e\x01\x65\x3c\x81\x63\x6b\x49\xc5\x50\xe4\x46\x1d\x8c\x85\xa3\x0d\x74\x42\xcf\x3d\x27\xb0\x56\xf2\x7d\x2b\x70\xf3\xee\xdf\xaf\xdc\xb8\x0a\x77\x6f\xc2\xf2\x3f\xde\x5d\xbe\xf1\x1d\x75\xc0\x2c\x18\xea\x57\xc2\x10\x69\x3d\xa4\x52\x3d\xb6\x70\xf3\xb5\x42\xca\x1d\xfa\x17\x74\x2a\x52\x71\xbe\x55\xfe\x00\x1d\x05\x5a\x74\x83\x47\xba\xc1\xd3\x1a\x43\xf9\x85\xf3\xa9\xe5\xb2\x2d\x77\x3b\x48\x56\x39\x00\xd7\x00\xfa\xdc\x15\xb7\xca\x6c\xf9\x1a\xab\x89\x18\xf9\x9f\xb0\xb3\x2b\xd5\x7f\xc9\xce\x6c\xb3\xa3\x63\x03\xa6\x1a\x13\x7c\x1e\x7e\x0e\xef\x22\xf6\x3f\x47\x35\xcc\x7d\xe5\x9c\x5f\xc5\x9c\x6b\x8b\xde\xcf\x43\x86\xa7\x34\x6b\x5a\xe8\x1c\x23\x9a\xdd\xda\xfb\x24\xad\x46\x63\x19\x53\xba\x48\xb7\x6c\xa9\xc1\x0b\xd0\xf5\xc0\xf2\x12\xfc\x7d\x8c\xf3\xd0\x84\x6c\xd8\x6e\xab\x2c\x6b\xe1\xc5\x7d\xcf\xbf\xa8\xf3\x41\x26\xb6\x83\x13\xbf\x88\x33\x8c\xd5\x83\x81\x6a\xd3\x97\xc5\x70\x5b\x22\xb3\x92\x93\x94\xb4\x28\x57\x57\xe9\x13\x66\x5e\x99\x51\x80\xe0\xea\x96\x4b\x8a\x7b\x1a\xae\x92\xc5\x00\xdd\x79\xce\x76\x42\x17\xd2\xdc\x49\x04\x24\x21\x86\xad\x02\x8c\xd3\x12\xac\x86\x98\x07\x58\x1b\xf6\x55\x9c\x67\x81\x64\xce\xc8\xa5\x4f\x52\x56\xdb\x6c\x81\x38\xf8\xf0\x46\x4a\x1c\x62\x9b\x50\xfa\x61\xde\x5e\xe7\x48\xc4\x7e\xf0\x10\xb0\xd6\xeb\xf6\x54\x6f\xda\xe9\x4c\xa8\x13\x79\xec\xb7\x87\xca\xf7\x7a\xc6\x80\xb2\x46\xcd\xa6\x5c\xbe\xde\xa1\xc6\xce\x9a\xdc\x4e\xb9\x20\x68\xbf\xc4\x78\xe2\x3c\x18\x3f\x06\x11\x2d\x6e\xf6\x38\xdc\xa8\x2e\x41\xb5\xa6\x62\x0f\x95\x68\x64\x64\x63\xeb\x57\xf6\xe3\x0e\xd2\x3d\x8b\x86\xeb\x40\xf8\x9f\xda\xcd\x4b\x2d\x9d\x66\x57\x0e\xe0\x22\x34\xd9\x58\x87\x3d\xa0\x8b\x4a\x4b\x6c\x4b\x7d\x7f\x01\x92\x54\xae\x21\x57\x0a\x14\x0b\xd6\x63\x5a\xf4\x89\xfc\xdf\xac\x5e\xf9\xeb\x23\xcc\x98\x33\x8c\xaf\xc7\x42\xe4\xbf\xd2\xc2\x5a\xaa\xf2\x44\xc9\x9f\x57\x73\xe7\x55\x34\x2f\xfc\x18\xc5\xa8\x6d\xe5\x7b\x49\xb8\xac\xc3\x11\x09\x7d\x4c\xa8\xfa\x6d\xc9\x56\x99\x77\xe6\xc5\xd8\x4b\xf5\xcc\x30\x75\x52\x56\xfa\x10\x19\x9a\x85\xc9\xdc\x6b\xb6\x8c\xea\xdb\x72\x29\xbc\x8c\xaa\xbb\xcb\x19\xbc\xf6\xeb\x3f\x01\x4f\x15\x8d\x0e\x59\x14\xb7\x95\x7f\x87\x70\xae\x68\x78\x57\xd9\xea\xb9\x7b\xee\x85\xc7\xe0\x5b\x7f\xa9\x23\xa8\xfd\xcc\xe8\x84\xbd\x6c\xdf\xa8\xcf\x20\xce\x3a\xd7\x2b\x49\xb7\x8a\x68\xe2\x35\x05\x99\x46\xe3\xff\x06\x00\x9a\xac\x44\x69\x0d\x57\x00\x00")
 
 func usageTxtBytes() ([]byte, error) {
 	return bindataRead(
@@ -104,7 +104,7 @@ func usageTxt() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "usage.txt", size: 3694, mode: os.FileMode(436), modTime: time.Unix(1449659275, 0)}
+	info := bindataFileInfo{name: "usage.txt", size: 22285, mode: os.FileMode(436), modTime: time.Unix(1792189911, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
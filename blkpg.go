@@ -0,0 +1,125 @@
+package fsextender
+
+import (
+	"log"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// ioctl requests from linux/fs.h
+// Запросы ioctl из linux/fs.h
+const (
+	ioctl_BLKRRPART = 0x125f
+	ioctl_BLKPG     = 0x1269
+)
+
+// Operations of BLKPG from linux/blkpg.h
+// Операции BLKPG из linux/blkpg.h
+const (
+	blkpg_ADD_PARTITION    = 1
+	blkpg_DEL_PARTITION    = 2
+	blkpg_RESIZE_PARTITION = 3
+)
+
+// struct blkpg_partition from linux/blkpg.h
+type blkpgPartition struct {
+	Start   int64 // Bytes
+	Length  int64 // Bytes
+	Number  int32
+	DevName [64]byte
+	VolName [64]byte
+}
+
+// struct blkpg_ioctl_arg from linux/blkpg.h
+type blkpgIoctlArg struct {
+	Op      int32
+	Flags   int32
+	DataLen int32
+	Data    unsafe.Pointer
+}
+
+func ioctl(fd uintptr, request uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+/*
+Change partition in kernel without reread partition table. Unlike reread it works when other partitions of the disk
+are mounted. Resize can't change start of partition.
+
+Изменяет раздел в ядре без перечитывания таблицы разделов. В отличие от перечитывания работает, когда другие разделы
+диска смонтированы. Изменение размера не может изменить начало раздела.
+*/
+func blkpgPartitionOp(diskPath string, op int32, number uint32, start, length uint64) error {
+	disk, err := os.Open(diskPath)
+	if err != nil {
+		return err
+	}
+	defer disk.Close()
+
+	part := blkpgPartition{Start: int64(start), Length: int64(length), Number: int32(number)}
+	arg := blkpgIoctlArg{Op: op, DataLen: int32(unsafe.Sizeof(part)), Data: unsafe.Pointer(&part)}
+	return ioctl(disk.Fd(), ioctl_BLKPG, unsafe.Pointer(&arg))
+}
+
+// Reread whole partition table of disk. It fails if any partition of the disk is used.
+// Перечитывает всю таблицу разделов диска. Не работает, если какой-либо раздел диска используется.
+func blkRereadPartitionTable(diskPath string) error {
+	disk, err := os.Open(diskPath)
+	if err != nil {
+		return err
+	}
+	defer disk.Close()
+	return ioctl(disk.Fd(), ioctl_BLKRRPART, nil)
+}
+
+/*
+Tell kernel about changed partition after write partition table. Try BLKPG first, if it fails - reread partition
+table by partprobe and BLKRRPART.
+length - bytes, it rounds down to sector size.
+
+Сообщает ядру об изменённом разделе после записи таблицы разделов. Сначала пробует BLKPG, если не получилось -
+перечитывает таблицу разделов через partprobe и BLKRRPART.
+length - в байтах, округляется вниз до размера сектора.
+*/
+func kernelPartitionUpdate(op int32, part partition, length uint64) {
+	length = length / part.Disk.SectorSizeLogical * part.Disk.SectorSizeLogical
+	err := blkpgPartitionOp(part.Disk.Path, op, part.Number, part.FirstByte, length)
+	if err == nil {
+		return
+	}
+	log.Printf("Can't update partition %v in kernel by BLKPG, reread partition table: %v\n", part.Path, err)
+	kernelRereadPartitionTable(part.Disk.Path)
+}
+
+func kernelRereadPartitionTable(diskPath string) {
+	_, stderr, err := cmd("partprobe", diskPath)
+	if err == nil {
+		return
+	}
+	log.Println("Can't reread partition table by partprobe, try BLKRRPART: ", diskPath, err, stderr)
+	if err = blkRereadPartitionTable(diskPath); err != nil {
+		log.Println("Can't reread partition table: ", diskPath, err)
+	}
+}
+
+/*
+Tell kernel about moved partition. Partition must be unused. Delete and add it by BLKPG, if it fails - reread
+partition table.
+
+Сообщает ядру о перемещённом разделе. Раздел не должен использоваться. Удаляет и добавляет его через BLKPG, если
+не получилось - перечитывает таблицу разделов.
+*/
+func kernelPartitionMove(part partition) {
+	err := blkpgPartitionOp(part.Disk.Path, blkpg_DEL_PARTITION, part.Number, 0, 0)
+	if err == nil {
+		kernelPartitionUpdate(blkpg_ADD_PARTITION, part, part.Size())
+		return
+	}
+	log.Printf("Can't delete partition %v from kernel by BLKPG, reread partition table: %v\n", part.Path, err)
+	kernelRereadPartitionTable(part.Disk.Path)
+}
@@ -0,0 +1,74 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Device of btrfs filesystem
+// Устройство файловой системы btrfs
+type btrfsDevice struct {
+	DevID uint64
+	Size  uint64 // Bytes, used by filesystem. Байт, используемых файловой системой
+	Path  string
+}
+
+// Return devices of btrfs filesystem.
+// path - device of filesystem or mount point.
+// Возвращает устройства файловой системы btrfs.
+// path - устройство файловой системы или точка монтирования.
+func btrfsDevices(path string) ([]btrfsDevice, error) {
+	res, errString, err := cmd("btrfs", "filesystem", "show", "--raw", path)
+	if err != nil {
+		return nil, fmt.Errorf("%v (%v)", err, strings.TrimSpace(errString))
+	}
+	return parseBtrfsShow(res)
+}
+
+// Parse output of btrfs filesystem show --raw:
+// Разбирает вывод btrfs filesystem show --raw:
+//
+//	Label: none  uuid: 5b9c1a4e-3a0f-4c4b-9a43-8b3b1a2e0c11
+//		Total devices 2 FS bytes used 196608
+//		devid    1 size 1073741824 used 138412032 path /dev/sda1
+//		devid    2 size 536870912 used 0 path /dev/sdb1
+func parseBtrfsShow(out string) (devices []btrfsDevice, err error) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 || fields[0] != "devid" || fields[2] != "size" || fields[6] != "path" {
+			continue
+		}
+		var dev btrfsDevice
+		if dev.DevID, err = parseUint(fields[1]); err != nil {
+			return nil, err
+		}
+		if dev.Size, err = parseUint(fields[3]); err != nil {
+			return nil, err
+		}
+		dev.Path = fields[7]
+		devices = append(devices, dev)
+	}
+	if len(devices) == 0 {
+		return nil, errors.New("Can't find devices of btrfs")
+	}
+	return devices, nil
+}
+
+/*
+Size of btrfs - sum of sizes of all devices.
+path - device of filesystem or mount point.
+
+Размер btrfs - сумма размеров всех устройств.
+path - устройство файловой системы или точка монтирования.
+*/
+func fsGetSizeBtrfs(path string) (size uint64, err error) {
+	devices, err := btrfsDevices(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, dev := range devices {
+		size += dev.Size
+	}
+	return size, nil
+}
@@ -0,0 +1,63 @@
+package fsextender
+
+import (
+	"errors"
+	"log"
+)
+
+// Exit codes of program
+// Коды завершения программы
+const (
+	exit_OK                = 0
+	exit_ERROR             = 1  // Unexpected error. Непредвиденная ошибка
+	exit_NOTHING_TO_EXTEND = 10 // Plan hasn't any growth, nothing changed. В плане нет роста, ничего не изменено
+	exit_USAGE             = 11 // Bad arguments or files of arguments. Неправильные аргументы или файлы из аргументов
+	exit_PARTIAL           = 12 // Some steps of plan failed. Некоторые шаги плана завершились с ошибкой
+	exit_SCAN              = 13 // Can't scan storage layers. Не удалось просканировать слои хранения
+	exit_PLAN              = 14 // Can't make plan or plan changed since saved. Не удалось построить план или план изменился
+	exit_NEED_REBOOT       = 128
+)
+
+// Phase errors. Exit code of program depends on type of error.
+// Ошибки этапов работы. Код завершения программы зависит от типа ошибки.
+type usageError struct{ error }
+type scanError struct{ error }
+type planError struct{ error }
+type doError struct{ error }
+
+func (err usageError) Unwrap() error { return err.error }
+func (err scanError) Unwrap() error  { return err.error }
+func (err planError) Unwrap() error  { return err.error }
+func (err doError) Unwrap() error    { return err.error }
+
+var errNothingToExtend = errors.New("Nothing to extend")
+
+// Exit code for error, exit_OK for nil
+// Код завершения для ошибки, exit_OK для nil
+func exitCode(err error) int {
+	var usageErr usageError
+	var scanErr scanError
+	var planErr planError
+	var doErr doError
+	switch {
+	case err == nil:
+		return exit_OK
+	case errors.Is(err, errNothingToExtend):
+		return exit_NOTHING_TO_EXTEND
+	case errors.As(err, &usageErr):
+		return exit_USAGE
+	case errors.As(err, &scanErr):
+		return exit_SCAN
+	case errors.As(err, &planErr):
+		return exit_PLAN
+	case errors.As(err, &doErr):
+		return exit_PARTIAL
+	default:
+		return exit_ERROR
+	}
+}
+
+func exitWithError(err error) int {
+	log.Println(err)
+	return exitCode(err)
+}
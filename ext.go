@@ -0,0 +1,230 @@
+package fsextender
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Layout of ext2/3/4 superblock, offsets from start of the superblock. Described in linux fs/ext4/ext4.h.
+// Расположение суперблока ext2/3/4, смещения от начала суперблока. Описано в linux fs/ext4/ext4.h.
+const (
+	ext_SUPERBLOCK_OFFSET = 1024
+	ext_SUPERBLOCK_SIZE   = 1024
+	ext_MAGIC             = 0xEF53
+
+	ext_OFFSET_BLOCKS_COUNT_LO  = 0x04
+	ext_OFFSET_LOG_BLOCK_SIZE   = 0x18
+	ext_OFFSET_MAGIC            = 0x38
+	ext_OFFSET_STATE            = 0x3A
+	ext_OFFSET_REV_LEVEL        = 0x4C
+	ext_OFFSET_FEATURE_COMPAT   = 0x5C
+	ext_OFFSET_FEATURE_INCOMPAT = 0x60
+	ext_OFFSET_FEATURE_RO       = 0x64
+	ext_OFFSET_UUID             = 0x68
+	ext_OFFSET_VOLUME_NAME      = 0x78
+	ext_OFFSET_LAST_MOUNTED     = 0x88
+	ext_OFFSET_BLOCKS_COUNT_HI  = 0x150
+
+	ext_STATE_VALID  = 0x0001 // Cleanly unmounted. Корректно отмонтирована
+	ext_STATE_ERRORS = 0x0002 // Errors detected. Обнаружены ошибки
+	ext_STATE_ORPHAN = 0x0004 // Orphans being recovered. Восстанавливаются потерянные inode
+
+	ext_FEATURE_COMPAT_HAS_JOURNAL  = 0x0004
+	ext_FEATURE_COMPAT_RESIZE_INODE = 0x0010
+	ext_FEATURE_INCOMPAT_META_BG    = 0x0010
+	ext_FEATURE_INCOMPAT_64BIT      = 0x0080
+
+	ext_DYNAMIC_REV = 1 // Revision with feature flags. Версия с флагами возможностей
+
+	// _IOW('f', 16, __u64) from linux fs/ext4/ext4.h
+	ioctl_EXT4_RESIZE_FS = 0x40086610
+)
+
+// Fields of ext2/3/4 superblock, which used for resize
+// Поля суперблока ext2/3/4, которые используются для изменения размера
+type extSuperblock struct {
+	BlockCount      uint64
+	BlockSize       uint64
+	State           uint16
+	FeatureCompat   uint32
+	FeatureIncompat uint32
+	FeatureROCompat uint32
+	UUID            [16]byte
+	Label           string
+	LastMounted     string // Path, where filesystem was mounted last time. Путь, куда файловая система была смонтирована последний раз
+}
+
+// Read superblock of ext filesystem from device or image file
+// Читает суперблок файловой системы ext с устройства или из файла образа
+func readExtSuperblock(path string) (sb extSuperblock, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return sb, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, ext_SUPERBLOCK_SIZE)
+	if _, err = f.ReadAt(buf, ext_SUPERBLOCK_OFFSET); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return sb, fmt.Errorf("Can't read ext superblock: %v (%v)", path, err)
+	}
+	sb, err = parseExtSuperblock(buf)
+	if err != nil {
+		return sb, fmt.Errorf("%v: %v", path, err)
+	}
+	return sb, nil
+}
+
+func parseExtSuperblock(buf []byte) (sb extSuperblock, err error) {
+	if len(buf) < ext_SUPERBLOCK_SIZE {
+		return sb, fmt.Errorf("Short ext superblock: %v bytes", len(buf))
+	}
+	le := binary.LittleEndian
+	if magic := le.Uint16(buf[ext_OFFSET_MAGIC:]); magic != ext_MAGIC {
+		return sb, fmt.Errorf("Bad magic of ext superblock: %#x", magic)
+	}
+	logBlockSize := le.Uint32(buf[ext_OFFSET_LOG_BLOCK_SIZE:])
+	if logBlockSize > 6 {
+		// Max block size 64KiB
+		// Максимальный размер блока 64КиБ
+		return sb, fmt.Errorf("Bad block size of ext superblock: 2^(10+%v)", logBlockSize)
+	}
+	sb.BlockSize = 1024 << logBlockSize
+	sb.BlockCount = uint64(le.Uint32(buf[ext_OFFSET_BLOCKS_COUNT_LO:]))
+	sb.State = le.Uint16(buf[ext_OFFSET_STATE:])
+
+	// Revision 0 hasn't feature flags, label and UUID
+	// В версии 0 нет флагов возможностей, метки и UUID
+	if le.Uint32(buf[ext_OFFSET_REV_LEVEL:]) < ext_DYNAMIC_REV {
+		return sb, nil
+	}
+	sb.FeatureCompat = le.Uint32(buf[ext_OFFSET_FEATURE_COMPAT:])
+	sb.FeatureIncompat = le.Uint32(buf[ext_OFFSET_FEATURE_INCOMPAT:])
+	sb.FeatureROCompat = le.Uint32(buf[ext_OFFSET_FEATURE_RO:])
+	copy(sb.UUID[:], buf[ext_OFFSET_UUID:])
+	sb.Label = cString(buf[ext_OFFSET_VOLUME_NAME : ext_OFFSET_VOLUME_NAME+16])
+	sb.LastMounted = cString(buf[ext_OFFSET_LAST_MOUNTED : ext_OFFSET_LAST_MOUNTED+64])
+	if sb.Is64bit() {
+		sb.BlockCount |= uint64(le.Uint32(buf[ext_OFFSET_BLOCKS_COUNT_HI:])) << 32
+	}
+	return sb, nil
+}
+
+// Size of filesystem in bytes
+// Размер файловой системы в байтах
+func (sb extSuperblock) Size() uint64 {
+	return sb.BlockCount * sb.BlockSize
+}
+
+func (sb extSuperblock) Is64bit() bool {
+	return sb.FeatureIncompat&ext_FEATURE_INCOMPAT_64BIT != 0
+}
+
+func (sb extSuperblock) HasResizeInode() bool {
+	return sb.FeatureCompat&ext_FEATURE_COMPAT_RESIZE_INODE != 0
+}
+
+func (sb extSuperblock) HasMetaBG() bool {
+	return sb.FeatureIncompat&ext_FEATURE_INCOMPAT_META_BG != 0
+}
+
+func (sb extSuperblock) HasJournal() bool {
+	return sb.FeatureCompat&ext_FEATURE_COMPAT_HAS_JOURNAL != 0
+}
+
+// Filesystem was cleanly unmounted and hasn't errors. Mounted filesystem isn't clean.
+// Файловая система была корректно отмонтирована и не содержит ошибок. Смонтированная файловая система не чистая.
+func (sb extSuperblock) IsClean() bool {
+	return sb.State&ext_STATE_VALID != 0 && sb.State&(ext_STATE_ERRORS|ext_STATE_ORPHAN) == 0
+}
+
+func (sb extSuperblock) HasErrors() bool {
+	return sb.State&ext_STATE_ERRORS != 0
+}
+
+// Max size of filesystem in bytes. Without 64bit feature count of blocks is 32-bit.
+// Максимальный размер файловой системы в байтах. Без возможности 64bit количество блоков 32-битное.
+func (sb extSuperblock) MaxSize() uint64 {
+	if sb.Is64bit() {
+		return ^uint64(0) - sb.BlockSize + 1
+	}
+	return (1<<32 - 1) * sb.BlockSize
+}
+
+func (sb extSuperblock) UUIDString() string {
+	return uuidString(sb.UUID)
+}
+
+/*
+Grow mounted ext4 to blockCount blocks by kernel (EXT4_IOC_RESIZE_FS), as resize2fs does for mounted filesystem.
+mountPoint - any path inside of the filesystem.
+Error *ext4ResizeError with errno of kernel.
+
+Расширяет смонтированную ext4 до blockCount блоков средствами ядра (EXT4_IOC_RESIZE_FS), как это делает resize2fs для
+смонтированной файловой системы.
+mountPoint - любой путь внутри файловой системы.
+Ошибка *ext4ResizeError с errno ядра.
+*/
+func ext4ResizeFS(mountPoint string, blockCount uint64) error {
+	f, err := os.Open(mountPoint)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err = ioctl(f.Fd(), ioctl_EXT4_RESIZE_FS, unsafe.Pointer(&blockCount)); err != nil {
+		return &ext4ResizeError{MountPoint: mountPoint, BlockCount: blockCount, Errno: err.(syscall.Errno)}
+	}
+	return nil
+}
+
+type ext4ResizeError struct {
+	MountPoint string
+	BlockCount uint64
+	Errno      syscall.Errno
+}
+
+func (err *ext4ResizeError) Error() string {
+	var reason string
+	switch err.Errno {
+	case syscall.EPERM:
+		reason = "need root (CAP_SYS_RESOURCE)"
+	case syscall.EINVAL:
+		reason = "new size is less then current or more then max size of filesystem"
+	case syscall.EBUSY:
+		reason = "other resize of the filesystem in progress"
+	case syscall.EOPNOTSUPP:
+		reason = "filesystem features doesn't allow online resize"
+	case syscall.ENOTTY:
+		reason = "it isn't ext4 or kernel doesn't support the ioctl"
+	case syscall.EROFS:
+		reason = "filesystem mounted read only"
+	default:
+		reason = err.Errno.Error()
+	}
+	return fmt.Sprintf("EXT4_IOC_RESIZE_FS %v to %v blocks: %v (%v)", err.MountPoint, err.BlockCount, reason,
+		err.Errno.Error())
+}
+
+func (err *ext4ResizeError) Unwrap() error { return err.Errno }
+
+// UUID in text form: 0f8a2e3c-5d1b-4c7e-9a1f-2b3c4d5e6f70
+// UUID в текстовом виде: 0f8a2e3c-5d1b-4c7e-9a1f-2b3c4d5e6f70
+func uuidString(u [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// String from zero-terminated byte array
+// Строка из массива байт, завершённого нулём
+func cString(buf []byte) string {
+	if end := bytes.IndexByte(buf, 0); end != -1 {
+		buf = buf[:end]
+	}
+	return string(buf)
+}
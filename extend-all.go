@@ -0,0 +1,421 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Filesystems, which extends by --all
+// Файловые системы, которые расширяются через --all
+var allFSTypes = map[string]bool{"ext2": true, "ext3": true, "ext4": true, "xfs": true}
+
+/*
+Mount points of all mounted ext and xfs filesystems. Every device returns once, also if it mounted few times.
+Точки монтирования всех смонтированных файловых систем ext и xfs. Каждое устройство возвращается один раз, даже если
+оно смонтировано несколько раз.
+*/
+func mountedStartPoints() (res []string, err error) {
+	mounts, err := readMountInfo()
+	if err != nil {
+		return nil, scanError{fmt.Errorf("Can't read mountinfo: %v", err)}
+	}
+	return filterStartPoints(mounts), nil
+}
+
+func filterStartPoints(mounts []mountInfo) (res []string) {
+	devices := make(map[[2]int]bool)
+	for _, mount := range mounts {
+		if !allFSTypes[mount.FSType] || mount.Root != "/" {
+			continue
+		}
+		if top, _ := findMountByPath(mounts, mount.MountPoint); top.MountID != mount.MountID {
+			// Over-mounted. Перекрыто другим монтированием.
+			continue
+		}
+		mm := [2]int{mount.Major, mount.Minor}
+		if devices[mm] {
+			continue
+		}
+		devices[mm] = true
+		res = append(res, mount.MountPoint)
+	}
+	return res
+}
+
+/*
+Policy of divide free space of LVM volume group between few logical volumes, which extend in one run.
+equal - same growth for every LV. proportional - growth proportional to current size of LV.
+Weights - growth proportional to weight of LV (by start point or LV path, default weight 1).
+
+Политика деления свободного места группы томов LVM между несколькими логическими томами, расширяемыми за один запуск.
+equal - одинаковый рост для каждого LV. proportional - рост пропорционален текущему размеру LV.
+Weights - рост пропорционален весу LV (по точке старта или пути LV, вес по умолчанию 1).
+*/
+type vgPolicy struct {
+	Proportional bool
+	Weights      map[string]uint64
+}
+
+func (policy vgPolicy) String() string {
+	switch {
+	case policy.Proportional:
+		return "proportional"
+	case len(policy.Weights) > 0:
+		var res []string
+		for key, weight := range policy.Weights {
+			res = append(res, key+"="+strconv.FormatUint(weight, 10))
+		}
+		sort.Strings(res)
+		return strings.Join(res, ",")
+	default:
+		return "equal"
+	}
+}
+
+// Parse policy: equal, proportional or PATH=WEIGHT,PATH=WEIGHT
+// Разбирает политику: equal, proportional или ПУТЬ=ВЕС,ПУТЬ=ВЕС
+func parseVGPolicy(s string) (res vgPolicy, err error) {
+	switch s {
+	case "", "equal":
+		return res, nil
+	case "proportional":
+		res.Proportional = true
+		return res, nil
+	}
+	res.Weights = make(map[string]uint64)
+	for _, rule := range strings.Split(s, ",") {
+		eq := strings.LastIndex(rule, "=")
+		if eq <= 0 {
+			return vgPolicy{}, fmt.Errorf("Bad weight, need PATH=WEIGHT: %q", rule)
+		}
+		// Sum of 32 bit weights doesn't overflow, so share of growth in vgShares fits in 64 bit.
+		// Сумма 32-битных весов не переполняется, поэтому доля роста в vgShares помещается в 64 бита.
+		weight, err := strconv.ParseUint(rule[eq+1:], 10, 32)
+		if err != nil {
+			return vgPolicy{}, fmt.Errorf("Bad weight of %v: %v", rule[:eq], err)
+		}
+		res.Weights[rule[:eq]] = weight
+	}
+	return res, nil
+}
+
+// Weight of LV by policy
+// Вес LV согласно политике
+func (policy vgPolicy) weight(lv storageItem, startPoint string) uint64 {
+	switch {
+	case policy.Proportional:
+		return lv.Size
+	case len(policy.Weights) > 0:
+		if weight, ok := policy.Weights[startPoint]; ok {
+			return weight
+		}
+		if weight, ok := policy.Weights[lv.Path]; ok {
+			return weight
+		}
+		return 1
+	default:
+		return 1
+	}
+}
+
+/*
+Scan every start point, make plans and merge them to one plan. Shared devices extend once.
+Free space of LVM volume group divides between LVs of the group by policy.
+
+Сканирует каждую точку старта, строит планы и объединяет их в один план. Общие устройства расширяются один раз.
+Свободное место группы томов LVM делится между LV этой группы согласно политике.
+*/
+func extendPlanAll(startPoints []string, options planOptions, policy vgPolicy) (plan []storageItem, err error) {
+	if len(startPoints) > 1 && !options.Size.IsMax() {
+		return nil, usageError{errors.New("Target size can be used with one start point only")}
+	}
+	plans := make([][]storageItem, 0, len(startPoints))
+	for _, startPoint := range startPoints {
+		// Numbers of new partitions must be same for every scan, else same free space has different paths in plans.
+		// Номера новых разделов должны совпадать при каждом сканировании, иначе одно свободное место будет иметь
+		// разные пути в планах.
+		diskNewPartitionNumLastGeneratedNum = make(map[[2]int]uint32)
+		storage, err := extendScanWays(startPoint)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", startPoint, err)
+		}
+		startPlan, err := extendPlan(storage, options)
+		if err != nil {
+			return nil, fmt.Errorf("Error while make extend plan of %v: %w", startPoint, err)
+		}
+		plans = append(plans, startPlan)
+	}
+	if len(plans) == 1 {
+		return plans[0], nil
+	}
+	plan, lvStartPoints := mergePlans(startPoints, plans)
+	planDivideVG(plan, lvStartPoints, policy)
+	return plan, nil
+}
+
+// Key of item for find same items in different plans
+// Ключ элемента для поиска одинаковых элементов в разных планах
+func planItemKey(item storageItem) string {
+	itemType := item.Type
+	if itemType == type_SKIP {
+		itemType = item.OldType
+	}
+	return itemType.String() + ":" + item.Path
+}
+
+/*
+Merge plans of few start points. Same items (same type and path) merge to one step. If item of later plan extends
+other device, then in earlier plans (for example free PV can be added to one volume group only) - the item skipped
+in later plan. Volume group can extend few LVs. Swap creation stays at end of plan.
+Return merged plan and start points of LVs in it.
+
+Объединяет планы нескольких точек старта. Одинаковые элементы (тот же тип и путь) объединяются в один шаг. Если
+элемент более позднего плана расширяет другое устройство, чем в более ранних планах (например свободный PV может
+быть добавлен только в одну группу томов) - элемент пропускается в более позднем плане. Группа томов может расширять
+несколько LV. Создание swap остается в конце плана.
+Возвращает объединенный план и точки старта LV в нём.
+*/
+func mergePlans(startPoints []string, plans [][]storageItem) (merged []storageItem, lvStartPoints map[int]string) {
+	lvStartPoints = make(map[int]string)
+	keys := make(map[string]int)       // key -> index in merged
+	usedPaths := make(map[string]bool) // paths of not skipped items in merged
+	childKey := func(plan []storageItem, index int) string {
+		if plan[index].Child == -1 {
+			return ""
+		}
+		return planItemKey(plan[plan[index].Child])
+	}
+	for planIndex, plan := range plans {
+		plan = append([]storageItem(nil), plan...)
+		for i, item := range plan {
+			if item.Type == type_SKIP || item.Type == type_LVM_GROUP {
+				continue
+			}
+			if mergedIndex, ok := keys[planItemKey(item)]; ok && merged[mergedIndex].Type != type_SKIP &&
+				childKey(merged, mergedIndex) != childKey(plan, i) {
+				planCancel(plan, i, "Used for extend other device: "+merged[merged[mergedIndex].Child].Path)
+			}
+		}
+
+		indexes := make([]int, len(plan)) // index in plan -> index in merged, -1 - dropped
+		added := make([]bool, len(plan))  // item added to merged from the plan
+		for i, item := range plan {
+			key := planItemKey(item)
+			switch mergedIndex, ok := keys[key]; {
+			case ok && merged[mergedIndex].Type == type_SKIP && item.Type != type_SKIP:
+				merged[mergedIndex] = item
+				indexes[i], added[i] = mergedIndex, true
+			case ok:
+				indexes[i] = mergedIndex
+				continue
+			case item.Type == type_SKIP && usedPaths[item.Path]:
+				indexes[i] = -1
+				continue
+			default:
+				keys[key] = len(merged)
+				indexes[i], added[i] = len(merged), true
+				merged = append(merged, item)
+			}
+			if item.Type != type_SKIP {
+				usedPaths[item.Path] = true
+			}
+			if item.Type == type_LVM_LV {
+				lvStartPoints[indexes[i]] = startPoints[planIndex]
+			}
+		}
+		for i, item := range plan {
+			if !added[i] {
+				continue
+			}
+			if item.Child == -1 {
+				merged[indexes[i]].Child = -1
+			} else {
+				merged[indexes[i]].Child = indexes[item.Child]
+			}
+		}
+	}
+
+	merged, indexes := planReorder(merged, planLayersOrder(merged))
+	lvOrdered := make(map[int]string, len(lvStartPoints))
+	for index, startPoint := range lvStartPoints {
+		lvOrdered[indexes[index]] = startPoint
+	}
+	return merged, lvOrdered
+}
+
+/*
+Order of items for execute: every item before items, which it extends. Swap creation after all other steps.
+Items, which placed after item they extend, move just before it, other items keep order.
+
+Порядок выполнения элементов: каждый элемент раньше элементов, которые он расширяет. Создание swap - после всех
+остальных шагов. Элементы, расположенные после расширяемого ими элемента, перемещаются прямо перед ним, остальные
+элементы сохраняют порядок.
+*/
+func planLayersOrder(plan []storageItem) (order []int) {
+	for i, item := range plan {
+		if item.Type != type_SWAP_CREATE {
+			order = append(order, i)
+		}
+	}
+	for i, item := range plan {
+		if item.Type == type_SWAP_CREATE {
+			order = append(order, i)
+		}
+	}
+	for moved := true; moved; {
+		moved = false
+		positions := make([]int, len(plan))
+		for pos, index := range order {
+			positions[index] = pos
+		}
+	orderLoop:
+		for pos, index := range order {
+			for _, child := range planChildren(plan, index) {
+				childPos := positions[child]
+				if childPos > pos {
+					continue
+				}
+				newOrder := make([]int, 0, len(order))
+				newOrder = append(newOrder, order[:childPos]...)
+				newOrder = append(newOrder, index)
+				newOrder = append(newOrder, order[childPos:pos]...)
+				newOrder = append(newOrder, order[pos+1:]...)
+				order = newOrder
+				moved = true
+				break orderLoop
+			}
+		}
+	}
+	return order
+}
+
+// Reorder items of plan and fix Child indexes. Return new plan and map old index -> new index.
+// Переупорядочивает элементы плана и исправляет индексы Child. Возвращает новый план и соответствие старых индексов новым.
+func planReorder(plan []storageItem, order []int) (res []storageItem, indexes []int) {
+	indexes = make([]int, len(plan))
+	res = make([]storageItem, len(order))
+	for newIndex, oldIndex := range order {
+		indexes[oldIndex] = newIndex
+		res[newIndex] = plan[oldIndex]
+	}
+	for i := range res {
+		if res[i].Child != -1 {
+			res[i].Child = indexes[res[i].Child]
+		}
+	}
+	return res, indexes
+}
+
+/*
+Indexes of items, which plan[index] extends. Volume group extends every LV of the group in plan.
+Индексы элементов, которые расширяет plan[index]. Группа томов расширяет каждый LV этой группы в плане.
+*/
+func planChildren(plan []storageItem, index int) []int {
+	item := plan[index]
+	if item.Child == -1 {
+		return nil
+	}
+	res := []int{item.Child}
+	if item.Type != type_LVM_GROUP {
+		return res
+	}
+	for i, lv := range plan {
+		if i != item.Child && lv.Type == type_LVM_LV && lvmLVGroupName(lv.Path) == item.Path {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Name of volume group of LV: vg/lv -> vg
+// Имя группы томов LV: vg/lv -> vg
+func lvmLVGroupName(lvPath string) string {
+	if slash := strings.Index(lvPath, "/"); slash != -1 {
+		return lvPath[:slash]
+	}
+	return lvPath
+}
+
+/*
+Divide growth of volume groups between few LVs of the group by policy: limit every LV by its share. Shares round
+down to extent size, growth of the group and its PVs reduces to sum of shares.
+
+Делит рост групп томов между несколькими LV группы согласно политике: ограничивает каждый LV его долей. Доли
+округляются вниз до размера экстента, рост группы и её PV уменьшается до суммы долей.
+*/
+func planDivideVG(plan []storageItem, lvStartPoints map[int]string, policy vgPolicy) {
+	grow := planGrowth(plan)
+	for vgIndex, vg := range plan {
+		if vg.Type != type_LVM_GROUP {
+			continue
+		}
+		lvs := planChildren(plan, vgIndex)
+		if len(lvs) < 2 {
+			continue
+		}
+		var weights []uint64
+		for _, lvIndex := range lvs {
+			weights = append(weights, policy.weight(plan[lvIndex], lvStartPoints[lvIndex]))
+		}
+		shares, total := vgShares(grow[vgIndex], weights, vg.LVMExtentSize)
+		if total < grow[vgIndex] {
+			// Underliing layers can provide less then need, if they can't be limited exactly
+			// Нижележащие слои могут предоставить меньше нужного, если их нельзя ограничить точно
+			if provided := planAllocate(plan, grow, vgIndex, total); provided < total {
+				shares, _ = vgShares(provided, weights, vg.LVMExtentSize)
+			}
+		}
+		for n, lvIndex := range lvs {
+			lv := &plan[lvIndex]
+			lv.Limit = lv.Size + shares[n]
+			if lv.FreeSpace > shares[n] {
+				lv.FreeSpace = shares[n]
+			}
+		}
+	}
+}
+
+// Shares of growth by weights, rounded down to extent size. total - sum of the shares.
+// Доли роста согласно весам, округлённые вниз до размера экстента. total - сумма долей.
+func vgShares(grow uint64, weights []uint64, extentSize uint64) (shares []uint64, total uint64) {
+	var sum uint64
+	for _, weight := range weights {
+		sum += weight
+	}
+	shares = make([]uint64, len(weights))
+	for n, weight := range weights {
+		if sum != 0 {
+			// Weights of proportional policy are sizes, multiply them in 128 bit for avoid overflow.
+			// Веса политики proportional - это размеры, перемножаем их в 128 битах во избежание переполнения.
+			hi, lo := bits.Mul64(grow, weight)
+			shares[n], _ = bits.Div64(hi, lo, sum)
+		}
+		if extentSize != 0 {
+			shares[n] -= shares[n] % extentSize
+		}
+		total += shares[n]
+	}
+	return shares, total
+}
+
+// Append strings, which slice doesn't contain yet
+// Добавляет строки, которых ещё нет в срезе
+func appendUniqueStrings(slice []string, strs ...string) []string {
+	for _, s := range strs {
+		found := false
+		for _, existed := range slice {
+			if existed == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			slice = append(slice, s)
+		}
+	}
+	return slice
+}
@@ -2,452 +2,258 @@ package fsextender
 
 import (
 	"fmt"
-	"github.com/rekby/gpt"
-	"github.com/rekby/mbr"
-	"io/ioutil"
 	"log"
-	"os"
 	"strconv"
-	"time"
 )
 
 const MAX_UINT32 = 4294967295
 const TRY_COUNT = 5 // Retry operations if it can and first is fail. For example - fast change LVM not always succesfully
 // and need retry after few seconds.
 
-func extendPrint(plan []storageItem) {
-	addSize := make([]uint64, len(plan))
+/*
+Planned growth of every item of plan: own free space with growth of underliing items.
+Планируемый рост каждого элемента плана: собственное свободное место вместе с ростом нижележащих элементов.
+*/
+func planGrowth(plan []storageItem) []uint64 {
+	grow := make([]uint64, len(plan))
 	for i, item := range plan {
-		item.FreeSpace += addSize[i]
-		if item.Child != -1 {
-			addSize[item.Child] += item.FreeSpace
+		grow[i] += item.FreeSpace
+		if item.Limit != 0 && item.Size+grow[i] > item.Limit {
+			grow[i] = item.Limit - item.Size
 		}
-		fmt.Print(strconv.Itoa(i) + ": ")
-		switch item.Type {
-		case type_PARTITION:
-			fmt.Println(item, "May need reboot")
-		case type_PARTITION_NEW:
-			if item.Partition.Disk.PartTable == "msdos" && item.Partition.Number > 4 {
-				fmt.Println("!!! ATTENTION, Can't create more then 4 partition in msdos table. Skip it. ", item)
-			} else {
-				fmt.Println(item)
+		// Free space of RAID calculated by usable size of array, not by sum of members.
+		// Свободное место RAID вычислено по полезному объему массива, а не по сумме участников.
+		for _, child := range planChildren(plan, i) {
+			if plan[child].Type != type_RAID {
+				grow[child] += grow[i]
 			}
-		default:
+		}
+	}
+	return grow
+}
+
+func extendPrint(plan []storageItem) {
+	grow := planGrowth(plan)
+	for i, item := range plan {
+		item.FreeSpace = grow[i]
+		fmt.Print(strconv.Itoa(i) + ": ")
+		if l := layerOf(item); l != nil {
+			fmt.Println(l.Describe(item))
+		} else {
 			fmt.Println(item)
 		}
 	}
 }
 
-func extendDo(plan []storageItem) (needReboot bool) {
-	for i := range plan {
-		log.Println("DO ", strconv.Itoa(i)+":", plan[i])
-		item := &plan[i]
-		switch item.Type {
-		case type_PARTITION:
-			oldKernelSize := getDiskSize(item.Path)
-			oldFreeSpace := item.FreeSpace
-			switch item.Partition.Disk.PartTable {
-			case "msdos":
-				if item.Partition.Number > 4 {
-					log.Println("WARNING: Can't work with partition number > 4 in msdos partition table. Skip it.")
-					continue
-				}
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDONLY|os.O_SYNC, 0)
-				if err != nil {
-					log.Println("Can't open disk: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				partTable, err := mbr.Read(diskIO)
-				diskIO.Close()
-				if err != nil {
-					log.Println("Can't read partition table: ", item.Partition.Disk.Path, err)
-					continue
-				}
-				newSize := item.Size + item.FreeSpace
-				sectorSize := newSize / item.Partition.Disk.SectorSizeLogical
-				if sectorSize > MAX_UINT32 {
-					// Тут возможно окргление размера в меньшую сторону, но пока для простоты - просто пропускаем.
-					log.Printf("New partition size greater then can be in msdos table. SKIP IT.")
-					continue
-				}
-				partTable.GetPartition(int(item.Partition.Number)).SetLBALen(uint32(sectorSize))
+// Options of execute plan
+// Параметры выполнения плана
+type doOptions struct {
+	CryptKeyFile string   // Key file for cryptsetup resize. Empty string - without key file. Файл ключа для cryptsetup resize. Пустая строка - без файла ключа
+	Journal      *journal // State journal for resume after reboot. nil - without journal. Журнал состояния для продолжения после перезагрузки. nil - без журнала
+	BackupDir    string   // Directory for backups of partition tables. Папка для резервных копий таблиц разделов
+	XFSGrowRT    bool     // Grow realtime section of xfs. Расширять realtime раздел xfs
+	XFSGrowLog   bool     // Grow external log of xfs. Расширять внешний журнал xfs
+}
 
-				diskIO, err = os.OpenFile(item.Partition.Disk.Path, os.O_WRONLY|os.O_SYNC, 0)
-				if err != nil {
-					log.Println("Can't open disk (2): ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				err = partTable.Write(diskIO)
-				diskIO.Close()
-				if err != nil {
-					log.Println("WARNING!!!!!! Can't write new partition table. Disk partition table can be damaged check it.")
-					continue
-				}
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += item.FreeSpace
-					item.Size += item.FreeSpace
-					item.FreeSpace = 0
-				}
-				log.Printf("Partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(oldFreeSpace))
-			case "gpt":
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR|os.O_SYNC, 0)
-				defer diskIO.Close() // Have to be closed manually. Defer close - for protect only.
-				if err != nil {
-					if err != nil {
-						log.Println("Can't open disk: ", item.Partition.Disk.Path, err)
-						diskIO.Close()
-						continue
-					}
-				}
-				_, err = diskIO.Seek(int64(item.Partition.Disk.SectorSizeLogical), 0)
-				if err != nil {
-					log.Println("Can't seek gpt disk: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				gptTable, err := gpt.ReadTable(diskIO, item.Partition.Disk.SectorSizeLogical)
-				if err != nil {
-					log.Println("Can't read gpt table: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				if uint32(len(gptTable.Partitions)) < item.Partition.Number {
-					log.Println("gpt bad partition number")
-					diskIO.Close()
-					continue
-				}
-				gptTable.Partitions[item.Partition.Number-1].LastLBA += item.FreeSpace / item.Partition.Disk.SectorSizeLogical
-				if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-					diskSizeInSectors := item.Partition.Disk.Size / item.Partition.Disk.SectorSizeLogical
-					gptTable = gptTable.CreateTableForNewDiskSize(diskSizeInSectors)
+// Status of plan step after execute
+// Состояние шага плана после выполнения
+type stepStatus int
 
-					if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-						log.Println("ATTENTION!!! Error in calc of GPT partition size", item.Path)
-						diskIO.Close()
-						continue
-					}
-				}
+const (
+	step_NOT_RUN           stepStatus = iota // Execute stopped before the step. Выполнение остановлено до шага
+	step_DONE                                // Шаг выполнен
+	step_FAILED                              // Шаг завершился с ошибкой
+	step_DEPENDENCY_FAILED                   // Step, which the step depends on, failed. Шаг, от которого зависит этот шаг, завершился с ошибкой
+)
 
-				// First write table at end of disk, becouse it can be empty after extend of phisical disk.
-				// Сначала записываем таблицу разделов в конец диска, т.к. она может отсутствовать на обычном месте после расширения диска
-				err = gptTable.CreateOtherSideTable().Write(diskIO)
-				if err != nil {
-					log.Println("WARNING!!! Write GPT PRIMARY TABLE error. DATA MAY BE LOST.", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				err = gptTable.Write(diskIO)
-				if err != nil {
-					log.Println("WARNING!!! Write GPT SECONDARY TABLE error. DATA MAY BE LOST.", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += item.FreeSpace
-					item.Size += item.FreeSpace
-					item.FreeSpace = 0
-				}
-				log.Printf("Partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(oldFreeSpace))
-			default:
-				log.Printf("I don't know partition table: %v(%v)", item.Partition.Disk.PartTable, item.Path)
-				continue
-			}
-			cmd("partprobe", item.Partition.Disk.Path)
-			newKernelSize := getDiskSize(item.Path)
-			if oldKernelSize == newKernelSize && oldFreeSpace != 0 {
-				log.Println("NEED REBOOT!")
-				needReboot = true
-			}
-		case type_PARTITION_NEW:
-			switch item.Partition.Disk.PartTable {
-			case "msdos":
-				if item.Partition.Number > 4 {
-					log.Println("WARNING: Can't create partition with number > 4 in msdos partition table.")
-					continue
-				}
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR, 0)
-				if err != nil {
-					log.Println("Can't create partition: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				partTable, err := mbr.Read(diskIO)
-				if err != nil {
-					log.Println("Can't read mbr partition table: ", item.Path, err)
-					diskIO.Close()
-					continue
-				}
-				partition := partTable.GetPartition(int(item.Partition.Number))
-				if partition == nil {
-					log.Println("Can't get mbr partition: ", item.Path)
-					diskIO.Close()
-					continue
-				}
-				if !partition.IsEmpty() {
-					log.Println("Mbr partition isn't empty: ", item.Path)
-					diskIO.Close()
-					continue
-				}
-				partition.SetType(mbr.PART_LVM)
-				lbaStart := item.Partition.FirstByte / item.Partition.Disk.SectorSizeLogical
-				if lbaStart >= MAX_UINT32 {
-					log.Println("Can't create msdos partition - sector number overflow", item.Path)
-					diskIO.Close()
-					continue
-				}
-				partition.SetLBAStart(uint32(lbaStart))
-				bytesLen := item.Partition.LastByte - item.Partition.FirstByte + 1
-				lbaLen := (bytesLen) / item.Partition.Disk.SectorSizeLogical
-				if bytesLen%item.Partition.Disk.SectorSizeLogical != 0 {
-					lbaLen += 1
-				}
-				if uint64(partition.GetLBAStart())+lbaLen > MAX_UINT32 {
-					lbaLen = uint64(MAX_UINT32 - partition.GetLBAStart())
-				}
-				partition.SetLBALen(uint32(lbaLen))
-				if partTable.Check() != nil {
-					log.Println("Bad partition table after virtual create partition ", item.Path, partTable.Check())
-					diskIO.Close()
-					continue
-				}
-				_, err = diskIO.Seek(0, 0)
-				if err != nil {
-					log.Println("Mbr, can't seek diskIO", err)
-					diskIO.Close()
-					continue
-				}
+func (status stepStatus) String() string {
+	switch status {
+	case step_NOT_RUN:
+		return "NOT RUN"
+	case step_DONE:
+		return "DONE"
+	case step_FAILED:
+		return "FAILED"
+	case step_DEPENDENCY_FAILED:
+		return "DEPENDENCY FAILED"
+	default:
+		return "stepStatus(" + strconv.Itoa(int(status)) + ")"
+	}
+}
 
-				err = partTable.Write(diskIO)
-				if err != nil {
-					log.Println("Mbr, can't write", err)
-					diskIO.Close()
-					continue
-				}
-				diskIO.Close()
-				cmd("partprobe", item.Partition.Disk.Path)
-				log.Printf("Partition created: %v (%v)\n", item.Path, formatSize(lbaLen*item.Partition.Disk.SectorSizeLogical))
-			case "gpt":
-				diskIO, err := os.OpenFile(item.Partition.Disk.Path, os.O_RDWR, 0)
-				if err != nil {
-					log.Println("Can't open disk for new partition in gpt:", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				_, err = diskIO.Seek(int64(item.Partition.Disk.SectorSizeLogical), 0)
-				if err != nil {
-					log.Println("Can't seek disk read gpt table for new partition: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				gptTable, err := gpt.ReadTable(diskIO, item.Partition.Disk.SectorSizeLogical)
-				if err != nil {
-					log.Println("Can't read gpt table, new partition: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				if int(item.Partition.Number) >= len(gptTable.Partitions) || item.Partition.Number < 1 {
-					log.Println("Bad partition number for create partition in gpt: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				part := &gptTable.Partitions[item.Partition.Number-1]
-				part.FirstLBA = item.Partition.FirstByte / item.Partition.Disk.SectorSizeLogical
-				part.LastLBA = item.Partition.LastByte / item.Partition.Disk.SectorSizeLogical
-				part.Type = gpt.GUID_LVM
+type stepResult struct {
+	Item   storageItem // State of item after execute. Состояние элемента после выполнения
+	Status stepStatus
+	Err    error
+}
 
-				if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-					diskSizeInSectors := item.Partition.Disk.Size / item.Partition.Disk.SectorSizeLogical
-					gptTable = gptTable.CreateTableForNewDiskSize(diskSizeInSectors)
+// Result of execute plan
+// Результат выполнения плана
+type doResult struct {
+	Steps      []stepResult
+	NeedReboot bool
+}
 
-					if gptTable.Partitions[item.Partition.Number-1].LastLBA > gptTable.Header.LastUsableLBA {
-						log.Println("ATTENTION!!! Error in calc of GPT partition size2", item.Path)
-						diskIO.Close()
-						continue
-					}
-				}
-				err = gptTable.Write(diskIO)
-				if err != nil {
-					log.Println("WARNING ERROR WHILE WRITE PRIMARY GPT PARTITION TABLE: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				err = gptTable.CreateOtherSideTable().Write(diskIO)
-				if err != nil {
-					log.Println("WARNING ERROR WHILE WRITE SECONDARY GPT PARTITION TABLE: ", item.Partition.Disk.Path, err)
-					diskIO.Close()
-					continue
-				}
-				cmd("partprobe", item.Partition.Disk.Path)
-				log.Printf("New GPT partition created: %v (%v)\n", item.Path, formatSize((part.LastLBA-part.FirstLBA+1)*item.Partition.Disk.SectorSizeLogical))
-			default:
-				log.Println("Can't create partition in unknown partition table: ", item.Partition.Path, item.Partition.Disk.PartTable)
-			}
+// Is any step failed
+// Завершился ли какой-либо шаг с ошибкой
+func (res doResult) Failed() bool {
+	return res.Err() != nil
+}
 
-		case type_LVM_GROUP:
-			if item.Child != -1 {
-				plan[item.Child].FreeSpace = item.FreeSpace
-			}
-			log.Printf("Free space on LVM_GROUP '%v' %v\n", item.Path, formatSize(item.FreeSpace))
-		case type_LVM_LV:
-		retryLoop2:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				if retry > 0 {
-					log.Println("Try extend LVM LV once more:", item.Path)
-					time.Sleep(time.Second)
-				}
-				cmd("lvresize", "-l", "+100%FREE", item.Path)
-				newSize := lvmLVGetSize(item.Path)
-				addSpace := newSize - item.Size
-				if item.FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
-					continue retryLoop2
-				}
-				log.Printf("Resize LVM_LV %v to %v(+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
-				item.Size = newSize
-				item.FreeSpace = 0
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += addSpace
-				}
-				break retryLoop2
-			}
-		case type_LVM_PV:
-		retryLoop:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				if retry > 0 {
-					log.Println("Try to resize LVM PV once more:", item.Path)
-				}
-				cmd("pvresize", item.Path)
-				newSize := lvmPVGetSize(item.Path)
-				addSpace := newSize - item.Size
-				if plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
-					continue retryLoop
-				}
-				if item.Child != -1 {
-					plan[item.Child].FreeSpace += addSpace
-				}
-				log.Printf("LVM PV Resized: %v to %v (+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
-				item.FreeSpace -= addSpace
-				item.Size = newSize
-				break retryLoop
-			}
-		case type_LVM_PV_ADD:
-			vg := plan[item.Child].Path
-			oldSize, _, _ := lvmVGGetSize(vg)
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				cmd("vgextend", vg, item.Path)
-				newSize, _, _ := lvmVGGetSize(vg)
-				if newSize > oldSize {
-					log.Printf("Add free pv (%v) to vg(%v), new size: %v(+%v)\n", item.Path, vg,
-						formatSize(newSize), formatSize(newSize-oldSize))
-					break
-				}
-			}
-		case type_LVM_PV_NEW:
-			vg := plan[item.Child].Path
-			oldSize, _, _ := lvmVGGetSize(vg)
-		retryLoop3:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				cmd("pvcreate", item.Path)
-				cmd("vgextend", vg, item.Path)
-				newSize, _, _ := lvmVGGetSize(vg) // Yes - create LVM PV, but check size of LVM VG. It is OK.
-				addSpace := newSize - oldSize
-				if plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
-					log.Println("Try extend VG once more: ", vg, item.Path)
-					time.Sleep(time.Second)
-					continue retryLoop3
-				} else {
-					log.Printf("Add PV %v (+%v)\n", item.Path, formatSize(newSize-oldSize))
-					break retryLoop3
-				}
+// Error, if any step failed
+// Ошибка, если какой-либо шаг завершился с ошибкой
+func (res doResult) Err() error {
+	failed := 0
+	for _, step := range res.Steps {
+		if step.Status == step_FAILED || step.Status == step_DEPENDENCY_FAILED {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return doError{fmt.Errorf("%v of %v steps failed or skipped because of failed dependencies", failed, len(res.Steps))}
+}
+
+// Log what was and wasn't done
+// Пишет в лог, что было и что не было выполнено
+func (res doResult) LogSummary() {
+	for i, step := range res.Steps {
+		if step.Err != nil {
+			log.Printf("%v %v: %v %v\n", step.Status, i, step.Item, step.Err)
+		} else {
+			log.Printf("%v %v: %v\n", step.Status, i, step.Item)
+		}
+	}
+}
+
+/*
+Index of step, which the step depends on and which failed. -1 if all dependencies are done.
+Step depends on steps, which extend it (the step is child of them). Create of swap depends on move of the swap.
+
+Индекс шага, от которого зависит шаг и который завершился с ошибкой. -1 если все зависимости выполнены.
+Шаг зависит от шагов, которые его расширяют (этот шаг - их потомок). Создание swap зависит от перемещения swap.
+*/
+func failedDependency(plan []storageItem, steps []stepResult, index int) int {
+	for i := 0; i < index; i++ {
+		if steps[i].Status != step_FAILED && steps[i].Status != step_DEPENDENCY_FAILED {
+			continue
+		}
+		for _, child := range planChildren(plan, i) {
+			if child == index {
+				return i
 			}
+		}
+		if plan[index].Type == type_SWAP_CREATE && plan[i].Type == type_SWAP_MOVE && plan[i].Path == plan[index].Path {
+			return i
+		}
+	}
+	return -1
+}
+
+/*
+Execute plan. Failed step doesn't stop execute, but steps, which depend on it, doesn't run.
+With journal finished steps skipped and execute stops after step, which need reboot.
 
-		case type_FS:
-		retryLoop4:
-			for retry := 0; retry < TRY_COUNT; retry++ {
-				if retry > 0 {
-					log.Println("Sleep a second before retry.")
-					time.Sleep(time.Second)
-				}
-				switch item.FSType {
-				case "ext3", "ext4":
-					res, stderr, _ := cmd("resize2fs", "-f", item.Path)
-					newSize, err := fsGetSizeExt(item.Path)
-					if err != nil {
-						log.Printf("ATTENTION: Can't read new size after fs resize. Log of resize:\nstdout:%v\nstderr:%v\n", res, stderr)
-						continue retryLoop4
-					}
-					addSpace := newSize - item.Size
-					if addSpace == 0 {
-						log.Printf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v\n", res, stderr)
-						continue retryLoop4
-					}
-					item.FreeSpace -= addSpace
-					item.Size = newSize
-					log.Printf("Resize filesystem: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
-					break retryLoop4
-				case "xfs":
-					var tmpMountPoint string
-					var mountPoint string
-					if mountPoint, _ = getMountPoint(item.Path); mountPoint == "" {
-						var err error
-						tmpMountPoint, err = ioutil.TempDir("", "")
-						if err != nil {
-							log.Println("Can't create tmp mount point for xfs.")
-							continue retryLoop4
-						}
-						var errString string
-					xfsMountLoop:
-						for retryXFSMount := 0; retryXFSMount < TRY_COUNT; retryXFSMount++ {
-							if retryXFSMount > 0 {
-								log.Println("Retry mount xfs volume")
-								time.Sleep(time.Second)
-							}
-							if _, errString, err = cmd("mount", "-t", "xfs", item.Path, tmpMountPoint); err == nil {
-								mountPoint = tmpMountPoint
-								break xfsMountLoop
-							}
-							log.Printf("Can't xfs mount: %v (%v) ('%v' -> '%v')", err, errString, item.Path, tmpMountPoint)
-							if retryXFSMount == TRY_COUNT-1 {
-								// При последней попытке - удаляем временную точку монтирования
-								os.Remove(tmpMountPoint)
-								break retryLoop4
-							}
-						}
-					}
+Выполняет план. Шаг с ошибкой не останавливает выполнение, но зависящие от него шаги не выполняются.
+С журналом завершенные шаги пропускаются, а выполнение останавливается после шага, которому нужна перезагрузка.
+*/
+func extendDo(plan []storageItem, options doOptions) (res doResult) {
+	var needReboot bool
+	res.Steps = make([]stepResult, len(plan))
+	result := func() doResult {
+		for i := range plan {
+			res.Steps[i].Item = plan[i]
+		}
+		res.NeedReboot = needReboot
+		return res
+	}
 
-					res, stderr, _ := cmd("xfs_growfs", mountPoint)
-					newSize, err := fsGetSizeXFS(item.Path)
+	// Error of current step
+	// Ошибка текущего шага
+	var stepErr error
+	fail := func(args ...interface{}) {
+		stepErr = stepError(args...)
+		log.Println(stepErr)
+	}
 
-					if tmpMountPoint != "" {
-						cmd("umount", tmpMountPoint)
-						os.Remove(tmpMountPoint)
-					}
+	// Swaps, which disabled while move. They have to be enabled after create.
+	// Разделы подкачки, отключенные при перемещении. Их надо включить после создания.
+	activeSwaps := make(map[string]bool)
+	if options.Journal != nil {
+		activeSwaps = options.Journal.Swaps
+	}
 
-					if err != nil {
-						log.Printf("ATTENTION: Can't read new size after fs resize. Log of resize:\nstdout:%v\nstderr:%v\n", res, stderr)
-						continue retryLoop4
-					}
-					addSpace := newSize - item.Size
-					item.FreeSpace -= addSpace
-					item.Size = newSize
-					if addSpace == 0 {
-						log.Printf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v\n", res, stderr)
-						continue retryLoop4
-					}
-					log.Printf("Resize filesystem: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
-					break retryLoop4
-				default:
-					log.Println("I don't know the filesystem: ", item.Path, item.FSType)
-				}
-			}
-		case type_SKIP:
+	// Finish previous step in journal. Steps finishes on start of next step, so stop before reboot doesn't skip
+	// finish of the last step.
+	// Завершает предыдущий шаг в журнале. Шаги завершаются при старте следующего шага, поэтому остановка перед
+	// перезагрузкой не пропускает завершение последнего шага.
+	prevStep := -1
+	prevNeedReboot := false
+	finishPrevStep := func() (stop bool) {
+		if prevStep == -1 {
+			return false
+		}
+		if stepErr != nil {
+			res.Steps[prevStep].Status = step_FAILED
+			res.Steps[prevStep].Err = stepErr
+			options.Journal.StepFailed(prevStep, plan)
+		} else {
+			res.Steps[prevStep].Status = step_DONE
+			stop = options.Journal.StepFinish(prevStep, plan, needReboot && !prevNeedReboot)
+		}
+		prevStep = -1
+		stepErr = nil
+		return stop
+	}
+
+	for i := range plan {
+		if finishPrevStep() {
+			log.Println("Stop before reboot. Run with --resume after reboot for continue.")
+			return result()
+		}
+		if options.Journal.IsDone(i) {
+			res.Steps[i].Status = step_DONE
+			continue
+		}
+		if dep := failedDependency(plan, res.Steps, i); dep != -1 {
+			log.Printf("Skip step %v: step %v, which it depends on, failed: %v\n", i, dep, plan[i])
+			res.Steps[i].Status = step_DEPENDENCY_FAILED
+			res.Steps[i].Err = fmt.Errorf("Step %v, which the step depends on, failed", dep)
+			continue
+		}
+		options.Journal.StepStart(i, plan)
+		prevStep, prevNeedReboot = i, needReboot
+
+		log.Println("DO ", strconv.Itoa(i)+":", plan[i])
+		item := &plan[i]
+
+		// Don't extend more, then need for target size
+		// Не расширяем больше, чем нужно для целевого размера
+		if item.Limit != 0 && item.Size+item.FreeSpace > item.Limit {
+			item.FreeSpace = item.Limit - item.Size
+		}
+
+		l := layerOf(*item)
+		switch {
+		case item.Type == type_SKIP:
 			log.Println("Skip item:", item.SkipReason, item.OldType, item.Path, formatSize(item.Size))
-		case type_UNKNOWN:
-			log.Println("Unknown item type:", item.Type, item.Path)
+		case item.Type == type_UNKNOWN:
+			fail("Unknown item type:", item.Type, item.Path)
+		case l == nil:
+			fail("I don't know way to resize type: ", item.Type, item.FSType)
 		default:
-			log.Println("I don't know way to resize type: ", item.Type)
+			step := layerStep{Plan: plan, Index: i, Options: options, ActiveSwaps: activeSwaps}
+			if err := l.Apply(&step); err != nil {
+				log.Println(err)
+				stepErr = err
+			}
+			if step.NeedReboot {
+				needReboot = true
+			}
 		}
 	}
-	return needReboot
+	finishPrevStep()
+	options.Journal.Finish(plan)
+	return result()
 }
@@ -0,0 +1,184 @@
+package fsextender
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+)
+
+// Version of json plan schema. Increase it on incompatible changes of the schema.
+// Версия json схемы плана. Увеличивается при несовместимых изменениях схемы.
+const planJSON_VERSION = 1
+
+// Plan in machine-readable format
+// План в машиночитаемом формате
+type planJSON struct {
+	Version    int            `json:"version"`
+	StartPoint string         `json:"start_point"`
+	Target     planJSONTarget `json:"target"`
+	Steps      []planJSONStep `json:"steps"`
+}
+
+// Totals for target of extend (storage[0] of scan)
+// Итоги для цели расширения (storage[0] при сканировании)
+type planJSONTarget struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Size    uint64 `json:"size"`     // Bytes, current size. Текущий размер в байтах
+	Grow    uint64 `json:"grow"`     // Bytes, planned growth. Планируемый рост в байтах
+	NewSize uint64 `json:"new_size"` // Bytes, size after extend. Размер после расширения в байтах
+}
+
+type planJSONStep struct {
+	Index         int                `json:"index"`
+	Type          string             `json:"type"`
+	Path          string             `json:"path"`
+	Size          uint64             `json:"size"`       // Bytes, current size. Текущий размер в байтах
+	FreeSpace     uint64             `json:"free_space"` // Bytes, own free space of item. Собственное свободное место элемента
+	Grow          uint64             `json:"grow"`       // Bytes, own free space with growth of underliing items. Свободное место с учетом роста нижележащих элементов
+	Child         int                `json:"child"`      // Index of step, which use the growth. -1 - none. Индекс шага, использующего рост. -1 - нет
+	FSType        string             `json:"fs_type,omitempty"`
+	Partition     *planJSONPartition `json:"partition,omitempty"`
+	LVMExtentSize uint64             `json:"lvm_extent_size,omitempty"`
+	Limit         uint64             `json:"limit,omitempty"`       // Bytes, max size after extend. Максимальный размер после расширения в байтах
+	LVMReserve    uint64             `json:"lvm_reserve,omitempty"` // Bytes, reserved free space of LVM group. Зарезервированное свободное место группы LVM в байтах
+	SkipReason    string             `json:"skip_reason,omitempty"`
+	SkippedType   string             `json:"skipped_type,omitempty"`
+}
+
+type planJSONPartition struct {
+	Table     string `json:"table"`
+	Disk      string `json:"disk"`
+	Number    uint32 `json:"number"`
+	FirstByte uint64 `json:"first_byte"`
+	LastByte  uint64 `json:"last_byte"`
+	Logical   bool   `json:"logical,omitempty"`
+}
+
+// Stable name of type for external usage: type_PARTITION_NEW -> partition_new
+// Стабильное имя типа для внешнего использования: type_PARTITION_NEW -> partition_new
+func storageItemTypeName(t storageItemType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "type_"))
+}
+
+func extendPrintJSON(w io.Writer, startPoint string, plan []storageItem) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(makePlanJSON(startPoint, plan))
+}
+
+func makePlanJSON(startPoint string, plan []storageItem) planJSON {
+	grow := planGrowth(plan)
+	res := planJSON{Version: planJSON_VERSION, StartPoint: startPoint, Steps: make([]planJSONStep, 0, len(plan))}
+	for i, item := range plan {
+		step := planJSONStep{
+			Index:         i,
+			Type:          storageItemTypeName(item.Type),
+			Path:          item.Path,
+			Size:          item.Size,
+			FreeSpace:     item.FreeSpace,
+			Grow:          grow[i],
+			Child:         item.Child,
+			FSType:        item.FSType,
+			LVMExtentSize: item.LVMExtentSize,
+			Limit:         item.Limit,
+			LVMReserve:    item.LVMReserve,
+		}
+		if item.Partition.Disk != nil {
+			step.Partition = &planJSONPartition{
+				Table:     item.Partition.Disk.PartTable,
+				Disk:      item.Partition.Disk.Path,
+				Number:    item.Partition.Number,
+				FirstByte: item.Partition.FirstByte,
+				LastByte:  item.Partition.LastByte,
+				Logical:   item.Partition.Logical,
+			}
+		}
+		if item.Type == type_SKIP {
+			step.SkipReason = item.SkipReason
+			step.SkippedType = storageItemTypeName(item.OldType)
+		}
+		res.Steps = append(res.Steps, step)
+
+	}
+	if targetIndex := planTarget(plan); targetIndex != -1 {
+		target := plan[targetIndex]
+		res.Target = planJSONTarget{Path: target.Path, Type: storageItemTypeName(target.Type), Size: target.Size,
+			Grow: grow[targetIndex], NewSize: target.Size + grow[targetIndex]}
+	}
+	return res
+}
+
+// Save plan to file in json format
+// Сохраняет план в файл в формате json
+func writePlanFile(path string, startPoint string, plan []storageItem) error {
+	content, err := json.MarshalIndent(makePlanJSON(startPoint, plan), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(content, '\n'), 0600)
+}
+
+// Read plan, saved by writePlanFile
+// Читает план, сохраненный writePlanFile
+func readPlanFile(path string) (res planJSON, err error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return res, err
+	}
+	if err = json.Unmarshal(content, &res); err != nil {
+		return res, fmt.Errorf("Can't parse plan file %v: %v", path, err)
+	}
+	if res.Version != planJSON_VERSION {
+		return res, fmt.Errorf("Unsupported version of plan file %v: %v (need %v)", path, res.Version, planJSON_VERSION)
+	}
+	return res, nil
+}
+
+/*
+Differences between saved and current plan: steps, device sizes, partition boundaries, LVM extent sizes.
+Empty result - plans are same.
+
+Различия между сохраненным и текущим планом: шаги, размеры устройств, границы разделов, размеры экстентов LVM.
+Пустой результат - планы совпадают.
+*/
+func planDiff(saved, current planJSON) (diffs []string) {
+	if saved.StartPoint != current.StartPoint {
+		diffs = append(diffs, fmt.Sprintf("start point: %v -> %v", saved.StartPoint, current.StartPoint))
+	}
+	if len(saved.Steps) != len(current.Steps) {
+		return append(diffs, fmt.Sprintf("count of steps: %v -> %v", len(saved.Steps), len(current.Steps)))
+	}
+	for i := range saved.Steps {
+		savedStep, currentStep := saved.Steps[i], current.Steps[i]
+		check := func(field string, savedValue, currentValue interface{}) {
+			if !reflect.DeepEqual(savedValue, currentValue) {
+				diffs = append(diffs, fmt.Sprintf("step %v (%v): %v: %v -> %v", i, savedStep.Path, field,
+					savedValue, currentValue))
+			}
+		}
+		check("type", savedStep.Type, currentStep.Type)
+		check("skipped type", savedStep.SkippedType, currentStep.SkippedType)
+		check("path", savedStep.Path, currentStep.Path)
+		check("size", savedStep.Size, currentStep.Size)
+		check("free space", savedStep.FreeSpace, currentStep.FreeSpace)
+		check("grow", savedStep.Grow, currentStep.Grow)
+		check("child", savedStep.Child, currentStep.Child)
+		check("fs type", savedStep.FSType, currentStep.FSType)
+		check("lvm extent size", savedStep.LVMExtentSize, currentStep.LVMExtentSize)
+		check("limit", savedStep.Limit, currentStep.Limit)
+		check("lvm reserve", savedStep.LVMReserve, currentStep.LVMReserve)
+		var savedPartition, currentPartition planJSONPartition
+		if savedStep.Partition != nil {
+			savedPartition = *savedStep.Partition
+		}
+		if currentStep.Partition != nil {
+			currentPartition = *currentStep.Partition
+		}
+		check("partition", savedPartition, currentPartition)
+	}
+	return diffs
+}
@@ -18,21 +18,34 @@ func expandFilter(storage []storageItem, filter string) string {
 	for _, part := range strings.Split(filter, ",") {
 		if part == FILTER_LVM_ALREADY_PLACED {
 			for vgIndex, vg := range storage {
-				if vg.Type != type_LVM_GROUP {
+				isBtrfs := vg.Type == type_FS && vg.FSType == "btrfs"
+				if vg.Type != type_LVM_GROUP && !isBtrfs {
 					continue
 				}
 
+				// Disks with PV of LVM group or with devices of btrfs
+				// Диски с PV группы LVM или с устройствами btrfs
 				for partIndex := vgIndex + 1; partIndex < len(storage); partIndex++ {
 					part := storage[partIndex]
-					if part.Child != vgIndex || part.Type != type_LVM_PV {
+					if part.Child != vgIndex {
 						continue
 					}
-					diskPath, _, err := extractPartNumber(part.Path)
+					if !(part.Type == type_LVM_PV || isBtrfs && (part.Type == type_PARTITION || part.Type == type_DISK)) {
+						continue
+					}
+					diskPath, err := deviceDisk(part.Path)
 					if err != nil {
 						log.Println("Can't extract disk path.", part.Type, part.Path, err)
 						continue
 					}
 					express := "^" + diskPath + "[^/]*$"
+					if last := diskPath[len(diskPath)-1]; last >= '0' && last <= '9' {
+						// Disk name ends with digit, for example /dev/nvme0n1. Partitions of it: /dev/nvme0n1p1
+						// and doesn't match other disks, for example /dev/nvme0n11.
+						// Имя диска заканчивается цифрой, например /dev/nvme0n1. Разделы такого диска: /dev/nvme0n1p1,
+						// при этом не должны подходить другие диски, например /dev/nvme0n11.
+						express = "^" + diskPath + "(p[0-9]+)?$"
+					}
 					expressions[express] = true
 				}
 			}
@@ -55,6 +68,20 @@ func expandFilter(storage []storageItem, filter string) string {
 	return strings.Join(res, "|")
 }
 
+// Size of alignment for limited growth of partitions (in bytes).
+// Размер выравнивания для ограниченного роста разделов (в байтах).
+const size_PARTITION_ALIGN = 1024 * 1024
+
+// Options of make plan
+// Параметры построения плана
+type planOptions struct {
+	Filter         string      // Filter of block devices, see expandFilter. Фильтр блочных устройств, см. expandFilter
+	MoveSwap       bool        // Allow move swap partition to end of disk for extend previous partition. Разрешить перемещение раздела подкачки в конец диска для расширения предыдущего раздела
+	BtrfsAddDevice bool        // Allow add new partitions to btrfs filesystem as new devices. Разрешить добавлять новые разделы в btrfs как новые устройства
+	Size           sizeTarget  // Target size of extend. Zero value - max. Целевой размер расширения. Нулевое значение - максимальный
+	VGReserve      sizeReserve // Free space of LVM volume group, which doesn't use for extend LV. Свободное место группы томов LVM, не используемое для расширения LV
+}
+
 /*
 storage - description of storages hierarhy and ways of extend them. storage[0] - top of hierarchy, target of extend.
 storage can be modify while work the function. You have to store copy of them if you need previous state.
@@ -62,11 +89,11 @@ storage can be modify while work the function. You have to store copy of them if
 storage - описание иерархии и возможных путей расширения раздела. storage[0] - вершина, целевая точка расширения.
 в процессе работы функции storage может портиться. Если важно его сохранение нужно сохранить у себя копию.
 */
-func extendPlan(storage []storageItem, filter string) (plan []storageItem, err error) {
-	filter = expandFilter(storage, filter)
+func extendPlan(storage []storageItem, options planOptions) (plan []storageItem, err error) {
+	filter := expandFilter(storage, options.Filter)
 	filterRE, err := regexp.Compile(filter)
 	if err != nil {
-		err = errors.New("Error while compile filter regexp: " + err.Error())
+		err = usageError{errors.New("Error while compile filter regexp: " + err.Error())}
 		return nil, err
 	}
 
@@ -77,7 +104,7 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 	for i := range storage {
 		item := &storage[i]
 		switch item.Type {
-		case type_PARTITION, type_PARTITION_NEW, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW:
+		case type_PARTITION, type_PARTITION_NEW, type_PARTITION_EXTENDED, type_SWAP_MOVE, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW:
 			if !filterRE.MatchString(item.Path) {
 				item.OldType = item.Type
 				item.Type = type_SKIP
@@ -86,6 +113,70 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 		}
 	}
 
+	/*
+		Move swap partition only if it allowed. Create swap only if it moved.
+		Перемещаем раздел подкачки только если это разрешено. Создаём swap только если он перемещён.
+	*/
+	for i := range storage {
+		item := &storage[i]
+		if !options.MoveSwap && item.Type == type_SWAP_MOVE {
+			item.OldType = item.Type
+			item.Type = type_SKIP
+			item.SkipReason = "Move swap disabled. Use --move-swap for enable it."
+		}
+		if item.Type != type_SKIP || item.OldType != type_SWAP_MOVE {
+			continue
+		}
+		item.FreeSpace = 0
+		for createI := range storage {
+			if storage[createI].Type == type_SWAP_CREATE && storage[createI].Path == item.Path {
+				storage[createI].Type = type_UNKNOWN
+			}
+		}
+	}
+
+	/*
+		Add device to btrfs only if it allowed and new partition for the device isn't skipped.
+		Добавляем устройство в btrfs только если это разрешено и новый раздел для устройства не пропущен.
+	*/
+	for i := range storage {
+		item := &storage[i]
+		if item.Type != type_BTRFS_DEVICE_NEW {
+			continue
+		}
+		if !options.BtrfsAddDevice {
+			item.OldType = item.Type
+			item.Type = type_SKIP
+			item.SkipReason = "Add device to btrfs disabled. Use --btrfs-add-device for enable it."
+		}
+		for partI := range storage {
+			part := &storage[partI]
+			if part.Child != i || part.Type != type_PARTITION_NEW && part.OldType != type_PARTITION_NEW {
+				continue
+			}
+			if item.Type == type_SKIP && part.Type != type_SKIP {
+				part.OldType = part.Type
+				part.Type = type_SKIP
+				part.SkipReason = item.SkipReason
+			}
+			if part.Type == type_SKIP && item.Type != type_SKIP {
+				item.OldType = item.Type
+				item.Type = type_SKIP
+				item.SkipReason = part.SkipReason
+			}
+		}
+	}
+
+	/*
+		Layers, which fix free space of items after filters. For example members of RAID grow by same size.
+		Слои, которые поправляют свободное место элементов после фильтров. Например участники RAID растут на одинаковый размер.
+	*/
+	for i := range storage {
+		if planner, ok := layerOf(storage[i]).(layerPlanner); ok {
+			planner.Plan(storage, i)
+		}
+	}
+
 	/*
 		When it can create new partition or extend current partition - always select extend.
 		Если есть возможность расширить существующий раздел и создать новый на этом же месте - выбираем расширение
@@ -98,7 +189,7 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 
 		// For every partition, what can be extended
 		// Для каждого раздела, который возможно расширить
-		if item.Type == type_PARTITION && item.FreeSpace > 0 {
+		if (item.Type == type_PARTITION || item.Type == type_PARTITION_EXTENDED || item.Type == type_SWAP_MOVE) && item.FreeSpace > 0 {
 			// Find create partitions plan, which overlap with item and cancel create them.
 			// ищем предположительно создаваемые разделы, которые пересекаются с расширением и отменяем их создание
 			// - на данный момент предпочитаем расширение текущего раздела созданию нового
@@ -118,9 +209,9 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 					continue
 				}
 
-				// Cancel create LVM PV for cancelled partition
-				// Отменяем создание LVM PV на этом томе
-				if newItem.Child != -1 && storage[newItem.Child].Type == type_LVM_PV_NEW {
+				// Cancel create LVM PV or add btrfs device for cancelled partition
+				// Отменяем создание LVM PV или добавление устройства btrfs на этом томе
+				if newItem.Child != -1 && (storage[newItem.Child].Type == type_LVM_PV_NEW || storage[newItem.Child].Type == type_BTRFS_DEVICE_NEW) {
 					storage[newItem.Child].OldType = storage[newItem.Child].Type
 					storage[newItem.Child].Type = type_SKIP
 					storage[newItem.Child].SkipReason = "Partition layout optimization. Partition number may be wrong becouse it optimize too."
@@ -142,7 +233,7 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 					part := &fixPartNumbersItem.Partition
 					if fixPartNumbersItem.Type != type_PARTITION_NEW ||
 						part.Disk.Major != diskMajor || part.Disk.Minor != diskMinor ||
-						part.Number <= prevNum {
+						part.Logical != newItem.Partition.Logical || part.Number <= prevNum {
 						continue
 					}
 
@@ -172,13 +263,24 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 	planMap[-1] = -1
 	for i := len(storage) - 1; i >= 0; i-- {
 		item := storage[i]
-		if item.Type == type_UNKNOWN {
+		if item.Type == type_UNKNOWN || item.Type == type_SWAP_CREATE {
 			continue
 		}
 		planMap[i] = len(plan)
 		plan = append(plan, item)
 	}
 
+	// Create swap after all other steps - kernel can see moved swap partition only after reread partition table, while
+	// extend previous partition.
+	// Создаём swap после всех остальных шагов - ядро может увидеть перемещённый раздел подкачки только после перечитывания
+	// таблицы разделов, при расширении предыдущего раздела.
+	for i := len(storage) - 1; i >= 0; i-- {
+		if storage[i].Type == type_SWAP_CREATE {
+			planMap[i] = len(plan)
+			plan = append(plan, storage[i])
+		}
+	}
+
 	// Can be placed other optimizations here.
 	// Тут в будущем возможны какие-то оптимизации, например чтобы сократить количество ребутов если их надо несколько.
 
@@ -188,9 +290,309 @@ func extendPlan(storage []storageItem, filter string) (plan []storageItem, err e
 		item := &plan[i]
 		item.Child = planMap[item.Child]
 	}
+
+	if options.VGReserve != (sizeReserve{}) {
+		planReserveVG(plan, options.VGReserve)
+	}
+
+	if !options.Size.IsMax() {
+		if err = planLimitSize(plan, options.Size); err != nil {
+			return nil, planError{err}
+		}
+	}
 	return plan, nil
 }
 
+/*
+Reserve free space in LVM volume groups. Percent of reserve calculated from size of group after extend.
+Резервирует свободное место в группах томов LVM. Процент резерва считается от размера группы после расширения.
+*/
+func planReserveVG(plan []storageItem, reserve sizeReserve) {
+	grow := planGrowth(plan)
+	for i := range plan {
+		item := &plan[i]
+		if item.Type != type_LVM_GROUP {
+			continue
+		}
+		newSize := item.Size + grow[i] - item.FreeSpace
+		item.LVMReserve = alignUp(reserve.Bytes(newSize), item.LVMExtentSize)
+		if grow[i] > item.LVMReserve {
+			item.Limit = item.Size + grow[i] - item.LVMReserve
+		} else {
+			item.Limit = item.Size
+		}
+	}
+}
+
+// Index of target of extend in plan. -1 if plan hasn't target.
+// Индекс цели расширения в плане. -1 если в плане нет цели.
+func planTarget(plan []storageItem) int {
+	for i, item := range plan {
+		if item.Child == -1 && item.Type != type_SKIP && item.Type != type_SWAP_CREATE {
+			return i
+		}
+	}
+	return -1
+}
+
+/*
+Limit growth of plan by target size. Use only growth, which need for reach the target. Free space of layer, which
+nearer to target, uses first. Existed devices uses before new.
+
+Ограничивает рост по плану целевым размером. Используется только рост, необходимый для достижения цели. Свободное
+место слоя, который ближе к цели, используется в первую очередь. Существующие устройства используются раньше новых.
+*/
+func planLimitSize(plan []storageItem, target sizeTarget) error {
+	targetIndex := planTarget(plan)
+	if targetIndex == -1 {
+		return errors.New("Can't find target of extend in plan")
+	}
+	grow := planGrowth(plan)
+	need, err := target.Need(plan[targetIndex].Size, grow[targetIndex])
+	if err != nil {
+		return err
+	}
+	if need == 0 {
+		// Target size reached already
+		// Целевой размер уже достигнут
+		log.Printf("Size of %v is %v, it doesn't need extend to %v\n", plan[targetIndex].Path,
+			formatSize(plan[targetIndex].Size), target)
+		for _, parent := range planParents(plan, targetIndex) {
+			planCancel(plan, parent, "Not needed for target size.")
+		}
+		plan[targetIndex].FreeSpace = 0
+		plan[targetIndex].Limit = plan[targetIndex].Size
+		return nil
+	}
+	planAllocate(plan, grow, targetIndex, need)
+	return nil
+}
+
+/*
+Allocate growth for plan[index] from own free space of item, then from underliing items.
+grow - growth of items before limit (by planGrowth).
+Return growth, which provided by the item. It can be more then need, if the item can't be extended partly.
+
+Выделяет рост для plan[index] из собственного свободного места элемента, затем из нижележащих элементов.
+grow - рост элементов до ограничения (из planGrowth).
+Возвращает рост, предоставленный элементом. Он может быть больше need, если элемент нельзя расширить частично.
+*/
+func planAllocate(plan []storageItem, grow []uint64, index int, need uint64) (provided uint64) {
+	item := &plan[index]
+	if need == 0 {
+		planCancel(plan, index, "Not needed for target size.")
+		return 0
+	}
+
+	// Align need, else underliing layers can't provide it.
+	// Выравниваем need, иначе нижележащие слои не смогут его предоставить.
+	switch item.Type {
+	case type_PARTITION, type_PARTITION_NEW, type_PARTITION_EXTENDED:
+		need = alignUp(need, size_PARTITION_ALIGN)
+	case type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW:
+		need = alignUp(need, item.LVMExtentSize)
+	case type_LVM_LV:
+		for _, parent := range planParents(plan, index) {
+			if plan[parent].Type == type_LVM_GROUP {
+				need = alignUp(need, plan[parent].LVMExtentSize)
+			}
+		}
+	case type_LVM_GROUP:
+		// Reserved space have to stay free, underliing layers have to provide it too.
+		// Зарезервированное место должно остаться свободным, нижележащие слои тоже должны его предоставить.
+		need += item.LVMReserve
+	case type_RAID:
+		return planAllocateRaid(plan, grow, index, need)
+	}
+
+	// Own free space of the types can be used partly
+	// Собственное свободное место этих типов может быть использовано частично
+	switch item.Type {
+	case type_PARTITION, type_PARTITION_NEW, type_PARTITION_EXTENDED, type_LVM_GROUP, type_FS:
+		if item.FreeSpace > need {
+			item.FreeSpace = need
+		}
+		if item.Type == type_PARTITION_NEW {
+			item.Partition.LastByte = item.Partition.FirstByte + item.FreeSpace - 1
+		}
+	}
+
+	provided = item.FreeSpace
+	for _, parent := range planParents(plan, index) {
+		if provided >= need {
+			planCancel(plan, parent, "Not needed for target size.")
+			continue
+		}
+		provided += planAllocate(plan, grow, parent, need-provided)
+	}
+
+	// Limit size of items, which can get more from underliing layers, then need.
+	// Ограничиваем размер элементов, которые могут получить от нижележащих слоёв больше, чем нужно.
+	switch item.Type {
+	case type_PARTITION, type_PARTITION_EXTENDED, type_LVM_LV, type_FS:
+		item.Limit = item.Size + minUint64(provided, need)
+	case type_LVM_GROUP:
+		item.Limit = item.Size
+		if used := minUint64(provided, need); used > item.LVMReserve {
+			item.Limit += used - item.LVMReserve
+		}
+		if provided < item.LVMReserve {
+			return 0
+		}
+		return provided - item.LVMReserve
+	}
+	return provided
+}
+
+/*
+Members of RAID grow by same size, they can't be limited separately. Allocate same growth from every member, which
+enough for need growth of array, and limit the array by its size with the members.
+
+Участники RAID растут на одинаковый размер, их нельзя ограничить по отдельности. Выделяет одинаковый рост на каждом
+участнике, достаточный для нужного роста массива, и ограничивает массив его размером с этими участниками.
+*/
+func planAllocateRaid(plan []storageItem, grow []uint64, index int, need uint64) (provided uint64) {
+	item := &plan[index]
+	component, err := item.Raid.ComponentSizeFor(item.Size + need)
+	if err != nil {
+		log.Printf("Can't limit growth of RAID: %v (%v)\n", item.Path, err)
+		return grow[index]
+	}
+	var memberNeed uint64
+	if component > item.Raid.ComponentSize {
+		memberNeed = alignUp(component-item.Raid.ComponentSize, size_PARTITION_ALIGN)
+	}
+	memberGrow := memberNeed
+	for _, parent := range planParents(plan, index) {
+		memberGrow = minUint64(memberGrow, planAllocate(plan, grow, parent, memberNeed))
+	}
+
+	item.FreeSpace = 0
+	newSize, _ := item.Raid.ArraySize(item.Raid.ComponentSize + memberGrow)
+	if newSize > item.Size {
+		item.FreeSpace = newSize - item.Size
+	}
+	item.Limit = item.Size + item.FreeSpace
+	return item.FreeSpace
+}
+
+/*
+Is plan extend anything: any not skipped item has free space (and it isn't limited by target size) or create new
+device.
+
+Расширяет ли план что-нибудь: у какого-либо не пропущенного элемента есть свободное место (и оно не ограничено
+целевым размером) или создаётся новое устройство.
+*/
+func planHasGrowth(plan []storageItem) bool {
+	for _, item := range plan {
+		switch item.Type {
+		case type_SKIP, type_UNKNOWN, type_SWAP_CREATE:
+			continue
+		case type_PARTITION_NEW, type_LVM_PV_ADD, type_LVM_PV_NEW, type_BTRFS_DEVICE_NEW:
+			return true
+		}
+		if item.FreeSpace > 0 && (item.Limit == 0 || item.Limit > item.Size) {
+			return true
+		}
+	}
+	return false
+}
+
+// Indexes of items of plan, which extend plan[index]. Existed devices first, new devices after them.
+// Индексы элементов плана, которые расширяют plan[index]. Сначала существующие устройства, после них - новые.
+func planParents(plan []storageItem, index int) (parents []int) {
+	var newParents []int
+	for i, item := range plan {
+		if item.Child != index || item.Type == type_SKIP || item.Type == type_UNKNOWN {
+			continue
+		}
+		switch item.Type {
+		case type_PARTITION_NEW, type_LVM_PV_ADD, type_LVM_PV_NEW, type_BTRFS_DEVICE_NEW:
+			newParents = append(newParents, i)
+		default:
+			parents = append(parents, i)
+		}
+	}
+	return append(parents, newParents...)
+}
+
+// Skip plan[index] and all underliing items.
+// Пропустить plan[index] и все нижележащие элементы.
+func planCancel(plan []storageItem, index int, reason string) {
+	item := &plan[index]
+	if item.Type == type_SKIP || item.Type == type_UNKNOWN {
+		return
+	}
+	if item.Type == type_SWAP_MOVE {
+		for i := range plan {
+			if plan[i].Type == type_SWAP_CREATE && plan[i].Path == item.Path {
+				planCancel(plan, i, reason)
+			}
+		}
+	}
+	item.OldType = item.Type
+	item.Type = type_SKIP
+	item.SkipReason = reason
+	item.FreeSpace = 0
+	for i := range plan {
+		if plan[i].Child == index {
+			planCancel(plan, i, reason)
+		}
+	}
+}
+
+func alignUp(num, align uint64) uint64 {
+	if align == 0 || num%align == 0 {
+		return num
+	}
+	return num - num%align + align
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Set same free space for every member of RAID - minimal of them. Calc free space of RAID by the free space.
+// Members, which can't be extended (whole disks, skipped partitions) doesn't allow extend RAID.
+// Устанавливает одинаковое свободное место для всех участников RAID - минимальное из них. Вычисляет свободное место
+// RAID по этому значению. Участники, которые нельзя расширить (диски целиком, пропущенные разделы) не дают расширить RAID.
+func raidPlanMembers(storage []storageItem, raidIndex int) {
+	raid := &storage[raidIndex]
+	var members []int
+	var grow uint64
+	for i := range storage {
+		if storage[i].Child != raidIndex {
+			continue
+		}
+		if storage[i].Type != type_PARTITION {
+			grow = 0
+		} else if len(members) == 0 || storage[i].FreeSpace < grow {
+			grow = storage[i].FreeSpace
+		}
+		members = append(members, i)
+	}
+
+	// Degraded array or member without partition
+	// Деградированный массив или участник не является разделом
+	if uint64(len(members)) != raid.Raid.Disks {
+		grow = 0
+	}
+	for _, i := range members {
+		if storage[i].Type == type_PARTITION {
+			storage[i].FreeSpace = grow
+		}
+	}
+
+	raid.FreeSpace = 0
+	newSize, err := raid.Raid.ArraySize(raid.Raid.ComponentSize + grow)
+	if err == nil && newSize > raid.Size {
+		raid.FreeSpace = newSize - raid.Size
+	}
+}
+
 func formatUInt(num uint64) string {
 	return strconv.FormatUint(num, 10)
 }
@@ -2,13 +2,25 @@ package fsextender
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rekby/gpt"
+	"github.com/rekby/mbr"
+	"github.com/rekby/pretty"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
+	"unsafe"
 )
 
 func TestDocumentationActual(t *testing.T) {
@@ -222,3 +234,2027 @@ func TestSortPartitionsByFirstByte(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestReadLogicalPartitions(t *testing.T) {
+	const sectorSize = 512
+	image := make([]byte, 4096*sectorSize)
+
+	writeTable := func(lba uint64, entries ...[3]uint32) {
+		table, _ := mbr.Read(bytes.NewReader(make([]byte, sectorSize)))
+		table.FixSignature()
+		for i, entry := range entries {
+			part := table.GetPartition(i + 1)
+			part.SetType(mbr.PartitionType(entry[0]))
+			part.SetLBAStart(entry[1])
+			part.SetLBALen(entry[2])
+		}
+		buf := &bytes.Buffer{}
+		table.Write(buf)
+		copy(image[lba*sectorSize:], buf.Bytes())
+	}
+
+	// Extended partition from sector 1000 to 3999. Logical partitions: 5 (1100-1999), 6 (2100-2999)
+	writeTable(1000, [3]uint32{uint32(mbr.PART_LVM), 100, 900}, [3]uint32{uint32(mbr_PART_EXTENDED), 1000, 2000})
+	writeTable(2000, [3]uint32{uint32(mbr.PART_LVM), 100, 900})
+
+	disk := &diskInfo{Path: "/dev/sda", SectorSizeLogical: sectorSize}
+	extended := partition{Disk: disk, Number: 2, FirstByte: 1000 * sectorSize, LastByte: 4000*sectorSize - 1}
+	res, err := readLogicalPartitions(bytes.NewReader(image), disk, extended)
+	if err != nil {
+		t.Fatal(err)
+	}
+	need := []partition{
+		{Disk: disk, Path: "/dev/sda5", Number: 5, FirstByte: 1100 * sectorSize, LastByte: 2000*sectorSize - 1, Logical: true, EBRByte: 1000 * sectorSize},
+		{Disk: disk, Path: "/dev/sda6", Number: 6, FirstByte: 2100 * sectorSize, LastByte: 3000*sectorSize - 1, Logical: true, EBRByte: 2000 * sectorSize},
+	}
+	if diff := pretty.Diff(res, need); diff != nil {
+		t.Error(diff)
+	}
+
+	res, err = addFreeSpacePartitions(disk, res, extended.FirstByte, extended.LastByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 || !res[2].IsFreeSpace() || res[2].FirstByte != 3000*sectorSize || res[2].LastByte != extended.LastByte {
+		t.Error(res)
+	}
+
+	// Order of EBR chain differs from order on disk: 5 (3100-3899), 6 (2100-2999). Extended partition grows with 5.
+	// Порядок цепочки EBR отличается от порядка на диске: 5 (3100-3899), 6 (2100-2999). С расширенным разделом растёт 5.
+	writeTable(1000, [3]uint32{uint32(mbr.PART_LVM), 2100, 800}, [3]uint32{uint32(mbr_PART_EXTENDED), 1000, 2000})
+	writeTable(2000, [3]uint32{uint32(mbr.PART_LVM), 100, 900})
+	disk.LogicalPartitions, err = readLogicalPartitions(bytes.NewReader(image), disk, extended)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last, ok := disk.lastPlacedLogicalPartition(); !ok || last.Number != 5 || last.LastByte != 3900*sectorSize-1 {
+		t.Error(last, ok)
+	}
+	if last, ok := disk.lastLogicalPartition(); !ok || last.Number != 6 {
+		t.Error(last, ok)
+	}
+
+	// Loop in EBR chain
+	writeTable(2000, [3]uint32{uint32(mbr.PART_LVM), 100, 900}, [3]uint32{uint32(mbr_PART_EXTENDED), 1000, 1000})
+	_, err = readLogicalPartitions(bytes.NewReader(image), disk, extended)
+	if err == nil {
+		t.Error("Loop in EBR chain must be detected")
+	}
+}
+
+func TestSwapNewPlace(t *testing.T) {
+	const MB = 1024 * 1024
+	const GB = 1024 * MB
+	swap := partition{Number: 2, FirstByte: 10 * GB, LastByte: 12*GB - 1}
+
+	newSwap, ok := swapNewPlace(swap, 100*GB-1)
+	if !ok || newSwap.FirstByte != 98*GB || newSwap.LastByte != 100*GB-1 || newSwap.Number != 2 {
+		t.Error(newSwap, ok)
+	}
+
+	// Align to MB
+	newSwap, ok = swapNewPlace(swap, 100*GB-MB/2-1)
+	if !ok || newSwap.FirstByte != 98*GB-MB || newSwap.Size() != swap.Size() {
+		t.Error(newSwap, ok)
+	}
+
+	// Too small free space
+	_, ok = swapNewPlace(swap, 12*GB+MB-1)
+	if ok {
+		t.Error("Swap mustn't be moved for small free space")
+	}
+}
+
+func TestParseMountInfo(t *testing.T) {
+	mountInfoBytes := []byte(`20 1 8:1 / / rw,relatime shared:1 - ext4 /dev/root rw,errors=remount-ro
+21 20 0:5 / /dev rw,nosuid shared:2 - devtmpfs udev rw
+30 20 253:0 / /mnt/my\040disk rw,relatime shared:10 - xfs /dev/mapper/vg-data rw,logdev=/dev/sdb1
+31 30 8:17 / /mnt/my\040disk rw,relatime shared:11 master:3 - ext4 UUID=123 rw
+
+bad line
+`)
+	log.SetOutput(&bytes.Buffer{})
+	mounts := parseMountInfo(mountInfoBytes)
+	log.SetOutput(os.Stderr)
+
+	need := []mountInfo{
+		{MountID: 20, ParentID: 1, Major: 8, Minor: 1, Root: "/", MountPoint: "/", FSType: "ext4", Source: "/dev/root", Options: "rw,errors=remount-ro"},
+		{MountID: 21, ParentID: 20, Major: 0, Minor: 5, Root: "/", MountPoint: "/dev", FSType: "devtmpfs", Source: "udev", Options: "rw"},
+		{MountID: 30, ParentID: 20, Major: 253, Minor: 0, Root: "/", MountPoint: "/mnt/my disk", FSType: "xfs", Source: "/dev/mapper/vg-data", Options: "rw,logdev=/dev/sdb1"},
+		{MountID: 31, ParentID: 30, Major: 8, Minor: 17, Root: "/", MountPoint: "/mnt/my disk", FSType: "ext4", Source: "UUID=123", Options: "rw"},
+	}
+	if diff := pretty.Diff(mounts, need); diff != nil {
+		t.Fatal(diff)
+	}
+
+	if logdev := mounts[2].Option("logdev"); logdev != "/dev/sdb1" {
+		t.Error(logdev)
+	}
+	if rtdev := mounts[2].Option("rtdev"); rtdev != "" {
+		t.Error(rtdev)
+	}
+
+	// Over-mounted path
+	if mount, ok := findMountByPath(mounts, "/mnt/my disk"); !ok || mount.MountID != 31 {
+		t.Error(mount, ok)
+	}
+	if mount, ok := findMountByPath(mounts, "/"); !ok || mount.MountID != 20 {
+		t.Error(mount, ok)
+	}
+	if _, ok := findMountByPath(mounts, "/home"); ok {
+		t.Error("Unmounted path")
+	}
+
+	if res := unescapeMountPath(`/a\040b\011c\134d\04`); res != "/a b\tc\\d\\04" {
+		t.Errorf("%q", res)
+	}
+}
+
+// Create fake sysfs tree for tests.
+// devices - kernel name of device => path of device directory from sysfs root and attributes of it.
+func createSysfsFixture(t *testing.T, root string, devices map[string]map[string]string) {
+	for name, attrs := range devices {
+		devDir := filepath.Join(root, attrs["path"])
+		for attr, value := range attrs {
+			switch attr {
+			case "path":
+				continue
+			case "holders", "slaves":
+				os.MkdirAll(filepath.Join(devDir, attr), 0700)
+				for _, link := range strings.Fields(value) {
+					os.Symlink("../../"+link, filepath.Join(devDir, attr, link))
+				}
+			default:
+				os.MkdirAll(filepath.Dir(filepath.Join(devDir, attr)), 0700)
+				if err := ioutil.WriteFile(filepath.Join(devDir, attr), []byte(value+"\n"), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		os.MkdirAll(filepath.Join(root, "class", "block"), 0700)
+		os.MkdirAll(filepath.Join(root, "dev", "block"), 0700)
+		if err := os.Symlink(devDir, filepath.Join(root, "class", "block", name)); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(devDir, filepath.Join(root, "dev", "block", attrs["dev"])); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSysfsTopology(t *testing.T) {
+	root, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	createSysfsFixture(t, root, map[string]map[string]string{
+		"sda":        {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda", "dev": "8:0", "size": "209715200", "queue/logical_block_size": "4096", "uevent": "MAJOR=8\nMINOR=0\nDEVNAME=sda\nDEVTYPE=disk"},
+		"sda1":       {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda/sda1", "dev": "8:1", "size": "2048", "partition": "1", "start": "2048", "holders": "dm-0"},
+		"nvme0n1":    {"path": "devices/pci0000:00/0000:00:04.0/nvme/nvme0/nvme0n1", "dev": "259:0", "size": "4194304", "queue/logical_block_size": "512"},
+		"nvme0n1p2":  {"path": "devices/pci0000:00/0000:00:04.0/nvme/nvme0/nvme0n1/nvme0n1p2", "dev": "259:3", "size": "2048", "partition": "2"},
+		"mmcblk0":    {"path": "devices/platform/mmc0/mmc_host/mmc0/mmc0:0001/block/mmcblk0", "dev": "179:0", "size": "2048", "queue/logical_block_size": "512"},
+		"xvda":       {"path": "devices/vbd-51712/block/xvda", "dev": "202:0", "size": "2048", "queue/logical_block_size": "512"},
+		"dm-0":       {"path": "devices/virtual/block/dm-0", "dev": "253:0", "size": "2048", "queue/logical_block_size": "512", "slaves": "sda1"},
+		"cciss!c0d0": {"path": "devices/pci0000:00/cciss0/block/cciss!c0d0", "dev": "104:0", "size": "2048", "queue/logical_block_size": "512"},
+	})
+	oldSysfsRoot := sysfsRoot
+	sysfsRoot = root
+	defer func() { sysfsRoot = oldSysfsRoot }()
+
+	dev, err := readBlockDevice("sda1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	need := blockDevice{Name: "sda1", DevName: "sda1", Major: 8, Minor: 1, Partition: 1, Start: 2048 * 512, Parent: "sda", Holders: []string{"dm-0"},
+		Size: 2048 * 512, LogicalBlockSize: 4096}
+	if diff := pretty.Diff(dev, need); diff != nil {
+		t.Error(diff)
+	}
+
+	types := map[[2]int]storageItemType{
+		{8, 0}: type_DISK, {8, 1}: type_PARTITION, {259, 0}: type_DISK, {259, 3}: type_PARTITION,
+		{179, 0}: type_DISK, {202, 0}: type_DISK, {253, 0}: type_UNKNOWN, {7, 0}: type_UNKNOWN,
+	}
+	for mm, needType := range types {
+		if res := getTypeByMajorMinor(mm[0], mm[1]); res != needType {
+			t.Error(mm, res, needType)
+		}
+	}
+
+	dev, err = blockDeviceByMajorMinor(259, 3)
+	if err != nil || dev.Parent != "nvme0n1" || dev.Partition != 2 || dev.LogicalBlockSize != 512 {
+		t.Error(dev, err)
+	}
+	dev, err = blockDeviceByMajorMinor(253, 0)
+	if err != nil || pretty.Diff(dev.Slaves, []string{"sda1"}) != nil {
+		t.Error(dev, err)
+	}
+	dev, err = readBlockDevice("cciss!c0d0")
+	if err != nil || dev.DevPath() != "/dev/cciss/c0d0" {
+		t.Error(dev, err)
+	}
+
+	devices, err := readBlockDevices()
+	if err != nil || len(devices) != 8 {
+		t.Error(len(devices), err)
+	}
+}
+
+func TestNVMeTopology(t *testing.T) {
+	root, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	createSysfsFixture(t, root, map[string]map[string]string{
+		"nvme0n1":   {"path": "devices/pci0000:00/0000:00:04.0/nvme/nvme0/nvme0n1", "dev": "259:0", "size": "4194304", "queue/logical_block_size": "512"},
+		"nvme0n1p1": {"path": "devices/pci0000:00/0000:00:04.0/nvme/nvme0/nvme0n1/nvme0n1p1", "dev": "259:1", "size": "2048", "partition": "1"},
+		"nvme1n1":   {"path": "devices/pci0000:00/0000:00:05.0/nvme/nvme1/nvme1n1", "dev": "259:2", "size": "4194304", "queue/logical_block_size": "4096"},
+	})
+	oldSysfsRoot := sysfsRoot
+	sysfsRoot = root
+	defer func() { sysfsRoot = oldSysfsRoot }()
+
+	diskPath, partNumber, err := partitionDisk("/dev/nvme0n1p1")
+	if diskPath != "/dev/nvme0n1" || partNumber != 1 || err != nil {
+		t.Error(diskPath, partNumber, err)
+	}
+	if _, _, err = partitionDisk("/dev/nvme1n1"); err == nil {
+		t.Error("Disk isn't partition")
+	}
+	if diskPath, err = deviceDisk("/dev/nvme1n1"); diskPath != "/dev/nvme1n1" || err != nil {
+		t.Error(diskPath, err)
+	}
+
+	storage := []storageItem{
+		{Type: type_LVM_GROUP, Path: "storage", Child: -1},
+		{Type: type_LVM_PV, Path: "/dev/nvme0n1p1", Child: 0},
+		{Type: type_LVM_PV, Path: "/dev/nvme1n1", Child: 0},
+	}
+	filter := expandFilter(storage, FILTER_LVM_ALREADY_PLACED)
+	if filter != "^/dev/nvme0n1(p[0-9]+)?$|^/dev/nvme1n1(p[0-9]+)?$" {
+		t.Fatal(filter)
+	}
+	filterRE := regexp.MustCompile(filter)
+	for path, match := range map[string]bool{"/dev/nvme0n1": true, "/dev/nvme0n1p2": true, "/dev/nvme1n1p10": true,
+		"/dev/nvme0n11": false, "/dev/nvme0n11p1": false, "/dev/nvme2n1p1": false} {
+		if filterRE.MatchString(path) != match {
+			t.Error(path, match)
+		}
+	}
+
+	for diskPath, partPath := range map[string]string{"/dev/nvme0n1": "/dev/nvme0n1p3", "/dev/mmcblk0": "/dev/mmcblk0p3",
+		"/dev/sda": "/dev/sda3", "/dev/xvda": "/dev/xvda3", "/dev/loop0": "/dev/loop0p3"} {
+		part := partition{Disk: &diskInfo{Path: diskPath}, Number: 3}
+		if res := part.makePath(); res != partPath {
+			t.Error(diskPath, res)
+		}
+	}
+}
+
+func TestCryptTopology(t *testing.T) {
+	root, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	createSysfsFixture(t, root, map[string]map[string]string{
+		"sda":  {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda", "dev": "8:0", "size": "209715200", "queue/logical_block_size": "512"},
+		"sda1": {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda/sda1", "dev": "8:1", "size": "2048", "partition": "1", "holders": "dm-0"},
+		"dm-0": {"path": "devices/virtual/block/dm-0", "dev": "253:0", "size": "1024", "slaves": "sda1", "dm/name": "cryptroot", "dm/uuid": "CRYPT-LUKS2-0b7ba6e4a3b94a0e8b6c1dba2a5c8e3b-cryptroot"},
+		"dm-1": {"path": "devices/virtual/block/dm-1", "dev": "253:1", "size": "1024", "slaves": "dm-0", "dm/name": "vg-root", "dm/uuid": "LVM-Yb7Yk2dCd3Gf"},
+	})
+	oldSysfsRoot := sysfsRoot
+	sysfsRoot = root
+	defer func() { sysfsRoot = oldSysfsRoot }()
+
+	dev, err := blockDeviceByMajorMinor(253, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.StorageType() != type_CRYPT || dev.DMName != "cryptroot" || !reflect.DeepEqual(dev.Slaves, []string{"sda1"}) {
+		t.Errorf("%# v", pretty.Formatter(dev))
+	}
+	if dev, err = blockDeviceByMajorMinor(253, 1); err != nil || dev.StorageType() != type_UNKNOWN {
+		t.Errorf("%# v %v", pretty.Formatter(dev), err)
+	}
+
+	status := `/dev/mapper/cryptroot is active and is in use.
+  type:    LUKS2
+  cipher:  aes-xts-plain64
+  keysize: 512 bits
+  key location: keyring
+  device:  /dev/sda1
+  sector size:  512
+  offset:  32768 sectors
+  size:    1024 sectors
+  mode:    read/write
+`
+	offset, err := parseCryptStatusOffset(status)
+	if offset != 32768*512 || err != nil {
+		t.Error(offset, err)
+	}
+	if _, err = parseCryptStatusOffset("/dev/mapper/cryptroot is inactive.\n"); err == nil {
+		t.Error("Status without offset")
+	}
+
+	storage := []storageItem{
+		{Type: type_FS, Path: "/dev/mapper/cryptroot", Child: -1},
+		{Type: type_CRYPT, Path: "/dev/mapper/cryptroot", Size: 100 * 1024 * 1024, CryptOffset: 16 * 1024 * 1024, Child: 0},
+		{Type: type_PARTITION, Path: "/dev/sda1", Size: 1024 * 1024 * 1024, Child: 1},
+	}
+	scanFreeSpace(storage)
+	if storage[1].FreeSpace != (1024-100-16)*1024*1024 {
+		t.Error(storage[1].FreeSpace)
+	}
+}
+
+func TestRaid(t *testing.T) {
+	root, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	createSysfsFixture(t, root, map[string]map[string]string{
+		"sda":  {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda", "dev": "8:0", "size": "209715200"},
+		"sda1": {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda/sda1", "dev": "8:1", "size": "2048", "partition": "1", "holders": "md0"},
+		"sdb":  {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:1/0:0:1:0/block/sdb", "dev": "8:16", "size": "209715200"},
+		"sdb1": {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:1/0:0:1:0/block/sdb/sdb1", "dev": "8:17", "size": "2048", "partition": "1", "holders": "md0"},
+		"md0":  {"path": "devices/virtual/block/md0", "dev": "9:0", "size": "1024", "slaves": "sda1 sdb1", "md/level": "raid10", "md/raid_disks": "2", "md/layout": "258", "md/component_size": "512"},
+	})
+	oldSysfsRoot := sysfsRoot
+	sysfsRoot = root
+	defer func() { sysfsRoot = oldSysfsRoot }()
+
+	dev, err := blockDeviceByPath("/dev/md0")
+	if err != nil || dev.StorageType() != type_RAID || !reflect.DeepEqual(dev.Slaves, []string{"sda1", "sdb1"}) {
+		t.Errorf("%# v %v", pretty.Formatter(dev), err)
+	}
+	raid, err := readRaidInfo("md0")
+	if diff := pretty.Diff(raid, raidInfo{Level: "raid10", Disks: 2, Layout: 258, ComponentSize: 512 * 1024}); diff != nil || err != nil {
+		t.Error(diff, err)
+	}
+
+	const MiB = 1024 * 1024
+	for _, test := range []struct {
+		raid raidInfo
+		size uint64
+	}{
+		{raidInfo{Level: "raid1", Disks: 3}, 100 * MiB},
+		{raidInfo{Level: "raid5", Disks: 4}, 300 * MiB},
+		{raidInfo{Level: "raid6", Disks: 4}, 200 * MiB},
+		{raidInfo{Level: "raid10", Disks: 4, Layout: 0x102}, 200 * MiB},
+		{raidInfo{Level: "raid10", Disks: 3, Layout: 0x102}, 150 * MiB},
+	} {
+		if size, err := test.raid.ArraySize(100 * MiB); size != test.size || err != nil {
+			t.Error(test.raid, size, err)
+		}
+	}
+	for _, level := range []string{"raid0", "linear"} {
+		if _, err := (raidInfo{Level: level, Disks: 2}).ArraySize(100 * MiB); err == nil {
+			t.Error(level)
+		}
+	}
+
+	storage := []storageItem{
+		{Type: type_FS, Path: "/dev/md0", Child: -1},
+		{Type: type_RAID, Path: "/dev/md0", Size: 200 * MiB, Child: 0, Raid: raidInfo{Level: "raid5", Disks: 3, ComponentSize: 100 * MiB}},
+		{Type: type_PARTITION, Path: "/dev/sda1", FreeSpace: 50 * MiB, Child: 1},
+		{Type: type_PARTITION, Path: "/dev/sdb1", FreeSpace: 30 * MiB, Child: 1},
+		{Type: type_PARTITION, Path: "/dev/sdc1", FreeSpace: 70 * MiB, Child: 1},
+	}
+	raidPlanMembers(storage, 1)
+	if storage[1].FreeSpace != 60*MiB || storage[2].FreeSpace != 30*MiB || storage[3].FreeSpace != 30*MiB ||
+		storage[4].FreeSpace != 30*MiB {
+		t.Error(storage)
+	}
+
+	// Whole disk member can't be extended
+	storage[4].Type = type_DISK
+	raidPlanMembers(storage, 1)
+	if storage[1].FreeSpace != 0 || storage[2].FreeSpace != 0 {
+		t.Error(storage)
+	}
+
+	raid5 := raidInfo{Level: "raid5", Disks: 3, ComponentSize: 100 * MiB}
+	for _, test := range []struct{ arraySize, component uint64 }{
+		{200 * MiB, 100 * MiB},
+		{221 * MiB, 110*MiB + 512*1024},
+		{221*MiB + 1, 110*MiB + 513*1024},
+	} {
+		if component, err := raid5.ComponentSizeFor(test.arraySize); component != test.component || err != nil {
+			t.Error(test, component, err)
+		}
+	}
+	if _, err := (raidInfo{Level: "raid0", Disks: 2}).ComponentSizeFor(100 * MiB); err == nil {
+		t.Error("Component size of raid0")
+	}
+
+	// Limited growth of RAID: members grow by same aligned size
+	// Ограниченный рост RAID: участники растут на одинаковый выровненный размер
+	storage = []storageItem{
+		{Type: type_FS, FSType: "ext4", Path: "/dev/md0", Size: 200 * MiB, Child: -1},
+		{Type: type_RAID, Path: "/dev/md0", Size: 200 * MiB, Child: 0, Raid: raid5},
+		{Type: type_PARTITION, Path: "/dev/sda1", Size: 100 * MiB, FreeSpace: 50 * MiB, Child: 1},
+		{Type: type_PARTITION, Path: "/dev/sdb1", Size: 100 * MiB, FreeSpace: 30 * MiB, Child: 1},
+		{Type: type_PARTITION, Path: "/dev/sdc1", Size: 100 * MiB, FreeSpace: 70 * MiB, Child: 1},
+	}
+	raidPlanMembers(storage, 1)
+	if err := planLimitSize(storage, sizeTarget{Value: 21 * MiB, Relative: true}); err != nil {
+		t.Fatal(err)
+	}
+	if storage[1].FreeSpace != 22*MiB || storage[1].Limit != 222*MiB || storage[0].Limit != 221*MiB {
+		t.Error(storage[:2])
+	}
+	for _, member := range storage[2:] {
+		if member.FreeSpace != 11*MiB || member.Limit != 111*MiB {
+			t.Error(member)
+		}
+	}
+
+	replay := &replayRunner{Records: []commandRecord{
+		{Name: "mdadm", Args: []string{"--grow", "/dev/md0", "--size=113664"}},
+		{Name: "blockdev", Args: []string{"--getsize64", "/dev/md0"}, Stdout: "232783872\n"},
+	}}
+	oldRunner := runner
+	defer func() { runner = oldRunner }()
+	runner = replay
+	if err := (raidLayer{}).Apply(&layerStep{Plan: storage, Index: 1}); err != nil {
+		t.Error(err)
+	}
+	if storage[1].Size != 222*MiB || storage[0].FreeSpace != 22*MiB || len(replay.Missed) != 0 {
+		t.Error(storage[:2], replay.Missed)
+	}
+}
+
+func TestBtrfs(t *testing.T) {
+	out := `Label: 'fedora'  uuid: 5b9c1a4e-3a0f-4c4b-9a43-8b3b1a2e0c11
+	Total devices 2 FS bytes used 196608
+	devid    1 size 1073741824 used 138412032 path /dev/sda1
+	devid    2 size 536870912 used 0 path /dev/sdb1
+
+`
+	devices, err := parseBtrfsShow(out)
+	need := []btrfsDevice{{DevID: 1, Size: 1073741824, Path: "/dev/sda1"}, {DevID: 2, Size: 536870912, Path: "/dev/sdb1"}}
+	if diff := pretty.Diff(devices, need); diff != nil || err != nil {
+		t.Error(diff, err)
+	}
+	if _, err = parseBtrfsShow("ERROR: not a valid btrfs filesystem: /dev/sdc\n"); err == nil {
+		t.Error("Output without devices")
+	}
+
+	storage := []storageItem{
+		{Type: type_FS, FSType: "btrfs", Path: "/dev/sda1", Child: -1},
+		{Type: type_PARTITION, Path: "/dev/sda1", Child: 0, FreeSpace: 100,
+			Partition: partition{Disk: &diskInfo{Path: "/dev/sda"}, Path: "/dev/sda1", Number: 1, FirstByte: 1024 * 1024, LastByte: 2*1024*1024 - 1}},
+		{Type: type_BTRFS_DEVICE_NEW, Path: "/dev/sdc1", Child: 0},
+		{Type: type_PARTITION_NEW, Path: "/dev/sdc1", Child: 2, FreeSpace: 200,
+			Partition: partition{Disk: &diskInfo{Path: "/dev/sdc"}, Path: "/dev/sdc1", Number: 1, FirstByte: 1024 * 1024, LastByte: 2*1024*1024 - 1}},
+	}
+	if filter := expandFilter(storage, FILTER_LVM_ALREADY_PLACED); filter != "^/dev/sda[^/]*$" {
+		t.Error(filter)
+	}
+
+	copyStorage := func() []storageItem {
+		return append([]storageItem(nil), storage...)
+	}
+	plan, err := extendPlan(copyStorage(), planOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range plan {
+		if item.Path == "/dev/sdc1" && item.Type != type_SKIP {
+			t.Error("Add device must be skipped without option", item)
+		}
+	}
+
+	plan, err = extendPlan(copyStorage(), planOptions{BtrfsAddDevice: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make([]storageItemType, len(plan))
+	for i, item := range plan {
+		types[i] = item.Type
+	}
+	if !reflect.DeepEqual(types, []storageItemType{type_PARTITION_NEW, type_BTRFS_DEVICE_NEW, type_PARTITION, type_FS}) {
+		t.Error(types)
+	}
+
+	// New partition skipped by filter - device doesn't add.
+	plan, err = extendPlan(copyStorage(), planOptions{Filter: FILTER_LVM_ALREADY_PLACED, BtrfsAddDevice: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range plan {
+		if item.Path == "/dev/sdc1" && item.Type != type_SKIP {
+			t.Error("Add device must be skipped by filter", item)
+		}
+	}
+}
+
+func TestExtendPrintJSON(t *testing.T) {
+	disk := &diskInfo{Path: "/dev/sda", PartTable: "gpt"}
+	plan := []storageItem{
+		{Type: type_PARTITION_NEW, Path: "/dev/sda2", Child: 1, FreeSpace: 300,
+			Partition: partition{Disk: disk, Path: "/dev/sda2", Number: 2, FirstByte: 2048, LastByte: 4095}},
+		{Type: type_LVM_PV_NEW, Path: "/dev/sda2", Child: 3, LVMExtentSize: 4 * 1024 * 1024},
+		{Type: type_SKIP, OldType: type_PARTITION, Path: "/dev/sdb1", Child: -1, SkipReason: "Skip by filters."},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 4, Size: 1000, FreeSpace: 100},
+		{Type: type_LVM_LV, Path: "vg/root", Child: 5, Size: 1000},
+		{Type: type_FS, FSType: "ext4", Path: "/dev/mapper/vg-root", Child: -1, Size: 900, FreeSpace: 100},
+	}
+	buf := &bytes.Buffer{}
+	if err := extendPrintJSON(buf, "/home", plan); err != nil {
+		t.Fatal(err)
+	}
+	var res planJSON
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Version != planJSON_VERSION || len(res.Steps) != len(plan) {
+		t.Fatal(buf.String())
+	}
+	needTarget := planJSONTarget{Path: "/dev/mapper/vg-root", Type: "fs", Size: 900, Grow: 500, NewSize: 1400}
+	if diff := pretty.Diff(res.Target, needTarget); diff != nil {
+		t.Error(diff)
+	}
+	needStep := planJSONStep{Index: 0, Type: "partition_new", Path: "/dev/sda2", FreeSpace: 300, Grow: 300, Child: 1,
+		Partition: &planJSONPartition{Table: "gpt", Disk: "/dev/sda", Number: 2, FirstByte: 2048, LastByte: 4095}}
+	if diff := pretty.Diff(res.Steps[0], needStep); diff != nil {
+		t.Error(diff)
+	}
+	if res.Steps[2].SkipReason != "Skip by filters." || res.Steps[2].SkippedType != "partition" {
+		t.Errorf("%# v", pretty.Formatter(res.Steps[2]))
+	}
+	if res.Steps[4].Grow != 400 || res.Steps[1].LVMExtentSize != 4*1024*1024 {
+		t.Error(buf.String())
+	}
+}
+
+func TestPlanFile(t *testing.T) {
+	disk := &diskInfo{Path: "/dev/sda", PartTable: "msdos"}
+	plan := []storageItem{
+		{Type: type_PARTITION, Path: "/dev/sda1", Child: 1, Size: 1000, FreeSpace: 500,
+			Partition: partition{Disk: disk, Path: "/dev/sda1", Number: 1, FirstByte: 512, LastByte: 1511}},
+		{Type: type_FS, FSType: "xfs", Path: "/dev/sda1", Child: -1, Size: 1000},
+	}
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	planFile := filepath.Join(dir, "plan.json")
+	if err = writePlanFile(planFile, "/home", plan); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := readPlanFile(planFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := planDiff(saved, makePlanJSON("/home", plan)); len(diffs) != 0 {
+		t.Error(diffs)
+	}
+
+	plan[0].Partition.LastByte = 2047
+	plan[1].Size = 1500
+	diffs := planDiff(saved, makePlanJSON("/home", plan))
+	need := []string{
+		"step 0 (/dev/sda1): partition: {msdos /dev/sda 1 512 1511 false} -> {msdos /dev/sda 1 512 2047 false}",
+		"step 1 (/dev/sda1): size: 1000 -> 1500",
+	}
+	if !reflect.DeepEqual(diffs, need) {
+		t.Error(diffs)
+	}
+
+	diffs = planDiff(saved, makePlanJSON("/home", plan[1:]))
+	if !reflect.DeepEqual(diffs, []string{"count of steps: 2 -> 1"}) {
+		t.Error(diffs)
+	}
+
+	ioutil.WriteFile(planFile, []byte(`{"version": 1000, "steps": []}`), 0600)
+	if _, err = readPlanFile(planFile); err == nil {
+		t.Error("Unsupported version")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for s, need := range map[string]uint64{"1024": 1024, "10K": 10 * 1024, "20m": 20 * 1024 * 1024,
+		"30G": 30 * 1024 * 1024 * 1024, "30GB": 30 * 1024 * 1024 * 1024, "30GiB": 30 * 1024 * 1024 * 1024,
+		"1T": 1024 * 1024 * 1024 * 1024} {
+		if size, err := parseSize(s); size != need || err != nil {
+			t.Error(s, size, err)
+		}
+	}
+	for _, s := range []string{"", "G", "-1G", "1.5G", "1X", "20000000P"} {
+		if size, err := parseSize(s); err == nil {
+			t.Error(s, size)
+		}
+	}
+
+	for s, need := range map[string]sizeTarget{"max": {}, "200G": {Value: 200 * 1024 * 1024 * 1024},
+		"+50G": {Value: 50 * 1024 * 1024 * 1024, Relative: true}, "80%": {Value: 80, Percent: true}} {
+		if target, err := parseSizeTarget(s); target != need || err != nil {
+			t.Error(s, target, err)
+		}
+	}
+	for _, s := range []string{"0%", "101%", "+0", "0", "abc"} {
+		if target, err := parseSizeTarget(s); err == nil {
+			t.Error(s, target)
+		}
+	}
+
+	for _, test := range []struct {
+		target                  sizeTarget
+		size, available, result uint64
+		isErr                   bool
+	}{
+		{sizeTarget{}, 100, 50, 50, false},
+		{sizeTarget{Value: 120}, 100, 50, 20, false},
+		{sizeTarget{Value: 80}, 100, 50, 0, false},
+		{sizeTarget{Value: 200}, 100, 50, 0, true},
+		{sizeTarget{Value: 30, Relative: true}, 100, 50, 30, false},
+		{sizeTarget{Value: 60, Relative: true}, 100, 50, 0, true},
+		{sizeTarget{Value: 80, Percent: true}, 100, 50, 40, false},
+	} {
+		if need, err := test.target.Need(test.size, test.available); need != test.result || (err != nil) != test.isErr {
+			t.Error(test, need, err)
+		}
+	}
+}
+
+func TestPlanLimitSize(t *testing.T) {
+	const MiB = 1024 * 1024
+	const GiB = 1024 * MiB
+	disk := &diskInfo{Path: "/dev/sda", PartTable: "gpt", SectorSizeLogical: 512}
+	makePlan := func() []storageItem {
+		return []storageItem{
+			{Type: type_PARTITION_NEW, Path: "/dev/sda2", Child: 1, FreeSpace: 50 * GiB,
+				Partition: partition{Disk: disk, Path: "/dev/sda2", Number: 2, FirstByte: 101 * GiB, LastByte: 151*GiB - 1}},
+			{Type: type_LVM_PV_NEW, Path: "/dev/sda2", Child: 4, LVMExtentSize: 4 * MiB},
+			{Type: type_PARTITION, Path: "/dev/sda1", Child: 3, Size: 100 * GiB, FreeSpace: 20 * GiB,
+				Partition: partition{Disk: disk, Path: "/dev/sda1", Number: 1, FirstByte: MiB, LastByte: 101*GiB - 1}},
+			{Type: type_LVM_PV, Path: "/dev/sda1", Child: 4, Size: 100 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_GROUP, Path: "vg", Child: 5, Size: 100 * GiB, FreeSpace: 10 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_LV, Path: "vg/root", Child: 6, Size: 50 * GiB},
+			{Type: type_FS, FSType: "ext4", Path: "/dev/mapper/vg-root", Child: -1, Size: 50 * GiB},
+		}
+	}
+
+	// Free space of VG uses first, then existed partition
+	plan := makePlan()
+	if err := planLimitSize(plan, sizeTarget{Value: 15 * GiB, Relative: true}); err != nil {
+		t.Fatal(err)
+	}
+	if plan[0].Type != type_SKIP || plan[1].Type != type_SKIP {
+		t.Error("New partition doesn't need", plan[0], plan[1])
+	}
+	if plan[2].FreeSpace != 5*GiB || plan[2].Limit != 105*GiB || plan[4].FreeSpace != 10*GiB ||
+		plan[5].Limit != 65*GiB || plan[6].Limit != 65*GiB {
+		t.Error(plan)
+	}
+	if grow := planGrowth(plan); grow[6] != 15*GiB {
+		t.Error(formatSize(grow[6]))
+	}
+
+	// New partition created with part of free space
+	plan = makePlan()
+	if err := planLimitSize(plan, sizeTarget{Value: 50, Percent: true}); err != nil {
+		t.Fatal(err)
+	}
+	if plan[0].Type != type_PARTITION_NEW || plan[0].FreeSpace != 10*GiB || plan[0].Partition.LastByte != 111*GiB-1 ||
+		plan[2].FreeSpace != 20*GiB || plan[6].Limit != 90*GiB {
+		t.Error(plan)
+	}
+
+	// Target size reached already
+	plan = makePlan()
+	if err := planLimitSize(plan, sizeTarget{Value: 10 * GiB}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 6; i++ {
+		if plan[i].Type != type_SKIP {
+			t.Error(plan[i])
+		}
+	}
+	if plan[6].Type != type_FS || plan[6].FreeSpace != 0 || plan[6].Limit != plan[6].Size {
+		t.Error(plan[6])
+	}
+
+	if err := planLimitSize(makePlan(), sizeTarget{Value: 100 * GiB, Relative: true}); err == nil {
+		t.Error("Can't extend more then available")
+	}
+
+	// Logical partition takes rest of growth from extended partition, extended partition grows only by the rest
+	// Логический раздел берёт остаток роста у расширенного раздела, расширенный раздел растёт только на остаток
+	msdos := &diskInfo{Path: "/dev/sdb", PartTable: "msdos", SectorSizeLogical: 512, ExtendedNumber: 2}
+	plan = []storageItem{
+		{Type: type_PARTITION_EXTENDED, Path: "/dev/sdb2", Child: 1, Size: 20 * GiB, FreeSpace: 50 * GiB,
+			Partition: partition{Disk: msdos, Path: "/dev/sdb2", Number: 2, FirstByte: 10 * GiB, LastByte: 30*GiB - 1}},
+		{Type: type_PARTITION, Path: "/dev/sdb5", Child: 2, Size: 19 * GiB, FreeSpace: 1*GiB - 1*MiB,
+			Partition: partition{Disk: msdos, Path: "/dev/sdb5", Number: 5, FirstByte: 10*GiB + 1*MiB, LastByte: 29 * GiB,
+				Logical: true, EBRByte: 10 * GiB}},
+		{Type: type_FS, FSType: "ext4", Path: "/dev/sdb5", Child: -1, Size: 19 * GiB},
+	}
+	if err := planLimitSize(plan, sizeTarget{Value: 5 * GiB, Relative: true}); err != nil {
+		t.Fatal(err)
+	}
+	if plan[0].Type != type_PARTITION_EXTENDED || plan[0].FreeSpace != 4*GiB+1*MiB || plan[0].Limit != 24*GiB+1*MiB ||
+		plan[1].Limit != 24*GiB || plan[2].Limit != 24*GiB {
+		t.Error(plan)
+	}
+	if grow := planGrowth(plan); grow[2] != 5*GiB {
+		t.Error(formatSize(grow[2]))
+	}
+}
+
+func TestPlanReserveVG(t *testing.T) {
+	const MiB = 1024 * 1024
+	const GiB = 1024 * MiB
+	makePlan := func() []storageItem {
+		return []storageItem{
+			{Type: type_PARTITION, Path: "/dev/sda1", Child: 1, Size: 90 * GiB, FreeSpace: 10 * GiB,
+				Partition: partition{Disk: &diskInfo{Path: "/dev/sda", PartTable: "gpt"}, Path: "/dev/sda1", Number: 1}},
+			{Type: type_LVM_PV, Path: "/dev/sda1", Child: 2, Size: 90 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_GROUP, Path: "vg", Child: 3, Size: 90 * GiB, FreeSpace: 5 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_LV, Path: "vg/root", Child: 4, Size: 85 * GiB},
+			{Type: type_FS, FSType: "xfs", Path: "/dev/mapper/vg-root", Child: -1, Size: 85 * GiB},
+		}
+	}
+
+	for _, test := range []struct {
+		reserve sizeReserve
+		result  uint64
+	}{
+		{sizeReserve{Value: 3 * GiB}, 3 * GiB},
+		{sizeReserve{Value: 10, Percent: true}, 10 * GiB},
+		{sizeReserve{Value: 20 * GiB}, 20 * GiB},
+	} {
+		plan := makePlan()
+		planReserveVG(plan, test.reserve)
+		if plan[2].LVMReserve != test.result {
+			t.Error(test.reserve, formatSize(plan[2].LVMReserve))
+		}
+		grow := planGrowth(plan)
+		need := uint64(15*GiB) - minUint64(test.result, 15*GiB)
+		if grow[4] != need {
+			t.Error(test.reserve, formatSize(grow[4]))
+		}
+		if !strings.Contains(plan[2].String(), "Reserve: "+formatSize(test.result)) {
+			t.Error(plan[2].String())
+		}
+	}
+
+	// Reserve with target size: reserve stays free, partition extends for it.
+	plan := makePlan()
+	planReserveVG(plan, sizeReserve{Value: 3 * GiB})
+	if err := planLimitSize(plan, sizeTarget{Value: 4 * GiB, Relative: true}); err != nil {
+		t.Fatal(err)
+	}
+	if plan[0].FreeSpace != 2*GiB || plan[2].Limit != 94*GiB || plan[3].Limit != 89*GiB {
+		t.Error(plan)
+	}
+	if err := planLimitSize(makePlan(), sizeTarget{Value: 13 * GiB, Relative: true}); err != nil {
+		t.Error(err)
+	}
+	plan = makePlan()
+	planReserveVG(plan, sizeReserve{Value: 3 * GiB})
+	if err := planLimitSize(plan, sizeTarget{Value: 13 * GiB, Relative: true}); err == nil {
+		t.Error("Reserve isn't available for extend")
+	}
+
+	for s, need := range map[string]sizeReserve{"": {}, "10G": {Value: 10 * GiB}, "15%": {Value: 15, Percent: true}} {
+		if reserve, err := parseSizeReserve(s); reserve != need || err != nil {
+			t.Error(s, reserve, err)
+		}
+	}
+	for _, s := range []string{"101%", "abc", "-1G"} {
+		if reserve, err := parseSizeReserve(s); err == nil {
+			t.Error(s, reserve)
+		}
+	}
+}
+
+func TestJournal(t *testing.T) {
+	disk := &diskInfo{Path: "/dev/sda", PartTable: "msdos"}
+	disk.Partitions = []partition{{Disk: disk, Path: "/dev/sda1", Number: 1, FirstByte: 512, LastByte: 1511}}
+	plan := []storageItem{
+		{Type: type_SWAP_CREATE, Path: "/dev/sda2", Child: -1, Partition: partition{Disk: disk, Path: "/dev/sda2", Number: 2}},
+		{Type: type_LVM_LV, Path: "/dev/vg/lv", Child: 2, FreeSpace: 100},
+		{Type: type_FS, FSType: "xfs", Path: "/dev/vg/lv", Child: -1},
+	}
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stateFile := filepath.Join(dir, "state", "state.json")
+
+	j := newJournal(stateFile, "/home", plan)
+	j.StepStart(0, plan)
+	if j.StepFinish(0, plan, true) != true {
+		t.Error("Step with reboot must stop extend")
+	}
+	if j.IsDone(0) {
+		t.Error("Swap creation must repeat after reboot")
+	}
+	j.StepStart(1, plan)
+
+	saved, err := readJournal(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.StartPoint != "/home" || len(saved.Plan) != 3 || saved.Current != 1 || saved.RebootStep != 0 {
+		t.Errorf("%# v", pretty.Formatter(saved))
+	}
+	if saved.Plan[0].Partition.Disk == nil || saved.Plan[0].Partition.Disk.Path != "/dev/sda" {
+		t.Errorf("%# v", pretty.Formatter(saved.Plan[0]))
+	}
+	if err = saved.CheckReboot(); exitCode(err) != exit_PARTIAL {
+		t.Error("Interrupted step must be detected", err)
+	}
+	// Reboot doesn't help interrupted step
+	// Перезагрузка не помогает прерванному шагу
+	if code := resumeDo(stateFile, doOptions{}); code != exit_PARTIAL {
+		t.Error("Resume of interrupted step", code)
+	}
+
+	if j.StepFinish(1, plan, false) != false || !j.IsDone(1) {
+		t.Error("Step 1 must be done")
+	}
+	if err = j.CheckReboot(); err != nil {
+		t.Error(err)
+	}
+	j.Finish(plan)
+	if j.Complete {
+		t.Error("Extend isn't complete")
+	}
+	j.Done = []int{0, 1, 2}
+	j.Finish(plan)
+	if saved, err = readJournal(stateFile); err != nil || !saved.Complete {
+		t.Error(saved, err)
+	}
+
+	var nilJournal *journal
+	if nilJournal.IsDone(0) || nilJournal.StepFinish(0, plan, true) {
+		t.Error("Nil journal must do nothing")
+	}
+	nilJournal.StepStart(0, plan)
+	nilJournal.Finish(plan)
+}
+
+func TestJournalResumeLogicalPartition(t *testing.T) {
+	disk := &diskInfo{Path: "/dev/sda", PartTable: "msdos", Size: 1024 * 1024, SectorSizeLogical: 512, ExtendedNumber: 2}
+	disk.Partitions = []partition{
+		{Disk: disk, Path: "/dev/sda1", Number: 1, FirstByte: 512, LastByte: 65535},
+		{Disk: disk, Path: "/dev/sda2", Number: 2, FirstByte: 65536, LastByte: 1024*1024 - 1},
+	}
+	disk.LogicalPartitions = []partition{
+		{Disk: disk, Path: "/dev/sda5", Number: 5, FirstByte: 65536 + 512, LastByte: 131071, Logical: true, EBRByte: 65536},
+	}
+	plan := []storageItem{
+		{Type: type_PARTITION_NEW, Path: "/dev/sda6", Child: -1,
+			Partition: partition{Disk: disk, Path: "/dev/sda6", Number: 6, Logical: true}},
+	}
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stateFile := filepath.Join(dir, "state.json")
+
+	j := newJournal(stateFile, "/home", plan)
+	j.StepStart(0, plan)
+	if disk.Partitions[0].Disk != disk || disk.LogicalPartitions[0].Disk != disk {
+		t.Error("Save must not change partitions of plan")
+	}
+
+	saved, err := readJournal(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedDisk := saved.Plan[0].Partition.Disk
+	if savedDisk == nil {
+		t.Fatal("Disk of partition lost")
+	}
+	if extended, _, ok := savedDisk.extendedPartition(); !ok || extended.Number != 2 {
+		t.Errorf("%# v", pretty.Formatter(savedDisk))
+	}
+	if last, ok := savedDisk.lastLogicalPartition(); !ok || last.Number != 5 || last.EBRByte != 65536 {
+		t.Errorf("%# v", pretty.Formatter(savedDisk))
+	}
+	for _, part := range append(savedDisk.Partitions, savedDisk.LogicalPartitions...) {
+		if part.Disk != savedDisk {
+			t.Error("Link to disk isn't restored:", part.Path)
+		}
+	}
+}
+
+func TestBlkpgLayout(t *testing.T) {
+	// Size of struct blkpg_partition from linux/blkpg.h
+	if size := unsafe.Sizeof(blkpgPartition{}); size != 152 {
+		t.Error(size)
+	}
+	// Size of struct blkpg_ioctl_arg from linux/blkpg.h
+	ptrSize := unsafe.Sizeof(uintptr(0))
+	if size, need := unsafe.Sizeof(blkpgIoctlArg{}), (3*4+ptrSize-1)/ptrSize*ptrSize+ptrSize; size != need {
+		t.Error(size, need)
+	}
+	if err := blkpgPartitionOp("/not-existed-disk", blkpg_RESIZE_PARTITION, 1, 0, 0); err == nil {
+		t.Error("Error for not existed disk")
+	}
+}
+
+// Partition without child in plan (start point itself) grows and kernel is informed about new size
+// Раздел без потомка в плане (сама точка старта) растёт, и ядро оповещается о новом размере
+func TestPartitionApplyWithoutChild(t *testing.T) {
+	const MiB = 1024 * 1024
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oldSysfsRoot := sysfsRoot
+	defer func() { sysfsRoot = oldSysfsRoot }()
+	sysfsRoot = dir
+
+	diskPath := filepath.Join(dir, "sda")
+	table, _ := mbr.Read(bytes.NewReader(make([]byte, 512)))
+	table.FixSignature()
+	mbrPart := table.GetPartition(1)
+	mbrPart.SetType(mbr_PART_LINUX)
+	mbrPart.SetLBAStart(2048)
+	mbrPart.SetLBALen(2048)
+	buf := &bytes.Buffer{}
+	table.Write(buf)
+	if err = ioutil.WriteFile(diskPath, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Truncate(diskPath, 64*MiB)
+
+	replay := &replayRunner{Records: []commandRecord{
+		{Name: "stat", Args: []string{"-c", "%t:%T", diskPath}, Stdout: "8:0\n"},
+		{Name: "blockdev", Args: []string{"--getss", diskPath}, Stdout: "512\n"},
+		{Name: "blockdev", Args: []string{"--getsize64", diskPath}, Stdout: "67108864\n"},
+		{Name: "blockdev", Args: []string{"--getsize64", diskPath + "1"}, Stdout: "1048576\n"},
+		{Name: "partprobe", Args: []string{diskPath}},
+		{Name: "blockdev", Args: []string{"--getsize64", diskPath + "1"}, Stdout: "4194304\n"},
+	}}
+	oldRunner := runner
+	defer func() { runner = oldRunner }()
+	runner = replay
+
+	disk := &diskInfo{Path: diskPath, PartTable: "msdos", Size: 64 * MiB, SectorSizeLogical: 512}
+	plan := []storageItem{{Type: type_PARTITION, Path: diskPath + "1", Child: -1, Size: 1 * MiB, FreeSpace: 3 * MiB,
+		Partition: partition{Disk: disk, Path: diskPath + "1", Number: 1, FirstByte: 1 * MiB, LastByte: 2*MiB - 1}}}
+	step := &layerStep{Plan: plan, Index: 0, Options: doOptions{BackupDir: filepath.Join(dir, "backup")}}
+	if err = (partitionLayer{}).Apply(step); err != nil {
+		t.Fatal(err)
+	}
+	if plan[0].Size != 4*MiB || plan[0].FreeSpace != 0 || step.NeedReboot {
+		t.Error(plan[0], step.NeedReboot)
+	}
+	f, _ := os.Open(diskPath)
+	defer f.Close()
+	if table, err = mbr.Read(f); err != nil || table.GetPartition(1).GetLBALen() != 8192 {
+		t.Error(err)
+	}
+	if len(replay.Missed) != 0 {
+		t.Error(replay.Missed)
+	}
+}
+
+func TestTableBackup(t *testing.T) {
+	const diskSize = 1024 * 1024
+	diskFile, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(diskFile.Name())
+	defer diskFile.Close()
+	diskFile.Truncate(diskSize)
+
+	table := gpt.NewTable(diskSize, nil)
+	table.Partitions[0].Type = gpt.GUID_LVM
+	table.Partitions[0].FirstLBA = 2048
+	table.Partitions[0].LastLBA = 4095
+	if err = table.Write(diskFile); err != nil {
+		t.Fatal(err)
+	}
+	if err = table.CreateOtherSideTable().Write(diskFile); err != nil {
+		t.Fatal(err)
+	}
+	original, _ := ioutil.ReadFile(diskFile.Name())
+
+	disk := diskInfo{Path: "/dev/sda", PartTable: "gpt", Size: diskSize, SectorSizeLogical: 512}
+	backup, err := makeTableBackup(diskFile, disk, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup.DiskGUID != table.Header.DiskGUID.String() {
+		t.Error(backup.DiskGUID)
+	}
+	var regions []string
+	for _, region := range backup.Regions {
+		regions = append(regions, region.Name+":"+strconv.FormatUint(region.Offset, 10)+"+"+strconv.Itoa(len(region.Data)))
+	}
+	need := []string{"protective mbr:0+512", "gpt primary header:512+512", "gpt primary entries:1024+16384",
+		"gpt backup:1031680+16896"}
+	if !reflect.DeepEqual(regions, need) {
+		t.Error(regions)
+	}
+
+	// Damage primary table, GUID still can be checked by backup table
+	diskFile.WriteAt(make([]byte, 1024), 512)
+	if err = backup.Check(diskFile, diskSize, 512); err != nil {
+		t.Error(err)
+	}
+	if err = backup.Check(diskFile, diskSize*2, 512); err == nil {
+		t.Error("Check of disk size")
+	}
+	if err = backup.Check(diskFile, diskSize, 4096); err == nil {
+		t.Error("Check of sector size")
+	}
+	otherBackup := backup
+	otherBackup.DiskGUID = gpt.NewGUID().String()
+	if err = otherBackup.Check(diskFile, diskSize, 512); err == nil {
+		t.Error("Check of disk GUID")
+	}
+	if err = backup.Restore(diskFile); err != nil {
+		t.Fatal(err)
+	}
+	if restored, _ := ioutil.ReadFile(diskFile.Name()); !bytes.Equal(restored, original) {
+		t.Error("Restored disk differ from original")
+	}
+
+	// msdos with logical partitions
+	disk = diskInfo{Path: "/dev/sda", PartTable: "msdos", Size: diskSize, SectorSizeLogical: 512, ExtendedNumber: 2,
+		Partitions: []partition{{Number: 2, FirstByte: 65536, LastByte: diskSize - 1}},
+		LogicalPartitions: []partition{
+			{Number: 5, FirstByte: 65536 + 512, LastByte: 131071, Logical: true, EBRByte: 65536},
+			{Number: 6, FirstByte: 131072 + 512, LastByte: 196607, Logical: true, EBRByte: 131072},
+		}}
+	backup, err = makeTableBackup(diskFile, disk, []uint64{196608})
+	if err != nil {
+		t.Fatal(err)
+	}
+	regions = nil
+	for _, region := range backup.Regions {
+		regions = append(regions, region.Name+":"+strconv.FormatUint(region.Offset, 10)+"+"+strconv.Itoa(len(region.Data)))
+	}
+	need = []string{"mbr:0+512", "ebr:65536+512", "ebr:131072+512", "new:196608+512"}
+	if !reflect.DeepEqual(regions, need) {
+		t.Error(regions)
+	}
+}
+
+func TestExtendDoFailedDependency(t *testing.T) {
+	plan := []storageItem{
+		{Type: type_UNKNOWN, Path: "/dev/unknown", Child: 1},
+		{Type: type_SKIP, Path: "/dev/skip1", Child: 2},
+		{Type: type_SKIP, Path: "/dev/skip2", Child: -1},
+		{Type: type_SKIP, Path: "/dev/independent", Child: -1},
+		{Type: type_SWAP_MOVE, Path: "/dev/swap", Child: -1, Partition: partition{Disk: &diskInfo{Path: "/not-existed-disk"}}},
+		{Type: type_SWAP_CREATE, Path: "/dev/swap", Child: -1},
+	}
+	res := extendDo(plan, doOptions{BackupDir: "/not-existed-dir"})
+	need := []stepStatus{step_FAILED, step_DEPENDENCY_FAILED, step_DEPENDENCY_FAILED, step_DONE, step_FAILED, step_DEPENDENCY_FAILED}
+	if len(res.Steps) != len(need) {
+		t.Fatal(res.Steps)
+	}
+	for i, step := range res.Steps {
+		if step.Status != need[i] {
+			t.Error(i, step.Status, need[i], step.Err)
+		}
+		if (step.Err == nil) != (step.Status == step_DONE) {
+			t.Error(i, step.Err)
+		}
+		if step.Item.Path != plan[i].Path {
+			t.Error(i, step.Item)
+		}
+	}
+	if !res.Failed() || res.NeedReboot {
+		t.Error(res)
+	}
+	if res.Steps[0].Err.Error() != "Unknown item type: type_UNKNOWN /dev/unknown" {
+		t.Error(res.Steps[0].Err)
+	}
+
+	res = extendDo(plan[3:4], doOptions{})
+	if res.Failed() || res.Steps[0].Status != step_DONE {
+		t.Error(res)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{nil, exit_OK},
+		{errNothingToExtend, exit_NOTHING_TO_EXTEND},
+		{usageError{errors.New("usage")}, exit_USAGE},
+		{scanError{errors.New("scan")}, exit_SCAN},
+		{fmt.Errorf("Error while make extend plan: %w", planError{errors.New("plan")}), exit_PLAN},
+		{doError{errors.New("do")}, exit_PARTIAL},
+		{errors.New("other"), exit_ERROR},
+	}
+	for _, test := range cases {
+		if code := exitCode(test.err); code != test.code {
+			t.Error(test.err, code, test.code)
+		}
+	}
+
+	if _, err := extendScanWays("/not-existed-start-point"); exitCode(err) != exit_USAGE {
+		t.Error(err)
+	}
+	if _, err := extendPlan(nil, planOptions{Filter: "["}); exitCode(err) != exit_USAGE {
+		t.Error(err)
+	}
+
+	res := doResult{Steps: []stepResult{{Status: step_DONE}, {Status: step_FAILED}}}
+	if err := res.Err(); exitCode(err) != exit_PARTIAL {
+		t.Error(err)
+	}
+}
+
+func TestPlanHasGrowth(t *testing.T) {
+	plan := []storageItem{
+		{Type: type_SKIP, Path: "/dev/sda1", Child: 1, FreeSpace: 100},
+		{Type: type_FS, Path: "/dev/sda1", Child: -1, Size: 1000},
+	}
+	if planHasGrowth(plan) {
+		t.Error("Skipped items doesn't grow")
+	}
+	plan[1].FreeSpace = 100
+	plan[1].Limit = 1000
+	if planHasGrowth(plan) {
+		t.Error("Target size reached")
+	}
+	plan[1].Limit = 0
+	if !planHasGrowth(plan) {
+		t.Error("Filesystem has free space")
+	}
+	plan[1].FreeSpace = 0
+	plan[0].Type = type_PARTITION_NEW
+	if !planHasGrowth(plan) {
+		t.Error("New partition")
+	}
+}
+
+func TestMergePlans(t *testing.T) {
+	const MiB = 1024 * 1024
+	const GiB = 1024 * MiB
+	disk := &diskInfo{Path: "/dev/sda", PartTable: "gpt"}
+	makePlan := func(lv string) []storageItem {
+		return []storageItem{
+			{Type: type_PARTITION, Path: "/dev/sda1", Child: 2, Size: 90 * GiB, FreeSpace: 10 * GiB,
+				Partition: partition{Disk: disk, Path: "/dev/sda1", Number: 1}},
+			{Type: type_LVM_PV_ADD, Path: "/dev/sdb1", Child: 3, FreeSpace: 20 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_PV, Path: "/dev/sda1", Child: 3, Size: 90 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_GROUP, Path: "vg" + lv, Child: 4, Size: 90 * GiB, FreeSpace: 2 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_LV, Path: "vg" + lv + "/" + lv, Child: 5, Size: 40 * GiB},
+			{Type: type_FS, FSType: "xfs", Path: "/dev/mapper/vg" + lv + "-" + lv, Child: -1, Size: 40 * GiB},
+		}
+	}
+
+	// Two LVs of one volume group: shared layers merge, group extends both LVs
+	// Два LV одной группы томов: общие слои объединяются, группа расширяет оба LV
+	home, varPlan := makePlan("home"), makePlan("var")
+	varPlan[3].Path = "vghome"
+	varPlan[4].Path = "vghome/var"
+	varPlan[5].Path = "/dev/mapper/vghome-var"
+	plan, lvStartPoints := mergePlans([]string{"/home", "/var"}, [][]storageItem{home, varPlan})
+	if len(plan) != 8 {
+		t.Fatal(plan)
+	}
+	if plan[3].Type != type_LVM_GROUP || !reflect.DeepEqual(planChildren(plan, 3), []int{4, 6}) {
+		t.Error(plan)
+	}
+	if plan[6].Path != "vghome/var" || plan[6].Child != 7 || lvStartPoints[6] != "/var" || lvStartPoints[4] != "/home" {
+		t.Error(plan, lvStartPoints)
+	}
+	grow := planGrowth(plan)
+	if grow[4] != 32*GiB || grow[6] != 32*GiB {
+		t.Error(formatSize(grow[4]), formatSize(grow[6]))
+	}
+
+	planDivideVG(plan, lvStartPoints, vgPolicy{})
+	grow = planGrowth(plan)
+	if grow[4] != 16*GiB || grow[6] != 16*GiB || grow[5] != 16*GiB {
+		t.Error(formatSize(grow[4]), formatSize(grow[6]))
+	}
+
+	// Different volume groups: free PV and partition uses for first start point only
+	// Разные группы томов: свободный PV и раздел используются только для первой точки старта
+	plan, _ = mergePlans([]string{"/home", "/var"}, [][]storageItem{makePlan("home"), makePlan("var")})
+	var skipped []string
+	for _, item := range plan {
+		if item.Type == type_SKIP {
+			skipped = append(skipped, item.Path)
+		}
+	}
+	if len(plan) != 9 || len(skipped) != 0 {
+		t.Error(plan)
+	}
+	for i, item := range plan {
+		if item.Child != -1 && item.Child <= i {
+			t.Error("Child must be after item", i, item)
+		}
+	}
+	grow = planGrowth(plan)
+	if grow[planTarget(plan)] != 32*GiB || grow[len(plan)-1] != 2*GiB {
+		t.Error(plan)
+	}
+}
+
+func TestPlanLayersOrder(t *testing.T) {
+	plan := []storageItem{
+		{Type: type_SWAP_CREATE, Path: "/dev/sda3", Child: -1},
+		{Type: type_LVM_GROUP, Path: "vg", Child: 2},
+		{Type: type_LVM_LV, Path: "vg/home", Child: 3},
+		{Type: type_FS, Path: "/dev/mapper/vg-home", Child: -1},
+		{Type: type_LVM_PV_ADD, Path: "/dev/sdb1", Child: 1},
+	}
+	if order := planLayersOrder(plan); !reflect.DeepEqual(order, []int{4, 1, 2, 3, 0}) {
+		t.Error(order)
+	}
+	res, indexes := planReorder(plan, []int{4, 1, 2, 3, 0})
+	if res[0].Child != 1 || res[1].Child != 2 || res[4].Child != -1 || indexes[4] != 0 {
+		t.Error(res, indexes)
+	}
+}
+
+func TestVGPolicy(t *testing.T) {
+	const MiB = 1024 * 1024
+	const GiB = 1024 * MiB
+	makePlan := func() []storageItem {
+		return []storageItem{
+			{Type: type_LVM_GROUP, Path: "vg", Child: 1, Size: 100 * GiB, FreeSpace: 30 * GiB, LVMExtentSize: 4 * MiB},
+			{Type: type_LVM_LV, Path: "vg/home", Child: 2, Size: 60 * GiB},
+			{Type: type_FS, Path: "/dev/mapper/vg-home", Child: -1, Size: 60 * GiB},
+			{Type: type_LVM_LV, Path: "vg/var", Child: 4, Size: 10 * GiB},
+			{Type: type_FS, Path: "/dev/mapper/vg-var", Child: -1, Size: 10 * GiB},
+		}
+	}
+	lvStartPoints := map[int]string{1: "/home", 3: "/var"}
+	for policyString, need := range map[string][2]uint64{
+		"equal":        {15 * GiB, 15 * GiB},
+		"proportional": {30 * GiB * 6 / 7 / (4 * MiB) * (4 * MiB), 30 * GiB / 7 / (4 * MiB) * (4 * MiB)},
+		"/home=2":      {20 * GiB, 10 * GiB},
+		"/var=0":       {30 * GiB, 0},
+		"vg/var=3":     {30 * GiB / 4 / (4 * MiB) * (4 * MiB), 30 * GiB * 3 / 4 / (4 * MiB) * (4 * MiB)},
+	} {
+		policy, err := parseVGPolicy(policyString)
+		if err != nil {
+			t.Error(policyString, err)
+			continue
+		}
+		plan := makePlan()
+		planDivideVG(plan, lvStartPoints, policy)
+		grow := planGrowth(plan)
+		if grow[2] != need[0] || grow[4] != need[1] {
+			t.Error(policyString, formatSize(grow[2]), formatSize(grow[4]))
+		}
+		// Growth of group is sum of rounded shares
+		// Рост группы - сумма округлённых долей
+		if grow[0] != need[0]+need[1] || plan[0].FreeSpace != need[0]+need[1] {
+			t.Error(policyString, formatSize(grow[0]), formatSize(plan[0].FreeSpace))
+		}
+	}
+	for _, s := range []string{"/home", "/home=-1", "=1", "/home=a", "/home=4294967296",
+		"/a=18446744073709551615,/b=2"} {
+		if _, err := parseVGPolicy(s); err == nil {
+			t.Error(s)
+		}
+	}
+	if policy, _ := parseVGPolicy("/var=1,/home=2"); policy.String() != "/home=2,/var=1" {
+		t.Error(policy)
+	}
+}
+
+func TestFilterStartPoints(t *testing.T) {
+	mounts := parseMountInfo([]byte(`17 1 8:1 / / rw - ext4 /dev/sda1 rw
+18 17 0:5 / /proc rw - proc proc rw
+19 17 8:2 / /home rw - xfs /dev/sda2 rw
+20 17 8:2 / /srv rw - xfs /dev/sda2 rw
+21 17 8:2 /data /data rw - xfs /dev/sda2 rw
+22 17 8:3 / /mnt rw - ext4 /dev/sda3 rw
+23 22 8:4 / /mnt rw - btrfs /dev/sda4 rw
+24 17 8:5 / /boot rw - ext2 /dev/sda5 rw
+`))
+	if res := filterStartPoints(mounts); !reflect.DeepEqual(res, []string{"/", "/home", "/boot"}) {
+		t.Error(res)
+	}
+	if res := appendUniqueStrings([]string{"/", "/home"}, "/home", "/var"); !reflect.DeepEqual(res, []string{"/", "/home", "/var"}) {
+		t.Error(res)
+	}
+}
+
+type testLayer struct {
+	addOnlyScan
+	noFreeSpace
+	applied []int
+}
+
+func (l *testLayer) Describe(item storageItem) string {
+	return "test layer " + item.Path
+}
+
+func (l *testLayer) Apply(step *layerStep) error {
+	l.applied = append(l.applied, step.Index)
+	if step.Item().Path == "/dev/fail" {
+		return stepError("Test fail:", step.Item().Path)
+	}
+	step.GrowChild(step.Item().FreeSpace)
+	step.Item().Size += step.Item().FreeSpace
+	step.Item().FreeSpace = 0
+	return nil
+}
+
+func TestLayerRegistry(t *testing.T) {
+	oldLayer := layers[type_DISK]
+	defer func() { layers[type_DISK] = oldLayer }()
+	l := &testLayer{}
+	layers[type_DISK] = l
+
+	plan := []storageItem{
+		{Type: type_DISK, Path: "/dev/fail", Child: 1},
+		{Type: type_DISK, Path: "/dev/parent", Child: 2, FreeSpace: 10},
+		{Type: type_DISK, Path: "/dev/child", Child: -1},
+	}
+	if layerOf(plan[0]) != l {
+		t.Error(layerOf(plan[0]))
+	}
+	if layerOf(storageItem{Type: type_FS, FSType: "unknown-fs"}) != nil {
+		t.Error("Layer of unknown fs")
+	}
+	if layerOf(storageItem{Type: type_FS, FSType: "ext4"}) == nil {
+		t.Error("Hasn't layer of ext4")
+	}
+	if s := l.Describe(plan[1]); s != "test layer /dev/parent" {
+		t.Error(s)
+	}
+
+	res := extendDo(plan, doOptions{})
+	need := []stepStatus{step_FAILED, step_DEPENDENCY_FAILED, step_DEPENDENCY_FAILED}
+	for i, step := range res.Steps {
+		if step.Status != need[i] {
+			t.Error(i, step.Status, need[i], step.Err)
+		}
+	}
+	if res.Steps[0].Err == nil || res.Steps[0].Err.Error() != "Test fail: /dev/fail" {
+		t.Error(res.Steps[0].Err)
+	}
+
+	l.applied = nil
+	plan = plan[1:]
+	plan[0].Child = 1
+	res = extendDo(plan, doOptions{})
+	if res.Failed() || len(l.applied) != 2 || res.Steps[1].Item.Size != 10 {
+		t.Error(res, l.applied)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Register layer twice without panic")
+			}
+		}()
+		registerLayer(type_DISK, l)
+	}()
+}
+
+func TestReplayRunner(t *testing.T) {
+	r := &replayRunner{Records: []commandRecord{
+		{Name: "lvs", Args: []string{"vg"}, Stdout: "1"},
+		{Name: "lvs", Args: []string{"vg"}, Stdout: "2"},
+		{Name: "lvresize", Args: []string{"vg/lv"}, Stderr: "fail", Error: "exit status 5"},
+	}}
+	for _, need := range []string{"1", "2", "2"} {
+		if stdout, _, err := r.Run("lvs", "vg"); stdout != need || err != nil {
+			t.Error(stdout, need, err)
+		}
+	}
+	if _, stderr, err := r.Run("lvresize", "vg/lv"); stderr != "fail" || err == nil || err.Error() != "exit status 5" {
+		t.Error(stderr, err)
+	}
+	if _, _, err := r.Run("lvs", "vg2"); err == nil || len(r.Missed) != 1 || r.Missed[0] != "lvs vg2" {
+		t.Error(err, r.Missed)
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "commands.json")
+	rec := &recordRunner{Runner: &replayRunner{Records: r.Records}, Path: path}
+	rec.Run("lvs", "vg")
+	rec.Run("lvresize", "vg/lv")
+	replay, err := newReplayRunner(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replay.Records, []commandRecord{r.Records[0], r.Records[2]}) {
+		t.Error(replay.Records)
+	}
+}
+
+func TestReplayLVM(t *testing.T) {
+	const MiB = 1024 * 1024
+	const GiB = 1024 * MiB
+
+	replay, err := newReplayRunner("testdata/commands-lvm.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldRunner := runner
+	defer func() { runner = oldRunner }()
+	runner = replay
+
+	if major, minor := getMajorMinor("/dev/vg/root"); major != 253 || minor != 0 {
+		t.Error(major, minor)
+	}
+	scanLVM()
+	if item := majorMinorDeviceTypeCache[[2]int{253, 1}]; item.Path != "vg/swap" || item.Type != type_LVM_LV || item.Size != 1*GiB {
+		t.Error(item)
+	}
+	needPV := []lvmPV{{Path: "/dev/sda2", VolumeGroup: "vg", Size: 21470642176}, {Path: "/dev/sdc", Size: 10 * GiB}}
+	if pvs := getLvmPV(); !reflect.DeepEqual(pvs, needPV) {
+		t.Error(pvs)
+	}
+	if size := lvmPVGetSize("/dev/sda2"); size != 21470642176 {
+		t.Error(size)
+	}
+	if size, free, extent := lvmVGGetSize("vg"); size != 21470642176 || free != 9659482112 || extent != 4*MiB {
+		t.Error(size, free, extent)
+	}
+
+	plan := []storageItem{{Type: type_LVM_LV, Path: "vg/root", Child: -1, Size: lvmLVGetSize("vg/root"), FreeSpace: 9659482112}}
+	if err = (lvmLVLayer{}).Apply(&layerStep{Plan: plan, Index: 0}); err != nil {
+		t.Error(err)
+	}
+	if plan[0].Size != 20396900352 || plan[0].FreeSpace != 0 {
+		t.Error(plan[0])
+	}
+	if len(replay.Missed) != 0 {
+		t.Error(replay.Missed)
+	}
+}
+
+// Scan and plan of ext4 on msdos partition by recorded session of commands. Device files, sysfs and procfs are fixture
+// tree, paths of devices in the session moved to the tree.
+// Сканирование и план для ext4 на разделе msdos по записанному сеансу команд. Файлы устройств, sysfs и procfs -
+// подготовленное дерево, пути устройств в сеансе перенесены в это дерево.
+func TestReplayExtendPlan(t *testing.T) {
+	const MiB = 1024 * 1024
+	const GiB = 1024 * MiB
+
+	root, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	oldSysfsRoot, oldDevRoot, oldProcRoot := sysfsRoot, devRoot, procRoot
+	defer func() { sysfsRoot, devRoot, procRoot = oldSysfsRoot, oldDevRoot, oldProcRoot }()
+	sysfsRoot = filepath.Join(root, "sys")
+	devRoot = filepath.Join(root, "dev")
+	procRoot = filepath.Join(root, "proc")
+
+	// 10GiB disk with 1GiB partition from 1MiB, root mounted as /dev/root
+	// Диск 10ГиБ с разделом 1ГиБ с 1МиБ, корень смонтирован как /dev/root
+	createSysfsFixture(t, sysfsRoot, map[string]map[string]string{
+		"sda":  {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda", "dev": "8:0", "size": "20971520", "queue/logical_block_size": "512"},
+		"sda1": {"path": "devices/pci0000:00/0000:00:10.0/host0/target0:0:0/0:0:0:0/block/sda/sda1", "dev": "8:1", "size": "2097152", "partition": "1", "start": "2048"},
+	})
+	os.MkdirAll(filepath.Join(procRoot, "self"), 0700)
+	os.MkdirAll(devRoot, 0700)
+	files := map[string][]byte{
+		filepath.Join(procRoot, "self", "mountinfo"): []byte("22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/root rw\n"),
+		filepath.Join(procRoot, "swaps"):             []byte("Filename\tType\tSize\tUsed\tPriority\n"),
+	}
+	mbrTable, _ := mbr.Read(bytes.NewReader(make([]byte, 512)))
+	mbrTable.FixSignature()
+	part := mbrTable.GetPartition(1)
+	part.SetType(mbr.PartitionType(0x83))
+	part.SetLBAStart(2048)
+	part.SetLBALen(2097152)
+	buf := &bytes.Buffer{}
+	mbrTable.Write(buf)
+	files[filepath.Join(devRoot, "sda")] = buf.Bytes()
+	fs := make([]byte, ext_SUPERBLOCK_OFFSET+ext_SUPERBLOCK_SIZE)
+	le := binary.LittleEndian
+	le.PutUint32(fs[ext_SUPERBLOCK_OFFSET+ext_OFFSET_BLOCKS_COUNT_LO:], 1*GiB/4096)
+	le.PutUint32(fs[ext_SUPERBLOCK_OFFSET+ext_OFFSET_LOG_BLOCK_SIZE:], 2)
+	le.PutUint16(fs[ext_SUPERBLOCK_OFFSET+ext_OFFSET_MAGIC:], ext_MAGIC)
+	le.PutUint16(fs[ext_SUPERBLOCK_OFFSET+ext_OFFSET_STATE:], ext_STATE_VALID)
+	le.PutUint32(fs[ext_SUPERBLOCK_OFFSET+ext_OFFSET_REV_LEVEL:], ext_DYNAMIC_REV)
+	le.PutUint32(fs[ext_SUPERBLOCK_OFFSET+ext_OFFSET_FEATURE_INCOMPAT:], 0x0040) // extents
+	files[filepath.Join(devRoot, "sda1")] = fs
+	for path, content := range files {
+		if err = ioutil.WriteFile(path, content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replay, err := newReplayRunner("testdata/commands-ext4.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range replay.Records {
+		for j, arg := range replay.Records[i].Args {
+			if strings.HasPrefix(arg, "/dev/") {
+				replay.Records[i].Args[j] = filepath.Join(devRoot, strings.TrimPrefix(arg, "/dev/"))
+			}
+		}
+	}
+	oldRunner := runner
+	defer func() { runner = oldRunner }()
+	runner = replay
+	resetProgramState()
+
+	storage, err := extendScanWays("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := extendPlan(storage, planOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 2 || plan[0].Type != type_PARTITION || plan[0].Partition.Number != 1 ||
+		plan[0].Partition.Disk.Path != filepath.Join(devRoot, "sda") || plan[1].Type != type_FS || plan[1].FSType != "ext4" ||
+		plan[1].Path != filepath.Join(devRoot, "sda1") {
+		t.Fatalf("%# v", pretty.Formatter(plan))
+	}
+	// Partition grows to end of disk, filesystem - to new size of the partition
+	// Раздел растёт до конца диска, файловая система - до нового размера раздела
+	if grow := planGrowth(plan); plan[1].Size != 1*GiB || grow[0] != 9*GiB-1*MiB || grow[1] != 9*GiB-1*MiB {
+		t.Error(formatSize(plan[1].Size), formatSize(grow[0]), formatSize(grow[1]))
+	}
+	if len(replay.Missed) != 0 {
+		t.Error(replay.Missed)
+	}
+}
+
+func TestCryptApplyLimit(t *testing.T) {
+	const MiB = 1024 * 1024
+
+	replay := &replayRunner{Records: []commandRecord{
+		{Name: "cryptsetup", Args: []string{"resize", "--size", "6144", "crypt"}},
+		// Underliing device grew while resize, device is more then planned
+		// Нижележащее устройство выросло при изменении, устройство больше запланированного
+		{Name: "blockdev", Args: []string{"--getsize64", "/dev/mapper/crypt"}, Stdout: "4194304\n"},
+	}}
+	oldRunner := runner
+	defer func() { runner = oldRunner }()
+	runner = replay
+
+	plan := []storageItem{
+		{Type: type_CRYPT, Path: "/dev/mapper/crypt", Child: 1, Size: 2 * MiB, FreeSpace: 1 * MiB, Limit: 3 * MiB},
+		{Type: type_FS, FSType: "ext4", Path: "/dev/mapper/crypt", Child: -1, Size: 2 * MiB},
+	}
+	if err := (cryptLayer{}).Apply(&layerStep{Plan: plan, Index: 0}); err != nil {
+		t.Error(err)
+	}
+	if plan[0].Size != 4*MiB || plan[0].FreeSpace != 0 || plan[1].FreeSpace != 2*MiB {
+		t.Error(plan)
+	}
+	if len(replay.Missed) != 0 {
+		t.Error(replay.Missed)
+	}
+}
+
+func TestExtSuperblock(t *testing.T) {
+	// Crafted image of filesystem start: boot block and superblock
+	// Созданный образ начала файловой системы: загрузочный блок и суперблок
+	makeImage := func(logBlockSize, blocksLo, blocksHi, incompat uint32, rev uint32) []byte {
+		img := make([]byte, ext_SUPERBLOCK_OFFSET+ext_SUPERBLOCK_SIZE)
+		sb := img[ext_SUPERBLOCK_OFFSET:]
+		le := binary.LittleEndian
+		le.PutUint32(sb[ext_OFFSET_BLOCKS_COUNT_LO:], blocksLo)
+		le.PutUint32(sb[ext_OFFSET_LOG_BLOCK_SIZE:], logBlockSize)
+		le.PutUint16(sb[ext_OFFSET_MAGIC:], ext_MAGIC)
+		le.PutUint16(sb[ext_OFFSET_STATE:], ext_STATE_VALID)
+		le.PutUint32(sb[ext_OFFSET_REV_LEVEL:], rev)
+		le.PutUint32(sb[ext_OFFSET_FEATURE_COMPAT:], ext_FEATURE_COMPAT_HAS_JOURNAL|ext_FEATURE_COMPAT_RESIZE_INODE)
+		le.PutUint32(sb[ext_OFFSET_FEATURE_INCOMPAT:], incompat)
+		copy(sb[ext_OFFSET_UUID:], []byte{0x0f, 0x8a, 0x2e, 0x3c, 0x5d, 0x1b, 0x4c, 0x7e, 0x9a, 0x1f, 0x2b, 0x3c, 0x4d, 0x5e, 0x6f, 0x70})
+		copy(sb[ext_OFFSET_VOLUME_NAME:], "root")
+		copy(sb[ext_OFFSET_LAST_MOUNTED:], "/")
+		le.PutUint32(sb[ext_OFFSET_BLOCKS_COUNT_HI:], blocksHi)
+		return img
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	read := func(img []byte) (extSuperblock, error) {
+		path := filepath.Join(dir, "ext.img")
+		if err := ioutil.WriteFile(path, img, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return readExtSuperblock(path)
+	}
+
+	// ext4 with 32-bit block count, 4KiB blocks
+	sb, err := read(makeImage(2, 2621440, 0, 0, ext_DYNAMIC_REV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.BlockSize != 4096 || sb.BlockCount != 2621440 || sb.Size() != 10*1024*1024*1024 {
+		t.Error(sb)
+	}
+	if sb.Is64bit() || !sb.HasResizeInode() || sb.HasMetaBG() || !sb.HasJournal() || !sb.IsClean() || sb.HasErrors() {
+		t.Error(sb)
+	}
+	if sb.Label != "root" || sb.LastMounted != "/" || sb.UUIDString() != "0f8a2e3c-5d1b-4c7e-9a1f-2b3c4d5e6f70" {
+		t.Error(sb.Label, sb.LastMounted, sb.UUIDString())
+	}
+	if sb.MaxSize() != (1<<32-1)*4096 {
+		t.Error(sb.MaxSize())
+	}
+
+	// High bits of block count ignored without 64bit feature
+	// Старшие биты количества блоков игнорируются без возможности 64bit
+	sb, err = read(makeImage(2, 1, 3, 0, ext_DYNAMIC_REV))
+	if err != nil || sb.BlockCount != 1 {
+		t.Error(sb, err)
+	}
+
+	sb, err = read(makeImage(2, 1, 3, ext_FEATURE_INCOMPAT_64BIT|ext_FEATURE_INCOMPAT_META_BG, ext_DYNAMIC_REV))
+	if err != nil || sb.BlockCount != 3<<32+1 || !sb.Is64bit() || !sb.HasMetaBG() || sb.Size() != (3<<32+1)*4096 {
+		t.Error(sb, err)
+	}
+
+	// ext2 revision 0: 1KiB blocks, without features and label
+	// ext2 версии 0: блоки 1КиБ, без возможностей и метки
+	sb, err = read(makeImage(0, 1000, 3, ext_FEATURE_INCOMPAT_64BIT, 0))
+	if err != nil || sb.BlockSize != 1024 || sb.BlockCount != 1000 || sb.FeatureCompat != 0 || sb.Label != "" {
+		t.Error(sb, err)
+	}
+
+	img := makeImage(2, 1, 0, 0, ext_DYNAMIC_REV)
+	img[ext_SUPERBLOCK_OFFSET+ext_OFFSET_MAGIC] = 0
+	if _, err = read(img); err == nil {
+		t.Error("Bad magic without error")
+	}
+	img = makeImage(2, 1, 0, 0, ext_DYNAMIC_REV)
+	img[ext_SUPERBLOCK_OFFSET+ext_OFFSET_LOG_BLOCK_SIZE] = 20
+	if _, err = read(img); err == nil {
+		t.Error("Bad block size without error")
+	}
+	if _, err = read(img[:ext_SUPERBLOCK_OFFSET+100]); err == nil {
+		t.Error("Short image without error")
+	}
+	if size, err := fsGetSizeExt(filepath.Join(dir, "not-exist")); err == nil {
+		t.Error(size)
+	}
+}
+
+func TestXFSSuperblock(t *testing.T) {
+	makeImage := func(blockSize uint32, dataBlocks uint64, version uint16) []byte {
+		img := make([]byte, 4096)
+		be := binary.BigEndian
+		be.PutUint32(img[xfs_OFFSET_MAGIC:], xfs_MAGIC)
+		be.PutUint32(img[xfs_OFFSET_BLOCK_SIZE:], blockSize)
+		be.PutUint64(img[xfs_OFFSET_DATA_BLOCKS:], dataBlocks)
+		copy(img[xfs_OFFSET_UUID:], []byte{0x0f, 0x8a, 0x2e, 0x3c, 0x5d, 0x1b, 0x4c, 0x7e, 0x9a, 0x1f, 0x2b, 0x3c, 0x4d, 0x5e, 0x6f, 0x70})
+		be.PutUint32(img[xfs_OFFSET_AG_BLOCKS:], uint32(dataBlocks/4))
+		be.PutUint32(img[xfs_OFFSET_AG_COUNT:], 4)
+		be.PutUint16(img[xfs_OFFSET_VERSION:], version)
+		be.PutUint16(img[xfs_OFFSET_SECTOR_SIZE:], 512)
+		copy(img[xfs_OFFSET_FNAME:], "data")
+		be.PutUint32(img[xfs_OFFSET_FEATURES2:], 0x18a)
+		be.PutUint32(img[xfs_OFFSET_FEATURES_INCOMPAT:], 0x3)
+		return img
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "xfs.img")
+	read := func(img []byte) (xfsSuperblock, error) {
+		if err := ioutil.WriteFile(path, img, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return readXFSSuperblock(path)
+	}
+
+	sb, err := read(makeImage(4096, 26240000, 0xb4a5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.BlockSize != 4096 || sb.DataBlocks != 26240000 || sb.AGCount != 4 || sb.AGBlocks != 6560000 || sb.SectorSize != 512 {
+		t.Error(sb)
+	}
+	if sb.VersionNum() != 5 || sb.Features2 != 0x18a || sb.FeaturesIncompat != 0x3 || sb.InProgress {
+		t.Error(sb)
+	}
+	if sb.Label != "data" || sb.UUIDString() != "0f8a2e3c-5d1b-4c7e-9a1f-2b3c4d5e6f70" {
+		t.Error(sb.Label, sb.UUIDString())
+	}
+	if size, err := fsGetSizeXFS(path); size != 26240000*4096 || err != nil {
+		t.Error(size, err)
+	}
+
+	// Version 4 hasn't feature fields of version 5
+	// В версии 4 нет полей возможностей версии 5
+	sb, err = read(makeImage(512, 100, 0x34a4))
+	if err != nil || sb.VersionNum() != 4 || sb.FeaturesIncompat != 0 || sb.Size() != 51200 {
+		t.Error(sb, err)
+	}
+
+	img := makeImage(4096, 1, 0xb4a5)
+	img[xfs_OFFSET_IN_PROGRESS] = 1
+	if sb, err = read(img); err != nil || !sb.InProgress {
+		t.Error(sb, err)
+	}
+	if _, err = read(makeImage(3000, 1, 0xb4a5)); err == nil {
+		t.Error("Bad block size without error")
+	}
+	img = makeImage(4096, 1, 0xb4a5)
+	img[xfs_OFFSET_MAGIC] = 0
+	if _, err = read(img); err == nil {
+		t.Error("Bad magic without error")
+	}
+	if _, err = read(img[:100]); err == nil {
+		t.Error("Short image without error")
+	}
+}
+
+func TestProbeFS(t *testing.T) {
+	const size = 1024 * 1024
+	uuid := []byte{0x0f, 0x8a, 0x2e, 0x3c, 0x5d, 0x1b, 0x4c, 0x7e, 0x9a, 0x1f, 0x2b, 0x3c, 0x4d, 0x5e, 0x6f, 0x70}
+	const uuidString = "0f8a2e3c-5d1b-4c7e-9a1f-2b3c4d5e6f70"
+	le := binary.LittleEndian
+	be := binary.BigEndian
+
+	ext := func(compat, incompat, roCompat uint32) []byte {
+		img := make([]byte, size)
+		sb := img[ext_SUPERBLOCK_OFFSET:]
+		le.PutUint16(sb[ext_OFFSET_MAGIC:], ext_MAGIC)
+		le.PutUint32(sb[ext_OFFSET_REV_LEVEL:], ext_DYNAMIC_REV)
+		le.PutUint32(sb[ext_OFFSET_FEATURE_COMPAT:], compat)
+		le.PutUint32(sb[ext_OFFSET_FEATURE_INCOMPAT:], incompat)
+		le.PutUint32(sb[ext_OFFSET_FEATURE_RO:], roCompat)
+		copy(sb[ext_OFFSET_UUID:], uuid)
+		copy(sb[ext_OFFSET_VOLUME_NAME:], "root")
+		return img
+	}
+
+	xfs := make([]byte, size)
+	be.PutUint32(xfs[xfs_OFFSET_MAGIC:], xfs_MAGIC)
+	be.PutUint32(xfs[xfs_OFFSET_BLOCK_SIZE:], 4096)
+	copy(xfs[xfs_OFFSET_UUID:], uuid)
+	copy(xfs[xfs_OFFSET_FNAME:], "data")
+
+	btrfs := make([]byte, size)
+	copy(btrfs[64*1024+0x20:], uuid)
+	copy(btrfs[64*1024+0x40:], "_BHRfS_M")
+	copy(btrfs[64*1024+0x12b:], "pool")
+
+	swap := make([]byte, size)
+	copy(swap[4096-10:], "SWAPSPACE2")
+	copy(swap[1024+12:], uuid)
+	copy(swap[1024+28:], "swap")
+
+	lvm := make([]byte, size)
+	copy(lvm[512:], "LABELONE")
+	le.PutUint32(lvm[512+20:], 32)
+	copy(lvm[512+24:], "LVM2 001")
+	copy(lvm[512+32:], "qD3Tk9abcdefghijklmnopqrstuvABCD")
+
+	luks := make([]byte, size)
+	copy(luks, "LUKS\xba\xbe")
+	be.PutUint16(luks[6:], 2)
+	copy(luks[24:], "secret")
+	copy(luks[168:], uuidString)
+
+	raid := make([]byte, size)
+	le.PutUint32(raid[4096:], 0xa92b4efc)
+	le.PutUint32(raid[4096+4:], 1)
+	copy(raid[4096+16:], uuid)
+	copy(raid[4096+32:], "host:0")
+
+	raid090 := make([]byte, size)
+	le.PutUint32(raid090[size-64*1024:], 0xa92b4efc)
+	copy(raid090[size-64*1024+5*4:], uuid[0:4])
+	copy(raid090[size-64*1024+13*4:], uuid[4:16])
+
+	gpt := make([]byte, size)
+	gpt[510], gpt[511] = 0x55, 0xAA
+	copy(gpt[512:], "EFI PART")
+	copy(gpt[512+56:], []byte{0x3c, 0x2e, 0x8a, 0x0f, 0x1b, 0x5d, 0x7e, 0x4c, 0x9a, 0x1f, 0x2b, 0x3c, 0x4d, 0x5e, 0x6f, 0x70})
+
+	mbr := make([]byte, size)
+	mbr[510], mbr[511] = 0x55, 0xAA
+
+	// ext with boot sector signature is filesystem, not partition table
+	// ext с сигнатурой загрузочного сектора - файловая система, а не таблица разделов
+	extBoot := ext(0, 0, 0)
+	extBoot[510], extBoot[511] = 0x55, 0xAA
+
+	tests := []struct {
+		name string
+		img  []byte
+		need probeResult
+	}{
+		{"ext2", ext(0, 0x2, 0x1), probeResult{Type: "ext2", UUID: uuidString, Label: "root"}},
+		{"ext3", ext(ext_FEATURE_COMPAT_HAS_JOURNAL, 0x2, 0x1), probeResult{Type: "ext3", UUID: uuidString, Label: "root"}},
+		{"ext4", ext(ext_FEATURE_COMPAT_HAS_JOURNAL, 0x2|0x40, 0x1), probeResult{Type: "ext4", UUID: uuidString, Label: "root"}},
+		{"ext4 ro", ext(ext_FEATURE_COMPAT_HAS_JOURNAL, 0x2, 0x400), probeResult{Type: "ext4", UUID: uuidString, Label: "root"}},
+		{"ext boot", extBoot, probeResult{Type: "ext2", UUID: uuidString, Label: "root"}},
+		{"xfs", xfs, probeResult{Type: "xfs", UUID: uuidString, Label: "data"}},
+		{"btrfs", btrfs, probeResult{Type: "btrfs", UUID: uuidString, Label: "pool"}},
+		{"swap", swap, probeResult{Type: "swap", UUID: uuidString, Label: "swap"}},
+		{"lvm", lvm, probeResult{Type: "LVM2_member", UUID: "qD3Tk9-abcd-efgh-ijkl-mnop-qrst-uvABCD"}},
+		{"luks", luks, probeResult{Type: "crypto_LUKS", UUID: uuidString, Label: "secret"}},
+		{"raid", raid, probeResult{Type: "linux_raid_member", UUID: uuidString, Label: "host:0"}},
+		{"raid 0.90", raid090, probeResult{Type: "linux_raid_member", UUID: uuidString}},
+		{"gpt", gpt, probeResult{PTType: "gpt", UUID: uuidString}},
+		{"mbr", mbr, probeResult{PTType: "dos"}},
+		{"empty", make([]byte, size), probeResult{}},
+		{"small", make([]byte, 100), probeResult{}},
+	}
+	for _, test := range tests {
+		res, err := probeReader(bytes.NewReader(test.img), uint64(len(test.img)))
+		if err != nil || res != test.need {
+			t.Errorf("%v: %#v (%v), need %#v", test.name, res, err, test.need)
+		}
+	}
+
+	// Device less then 128KiB hasn't 0.90 superblock, even if start of device looks like it
+	// У устройства меньше 128КиБ нет суперблока 0.90, даже если начало устройства похоже на него
+	for _, smallSize := range []int{64, 4096, 64 * 1024, 100 * 1024, 128*1024 - 1} {
+		small := make([]byte, smallSize)
+		le.PutUint32(small, 0xa92b4efc)
+		dev := &probeDevice{Head: small, Size: uint64(smallSize), r: bytes.NewReader(small)}
+		if res, ok := probeRaid(dev); ok {
+			t.Error(smallSize, res)
+		}
+	}
+
+	// Device less then 8KiB hasn't 1.0 superblock
+	// У устройства меньше 8КиБ нет суперблока 1.0
+	for _, smallSize := range []int{4 * 1024, 6 * 1024} {
+		small := make([]byte, smallSize)
+		dev := &probeDevice{Head: small, Size: uint64(smallSize), r: bytes.NewReader(small)}
+		if offsets := raidV1Offsets(dev.Size); len(offsets) != 2 {
+			t.Error(smallSize, offsets)
+		}
+		if res, ok := probeRaid(dev); ok {
+			t.Error(smallSize, res)
+		}
+	}
+	if offsets := raidV1Offsets(16 * 1024); len(offsets) != 3 || offsets[2] != 8*1024 {
+		t.Error(offsets)
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "swap.img")
+	if err = ioutil.WriteFile(path, swap, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := probeFS(path); err != nil || res.Type != "swap" {
+		t.Error(res, err)
+	}
+	if _, err = probeFS(filepath.Join(dir, "not-exist")); err == nil {
+		t.Error("Probe of not existed file without error")
+	}
+}
+
+func TestExt4ResizeFS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Block count 1 is less then any filesystem, so kernel rejects it on every filesystem
+	// Количество блоков 1 меньше любой файловой системы, поэтому ядро отклоняет его на любой файловой системе
+	err = ext4ResizeFS(dir, 1)
+	var resizeErr *ext4ResizeError
+	if !errors.As(err, &resizeErr) || resizeErr.MountPoint != dir || resizeErr.BlockCount != 1 || resizeErr.Errno == 0 {
+		t.Error(err)
+	}
+	if err = ext4ResizeFS(filepath.Join(dir, "not-exist"), 1); err == nil || errors.As(err, &resizeErr) {
+		t.Error(err)
+	}
+
+	err = &ext4ResizeError{MountPoint: "/home", BlockCount: 100, Errno: syscall.EINVAL}
+	if err.Error() != "EXT4_IOC_RESIZE_FS /home to 100 blocks: new size is less then current or more then max size of filesystem (invalid argument)" {
+		t.Error(err)
+	}
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Error(err)
+	}
+
+	// Unsupported resize falls back to resize2fs, other errors are reported
+	// Неподдерживаемое изменение размера переходит к resize2fs, остальные ошибки сообщаются
+	for _, errno := range []syscall.Errno{syscall.ENOTTY, syscall.EOPNOTSUPP, syscall.EINVAL} {
+		if !ext4ResizeUnsupported(&ext4ResizeError{MountPoint: "/home", Errno: errno}) {
+			t.Error(errno)
+		}
+	}
+	for _, err := range []error{nil, &ext4ResizeError{MountPoint: "/home", Errno: syscall.EROFS},
+		&ext4ResizeError{MountPoint: "/home", Errno: syscall.EBUSY}} {
+		if ext4ResizeUnsupported(err) {
+			t.Error(err)
+		}
+	}
+}
+
+func TestXFSIoctl(t *testing.T) {
+	// _IOC from linux asm-generic/ioctl.h
+	ioc := func(dir, nr, size uintptr) uintptr {
+		return dir<<30 | size<<16 | 'X'<<8 | nr
+	}
+	const iocWrite, iocRead = 1, 2
+	if ioctl_XFS_FSGEOMETRY != ioc(iocRead, 126, unsafe.Sizeof(xfsGeometry{})) ||
+		ioctl_XFS_FSGEOMETRY_V4 != ioc(iocRead, 124, xfs_FSGEOMETRY_V4_SIZE) ||
+		ioctl_XFS_FSGROWFSDATA != ioc(iocWrite, 110, unsafe.Sizeof(xfsGrowFSData{})) ||
+		ioctl_XFS_FSGROWFSLOG != ioc(iocWrite, 111, unsafe.Sizeof(xfsGrowFSLog{})) ||
+		ioctl_XFS_FSGROWFSRT != ioc(iocWrite, 112, unsafe.Sizeof(xfsGrowFSRT{})) {
+		t.Error("Bad xfs ioctl request")
+	}
+	var geo xfsGeometry
+	if unsafe.Sizeof(geo) != xfs_FSGEOMETRY_SIZE || unsafe.Offsetof(geo.Sick) != xfs_FSGEOMETRY_V4_SIZE ||
+		unsafe.Offsetof(geo.DataBlocks) != 32 || unsafe.Offsetof(geo.UUID) != 64 {
+		t.Error("Bad layout of xfs geometry")
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var ioctlErr *xfsIoctlError
+	if geo, err = xfsFSGeometry(dir); err == nil {
+		// Temp dir placed on xfs
+		// Временная папка расположена на xfs
+		if geo.BlockSize == 0 || geo.DataBlocks == 0 {
+			t.Error(geo)
+		}
+	} else if !errors.As(err, &ioctlErr) || ioctlErr.MountPoint != dir {
+		t.Error(err)
+	}
+
+	// Grow to 1 block is shrink on every xfs and bad request for other filesystems
+	// Расширение до 1 блока - уменьшение для любой xfs и неправильный запрос для других файловых систем
+	if err = xfsGrowData(dir, 1, 25); !errors.As(err, &ioctlErr) || ioctlErr.Op != "XFS_IOC_FSGROWFSDATA" {
+		t.Error(err)
+	}
+	if err = xfsGrowData(filepath.Join(dir, "not-exist"), 1, 25); err == nil || errors.As(err, &ioctlErr) {
+		t.Error(err)
+	}
+
+	err = &xfsIoctlError{Op: "XFS_IOC_FSGROWFSLOG", MountPoint: "/data", Errno: syscall.ENOSYS}
+	if err.Error() != "XFS_IOC_FSGROWFSLOG /data: function not implemented" || !errors.Is(err, syscall.ENOSYS) {
+		t.Error(err)
+	}
+	if _, ok := xfsGrowError(err).(fsFatalError); !ok {
+		t.Error("Not fatal error of xfs grow")
+	}
+	if _, ok := xfsGrowError(&xfsIoctlError{Errno: syscall.EAGAIN}).(fsFatalError); ok {
+		t.Error("Fatal error of concurrent xfs grow")
+	}
+	if err = xfsGrowSections(dir, xfsGeometry{}, doOptions{}); err != nil {
+		t.Error(err)
+	}
+	// Absent realtime section is skipped
+	// Отсутствующий realtime раздел пропускается
+	if err = xfsGrowSections(dir, xfsGeometry{BlockSize: 4096}, doOptions{XFSGrowRT: true}); err != nil {
+		t.Error(err)
+	}
+	// Internal log is skipped
+	// Внутренний журнал пропускается
+	if err = xfsGrowSections(dir, xfsGeometry{BlockSize: 4096, LogStart: 8}, doOptions{XFSGrowLog: true}); err != nil {
+		t.Error(err)
+	}
+
+	// Kernel refuses grow of log and realtime section by ENOSYS or EINVAL
+	// Ядро отказывает в расширении журнала и realtime раздела через ENOSYS или EINVAL
+	for _, errno := range []syscall.Errno{syscall.ENOSYS, syscall.EINVAL} {
+		if !xfsGrowRefused(&xfsIoctlError{Op: "XFS_IOC_FSGROWFSLOG", MountPoint: "/data", Errno: errno}, "log") {
+			t.Error(errno)
+		}
+	}
+	if xfsGrowRefused(&xfsIoctlError{Op: "XFS_IOC_FSGROWFSRT", MountPoint: "/data", Errno: syscall.EPERM}, "realtime section") {
+		t.Error("EPERM")
+	}
+}
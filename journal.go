@@ -0,0 +1,237 @@
+package fsextender
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Version of state file format
+// Версия формата файла состояния
+const journal_VERSION = 1
+
+/*
+State journal of extend. It writes to state file before and after every step of plan, so extend can be resumed
+after reboot.
+
+Журнал состояния расширения. Записывается в файл состояния перед и после каждого шага плана, чтобы расширение можно
+было продолжить после перезагрузки.
+*/
+type journal struct {
+	Version    int               `json:"version"`
+	StartPoint string            `json:"start_point"`
+	Plan       []storageItem     `json:"plan"`         // State of plan after last finished step. Состояние плана после последнего завершенного шага
+	Done       []int             `json:"done"`         // Indexes of finished steps. Индексы завершенных шагов
+	Current    int               `json:"current"`      // Index of running step, -1 - none. Индекс выполняемого шага, -1 - нет
+	RebootStep int               `json:"reboot_step"`  // Index of step, which need reboot, -1 - none. Индекс шага, которому нужна перезагрузка, -1 - нет
+	Sizes      map[string]uint64 `json:"sizes"`        // Sizes of partitions, which kernel see after steps. Размеры разделов, которые видит ядро после шагов
+	Swaps      map[string]bool   `json:"active_swaps"` // Swaps, disabled while move. Разделы подкачки, выключенные при перемещении
+	Complete   bool              `json:"complete"`
+
+	path string
+}
+
+func newJournal(path, startPoint string, plan []storageItem) *journal {
+	return &journal{
+		Version:    journal_VERSION,
+		StartPoint: startPoint,
+		Plan:       plan,
+		Current:    -1,
+		RebootStep: -1,
+		Sizes:      make(map[string]uint64),
+		Swaps:      make(map[string]bool),
+		path:       path,
+	}
+}
+
+// Read journal from state file
+// Читает журнал из файла состояния
+func readJournal(path string) (*journal, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	res := &journal{path: path}
+	if err = json.Unmarshal(content, res); err != nil {
+		return nil, fmt.Errorf("Can't parse state file %v: %v", path, err)
+	}
+	if res.Version != journal_VERSION {
+		return nil, fmt.Errorf("Unsupported version of state file %v: %v (need %v)", path, res.Version, journal_VERSION)
+	}
+	if res.Sizes == nil {
+		res.Sizes = make(map[string]uint64)
+	}
+	if res.Swaps == nil {
+		res.Swaps = make(map[string]bool)
+	}
+	for _, item := range res.Plan {
+		if disk := item.Partition.Disk; disk != nil {
+			linkPartitions(disk.Partitions, disk)
+			linkPartitions(disk.LogicalPartitions, disk)
+		}
+	}
+	return res, nil
+}
+
+// Copy of partitions without links to disk
+// Копия разделов без ссылок на диск
+func unlinkPartitions(partitions []partition) []partition {
+	if partitions == nil {
+		return nil
+	}
+	res := make([]partition, len(partitions))
+	for i, part := range partitions {
+		part.Disk = nil
+		res[i] = part
+	}
+	return res
+}
+
+func linkPartitions(partitions []partition, disk *diskInfo) {
+	for i := range partitions {
+		partitions[i].Disk = disk
+	}
+}
+
+// Save journal to state file. Write to temporary file and rename it for never have broken state file.
+// Сохраняет журнал в файл состояния. Пишет во временный файл и переименовывает его, чтобы файл состояния никогда не
+// был испорчен.
+func (j *journal) save() error {
+	state := *j
+	state.Plan = make([]storageItem, len(j.Plan))
+	for i, item := range j.Plan {
+		// Partitions of disk have links back to the disk, it can't be saved. Save partitions without the links,
+		// readJournal restores them. Steps of logical partitions and backup of EBR need the partitions after resume.
+		// Разделы диска ссылаются обратно на диск, это нельзя сохранить. Разделы сохраняются без ссылок,
+		// readJournal восстанавливает их. Разделы нужны шагам логических разделов и копии EBR после resume.
+		if item.Partition.Disk != nil {
+			disk := *item.Partition.Disk
+			disk.Partitions = unlinkPartitions(disk.Partitions)
+			disk.LogicalPartitions = unlinkPartitions(disk.LogicalPartitions)
+			item.Partition.Disk = &disk
+		}
+		state.Plan[i] = item
+	}
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return err
+	}
+	tmpPath := j.path + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, append(content, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, j.path)
+}
+
+func (j *journal) saveLog() {
+	if err := j.save(); err != nil {
+		log.Println("Can't write state file:", j.path, err)
+	}
+}
+
+// Is the step finished. Always false for nil journal.
+// Завершен ли шаг. Всегда false для пустого журнала.
+func (j *journal) IsDone(index int) bool {
+	if j == nil {
+		return false
+	}
+	for _, done := range j.Done {
+		if done == index {
+			return true
+		}
+	}
+	return false
+}
+
+// Mark step as running. Do nothing for nil journal.
+// Отмечает шаг как выполняемый. Для пустого журнала ничего не делает.
+func (j *journal) StepStart(index int, plan []storageItem) {
+	if j == nil {
+		return
+	}
+	j.Current = index
+	j.Plan = plan
+	j.saveLog()
+}
+
+/*
+Mark step as finished. If the step need reboot - return true, then other steps have to be done after reboot by resume.
+Swap creation repeat after reboot, other steps doesn't repeat.
+Do nothing and return false for nil journal.
+
+Отмечает шаг как завершенный. Если шагу требуется перезагрузка - возвращает true, тогда остальные шаги нужно
+выполнить после перезагрузки через resume. Создание swap повторяется после перезагрузки, остальные шаги не повторяются.
+Для пустого журнала ничего не делает и возвращает false.
+*/
+func (j *journal) StepFinish(index int, plan []storageItem, needReboot bool) (stop bool) {
+	if j == nil {
+		return false
+	}
+	item := plan[index]
+	j.Current = -1
+	j.Plan = plan
+	if !needReboot || item.Type != type_SWAP_CREATE {
+		j.Done = append(j.Done, index)
+	}
+	switch item.Type {
+	case type_PARTITION, type_PARTITION_NEW, type_SWAP_MOVE:
+		j.Sizes[item.Path] = getDiskSize(item.Path)
+	}
+	if needReboot {
+		j.RebootStep = index
+	}
+	j.saveLog()
+	return needReboot
+}
+
+// Mark step as failed. It will be run again by resume. Do nothing for nil journal.
+// Отмечает шаг как завершившийся с ошибкой. Он будет выполнен снова при продолжении. Для пустого журнала ничего не делает.
+func (j *journal) StepFailed(index int, plan []storageItem) {
+	if j == nil {
+		return
+	}
+	j.Current = -1
+	j.Plan = plan
+	j.saveLog()
+}
+
+// Mark extend as complete. Do nothing for nil journal.
+// Отмечает расширение как завершенное. Для пустого журнала ничего не делает.
+func (j *journal) Finish(plan []storageItem) {
+	if j == nil {
+		return
+	}
+	j.Plan = plan
+	j.Current = -1
+	j.Complete = len(j.Done) == len(plan)
+	j.saveLog()
+}
+
+/*
+Check, that kernel see new size of partition, which need reboot. It must be called before resume.
+Interrupted step returns doError - reboot doesn't help it.
+Проверяет, что ядро видит новый размер раздела, которому нужна была перезагрузка. Вызывается перед продолжением.
+Прерванный шаг возвращает doError - перезагрузка ему не поможет.
+*/
+func (j *journal) CheckReboot() error {
+	if j.Current != -1 && !j.IsDone(j.Current) {
+		return doError{fmt.Errorf("Step %v was interrupted, check it manually: %v", j.Current, j.Plan[j.Current])}
+	}
+	if j.RebootStep == -1 || !j.IsDone(j.RebootStep) {
+		return nil
+	}
+	item := j.Plan[j.RebootStep]
+	if kernelSize := getDiskSize(item.Path); kernelSize != item.Size {
+		return fmt.Errorf("Kernel still see old size of %v: %v (need %v)", item.Path, formatSize(kernelSize),
+			formatSize(item.Size))
+	}
+	j.Sizes[item.Path] = item.Size
+	j.RebootStep = -1
+	return nil
+}
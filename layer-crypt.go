@@ -0,0 +1,95 @@
+package fsextender
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+func init() {
+	registerLayer(type_CRYPT, cryptLayer{})
+}
+
+// Encrypted device (dm-crypt, LUKS). It can be extended to size of underliing device without offset of data.
+// Шифрованное устройство (dm-crypt, LUKS). Может быть расширено до размера нижележащего устройства без смещения данных.
+type cryptLayer struct {
+	defaultDescribe
+}
+
+func (cryptLayer) Scan(scan *layerScan, item storageItem) error {
+	dev, err := blockDeviceByPath(item.Path)
+	if err != nil {
+		return fmt.Errorf("Can't read crypt device from sysfs: %v (%v)", item.Path, err)
+	}
+	if dev.DMName != "" {
+		item.Path = filepath.Join(devRoot, "mapper", dev.DMName)
+	}
+	item.Size = dev.Size
+	item.CryptOffset, err = cryptGetOffset(item.Path)
+	if err != nil {
+		return fmt.Errorf("Can't get offset of crypt device: %v (%v)", item.Path, err)
+	}
+	index := scan.Add(item)
+
+	if len(dev.Slaves) != 1 {
+		log.Printf("Crypt device must have one underliing device: %v (%v)\n", item.Path, dev.Slaves)
+		return nil
+	}
+	slave, err := readBlockDevice(dev.Slaves[0])
+	if err != nil {
+		log.Printf("Can't read underliing device of crypt device: %v (%v)\n", item.Path, err)
+		return nil
+	}
+	scan.AddParent(slave.DevPath(), slave.Major, slave.Minor, index)
+	return nil
+}
+
+func (cryptLayer) FreeSpace(item *storageItem, parent storageItem) {
+	if parent.Size > item.CryptOffset+item.Size {
+		item.FreeSpace = parent.Size - item.CryptOffset - item.Size
+	}
+}
+
+func (cryptLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	args := []string{"resize"}
+	if step.Options.CryptKeyFile != "" {
+		args = append(args, "--key-file", step.Options.CryptKeyFile)
+	}
+	if item.Limit != 0 {
+		// Limited growth, size in 512 bytes sectors
+		// Ограниченный рост, размер в секторах по 512 байт
+		args = append(args, "--size", formatUInt((item.Size+item.FreeSpace)/512))
+	}
+	args = append(args, filepath.Base(item.Path))
+	for retry := 0; ; retry++ {
+		if retry == TRY_COUNT {
+			if item.FreeSpace == 0 {
+				return nil
+			}
+			return stepError("Can't resize crypt device:", item.Path)
+		}
+		if retry > 0 {
+			log.Println("Try to resize crypt device once more:", item.Path)
+			time.Sleep(time.Second)
+		}
+		_, errString, err := cmd("cryptsetup", args...)
+		if err != nil {
+			log.Printf("Can't resize crypt device: %v (%v) %v\n", item.Path, err, errString)
+			continue
+		}
+		newSize := getDiskSize(item.Path)
+		if newSize <= item.Size {
+			continue
+		}
+		addSpace := newSize - item.Size
+		step.GrowChild(addSpace)
+		log.Printf("Crypt device resized: %v to %v (+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+		// Device can grow more then planned free space, if underliing device grew while resize
+		// Устройство может вырасти больше запланированного, если нижележащее устройство выросло во время изменения
+		item.FreeSpace -= minUint64(addSpace, item.FreeSpace)
+		item.Size = newSize
+		return nil
+	}
+}
@@ -0,0 +1,473 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+func init() {
+	registerFSLayer("ext2", extLayer{})
+	registerFSLayer("ext3", extLayer{})
+	registerFSLayer("ext4", extLayer{})
+	registerFSLayer("xfs", xfsLayer{})
+	registerFSLayer("btrfs", btrfsLayer{})
+	registerLayer(type_BTRFS_DEVICE_NEW, btrfsDeviceNewLayer{})
+}
+
+// Error of filesystem resize, which can't be fixed by retry
+// Ошибка изменения размера файловой системы, которую нельзя исправить повтором
+type fsFatalError struct{ error }
+
+func (err fsFatalError) Unwrap() error { return err.error }
+
+/*
+Resize filesystem once. newFSSize - size of filesystem after resize, 0 - max size.
+Return new size of filesystem and output of resize tool.
+
+Изменяет размер файловой системы один раз. newFSSize - размер файловой системы после изменения, 0 - максимальный.
+Возвращает новый размер файловой системы и вывод утилиты изменения размера.
+*/
+type fsResizeFunc func(item storageItem, newFSSize uint64) (newSize uint64, stdout, stderr string, err error)
+
+// Scan filesystem, which placed on one block device
+// Сканирует файловую систему, расположенную на одном блочном устройстве
+func scanFS(scan *layerScan, item storageItem, getSize func(path string) (uint64, error)) (err error) {
+	item.Size, err = getSize(item.Path)
+	if err != nil {
+		return fmt.Errorf("Can't get size of filesystem: %v (%v)", item.Path, err)
+	}
+	index := scan.Add(item)
+	major, minor := getMajorMinor(item.Path)
+	scan.AddParent(item.Path, major, minor, index)
+	return nil
+}
+
+// Filesystem can be extended to size of underliing device
+// Файловая система может быть расширена до размера нижележащего устройства
+func fsFreeSpace(fs *storageItem, parent storageItem) {
+	switch {
+	case parent.Size > fs.Size:
+		fs.FreeSpace += parent.Size - fs.Size
+	case parent.Size < fs.Size:
+		log.Printf("WARNING: Filesystem size (%v) more then underliing layer (%v, %v)\n", fs.Path, parent.Type, parent.Path)
+	}
+}
+
+// Resize filesystem with retries
+// Изменяет размер файловой системы с повторами
+func applyFS(step *layerStep, resize fsResizeFunc) error {
+	item := step.Item()
+
+	// Size of filesystem after resize, 0 - max size
+	// Размер файловой системы после расширения, 0 - максимальный
+	var newFSSize uint64
+	if item.Limit != 0 {
+		if item.FreeSpace == 0 {
+			log.Println("Filesystem doesn't need extend:", item.Path)
+			return nil
+		}
+		newFSSize = item.Size + item.FreeSpace
+	}
+	for retry := 0; ; retry++ {
+		if retry == TRY_COUNT {
+			if item.FreeSpace == 0 {
+				return nil
+			}
+			return stepError("Can't extend filesystem:", item.Path)
+		}
+		if retry > 0 {
+			log.Println("Sleep a second before retry.")
+			time.Sleep(time.Second)
+		}
+		newSize, stdout, stderr, err := resize(*item, newFSSize)
+		var fatal fsFatalError
+		if errors.As(err, &fatal) {
+			return fatal.error
+		}
+		if err != nil {
+			log.Printf("ATTENTION: Can't resize filesystem %v: %v. Log of resize:\nstdout:%v\nstderr:%v\n", item.Path, err,
+				stdout, stderr)
+			continue
+		}
+		addSpace := newSize - item.Size
+		if addSpace == 0 {
+			log.Printf("Filesystem doesn't extend. Log of resize:\nstdout: %v\nstderr: %v\n", stdout, stderr)
+			continue
+		}
+		item.FreeSpace -= addSpace
+		item.Size = newSize
+		log.Printf("Resize filesystem: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(addSpace))
+		return nil
+	}
+}
+
+// ext2, ext3, ext4
+type extLayer struct {
+	defaultDescribe
+}
+
+func (extLayer) Scan(scan *layerScan, item storageItem) error {
+	sb, err := readExtSuperblock(item.Path)
+	if err != nil {
+		return fmt.Errorf("Can't get size of filesystem: %v (%v)", item.Path, err)
+	}
+	if sb.HasErrors() {
+		log.Printf("WARNING: Filesystem %v has errors, resize may fail. Check it by e2fsck.\n", item.Path)
+	}
+	return scanFS(scan, item, func(string) (uint64, error) { return sb.Size(), nil })
+}
+
+func (extLayer) FreeSpace(item *storageItem, parent storageItem) {
+	fsFreeSpace(item, parent)
+}
+
+func (extLayer) Apply(step *layerStep) error {
+	return applyFS(step, func(item storageItem, newFSSize uint64) (newSize uint64, stdout, stderr string, err error) {
+		sb, err := readExtSuperblock(item.Path)
+		if err != nil {
+			return 0, "", "", err
+		}
+		// Count of blocks is 32-bit without 64bit feature
+		// Без возможности 64bit количество блоков 32-битное
+		if maxSize := sb.MaxSize(); (newFSSize == 0 || newFSSize > maxSize) && getDiskSize(item.Path) > maxSize {
+			log.Printf("Filesystem %v without 64bit feature can't be more then %v, extend to the size.\n", item.Path,
+				formatSize(maxSize))
+			newFSSize = maxSize
+		}
+		resized := false
+		if mountPoint, _ := getMountPoint(item.Path); mountPoint != "" && item.FSType == "ext4" {
+			err = extResizeMounted(item, sb, mountPoint, newFSSize)
+			switch {
+			case err == nil:
+				resized = true
+			case ext4ResizeUnsupported(err):
+				log.Printf("Kernel can't resize %v, try resize2fs: %v\n", item.Path, err)
+			default:
+				return 0, "", "", err
+			}
+		}
+		if !resized {
+			args := []string{"-f", item.Path}
+			if newFSSize != 0 {
+				args = append(args, formatUInt(minUint64(newFSSize, getDiskSize(item.Path))/1024)+"K")
+			}
+			stdout, stderr, _ = cmd("resize2fs", args...)
+		}
+		newSize, err = fsGetSizeExt(item.Path)
+		return newSize, stdout, stderr, err
+	})
+}
+
+// Grow mounted ext4 by kernel to exact count of blocks. Only busy error can be fixed by retry.
+// Расширяет смонтированную ext4 средствами ядра до точного количества блоков. Только ошибка занятости исправляется
+// повтором.
+func extResizeMounted(item storageItem, sb extSuperblock, mountPoint string, newFSSize uint64) error {
+	newSize := getDiskSize(item.Path)
+	if newFSSize != 0 {
+		newSize = minUint64(newFSSize, newSize)
+	}
+	blockCount := newSize / sb.BlockSize
+	log.Printf("Resize mounted ext4 %v (%v) to %v blocks by kernel\n", item.Path, mountPoint, blockCount)
+	err := ext4ResizeFS(mountPoint, blockCount)
+	var resizeErr *ext4ResizeError
+	if errors.As(err, &resizeErr) && resizeErr.Errno != syscall.EBUSY && !ext4ResizeUnsupported(err) {
+		return fsFatalError{err}
+	}
+	return err
+}
+
+// Kernel or features of filesystem don't allow resize by ioctl, resize2fs can do it other way
+// Ядро или возможности файловой системы не позволяют изменить размер через ioctl, resize2fs может сделать это иначе
+func ext4ResizeUnsupported(err error) bool {
+	return errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EOPNOTSUPP) || errors.Is(err, syscall.EINVAL)
+}
+
+type xfsLayer struct {
+	defaultDescribe
+}
+
+func (xfsLayer) Scan(scan *layerScan, item storageItem) error {
+	sb, err := readXFSSuperblock(item.Path)
+	if err != nil {
+		return fmt.Errorf("Can't get size of filesystem: %v (%v)", item.Path, err)
+	}
+	if sb.InProgress {
+		return fmt.Errorf("Creation of xfs didn't finish: %v", item.Path)
+	}
+	return scanFS(scan, item, func(string) (uint64, error) { return sb.Size(), nil })
+}
+
+func (xfsLayer) FreeSpace(item *storageItem, parent storageItem) {
+	fsFreeSpace(item, parent)
+}
+
+// xfs can be extended only while mounted, it grows by kernel to exact count of blocks
+// xfs может быть расширена только в смонтированном состоянии, расширяется средствами ядра до точного количества блоков
+func (xfsLayer) Apply(step *layerStep) error {
+	return applyFS(step, func(item storageItem, newFSSize uint64) (newSize uint64, stdout, stderr string, err error) {
+		mountPoint, tmpMountPoint, err := fsMount(item.Path, item.FSType)
+		if err != nil {
+			return 0, "", "", fsFatalError{err}
+		}
+		defer fsUmountTmp(tmpMountPoint)
+
+		geo, err := xfsFSGeometry(mountPoint)
+		if err != nil {
+			return 0, "", "", fsFatalError{err}
+		}
+		newSize = getDiskSize(item.Path)
+		if newFSSize != 0 {
+			newSize = minUint64(newFSSize, newSize)
+		}
+		if newBlocks := newSize / uint64(geo.BlockSize); newBlocks > geo.DataBlocks {
+			log.Printf("Grow xfs %v (%v) to %v blocks by kernel\n", item.Path, mountPoint, newBlocks)
+			if err = xfsGrowData(mountPoint, newBlocks, geo.IMaxPct); err != nil {
+				return 0, "", "", xfsGrowError(err)
+			}
+		}
+		if err = xfsGrowSections(mountPoint, geo, step.Options); err != nil {
+			return 0, "", "", xfsGrowError(err)
+		}
+		if geo, err = xfsFSGeometry(mountPoint); err != nil {
+			return 0, "", "", err
+		}
+		return geo.DataBlocks * uint64(geo.BlockSize), "", "", nil
+	})
+}
+
+// Only error of concurrent grow can be fixed by retry
+// Только ошибка одновременного расширения исправляется повтором
+func xfsGrowError(err error) error {
+	if errors.Is(err, syscall.EAGAIN) {
+		return err
+	}
+	return fsFatalError{err}
+}
+
+/*
+Grow realtime section and external log of xfs to size of their devices (rtdev and logdev options of mount), if it
+asked by options. Absent realtime section, internal log and refuse of kernel are skipped with message, it isn't error
+of step.
+Расширяет realtime раздел и внешний журнал xfs до размера их устройств (опции монтирования rtdev и logdev), если это
+задано в options. Отсутствующий realtime раздел, внутренний журнал и отказ ядра пропускаются с сообщением, это не
+ошибка шага.
+*/
+func xfsGrowSections(mountPoint string, geo xfsGeometry, options doOptions) error {
+	if !options.XFSGrowRT && !options.XFSGrowLog {
+		return nil
+	}
+	mounts, err := readMountInfo()
+	if err != nil {
+		return err
+	}
+	mount, _ := findMountByPath(mounts, mountPoint)
+	blockSize := uint64(geo.BlockSize)
+
+	if options.XFSGrowRT {
+		rtdev := mount.Option("rtdev")
+		if geo.RTBlocks == 0 || rtdev == "" {
+			log.Println("xfs hasn't realtime section, skip grow of it:", mountPoint)
+		} else if newBlocks := getDiskSize(rtdev) / blockSize; newBlocks > geo.RTBlocks {
+			log.Printf("Grow realtime section of xfs %v (%v) to %v blocks\n", mountPoint, rtdev, newBlocks)
+			err = xfsGrowRT(mountPoint, newBlocks, geo.RTExtSize)
+			if err != nil && !xfsGrowRefused(err, "realtime section") {
+				return err
+			}
+		}
+	}
+
+	if options.XFSGrowLog {
+		logdev := mount.Option("logdev")
+		if geo.LogStart != 0 || logdev == "" {
+			log.Println("xfs has internal log, it can't be grown:", mountPoint)
+			return nil
+		}
+		newBlocks := getDiskSize(logdev) / blockSize
+		if newBlocks > 1<<32-1 {
+			newBlocks = 1<<32 - 1
+		}
+		if newBlocks > uint64(geo.LogBlocks) {
+			log.Printf("Grow log of xfs %v (%v) to %v blocks\n", mountPoint, logdev, newBlocks)
+			err = xfsGrowLog(mountPoint, uint32(newBlocks), false)
+			if err != nil && !xfsGrowRefused(err, "log") {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Linux kernel refuses grow of the section (ENOSYS or EINVAL). Print message about it.
+// Ядро linux отказывает в расширении раздела (ENOSYS или EINVAL). Выводит сообщение об этом.
+func xfsGrowRefused(err error, section string) bool {
+	if errors.Is(err, syscall.ENOSYS) || errors.Is(err, syscall.EINVAL) {
+		log.Printf("Kernel can't grow %v of xfs, skip it: %v\n", section, err)
+		return true
+	}
+	return false
+}
+
+// btrfs can be placed on few devices, free space of them detects while scan
+// btrfs может располагаться на нескольких устройствах, их свободное место определяется при сканировании
+type btrfsLayer struct {
+	noFreeSpace
+	defaultDescribe
+}
+
+func (btrfsLayer) Scan(scan *layerScan, item storageItem) (err error) {
+	item.Size, err = fsGetSizeBtrfs(item.Path)
+	if err != nil {
+		return fmt.Errorf("Can't get size of filesystem: %v (%v)", item.Path, err)
+	}
+	scanBtrfsDevices(scan, scan.Add(item))
+	return nil
+}
+
+// Add devices of btrfs filesystem storage[fsIndex] to scan. Add unused space of devices to free space of the filesystem.
+// Add new partitions, which can be added to the filesystem as new devices.
+// Добавляет устройства файловой системы btrfs storage[fsIndex] к сканированию. Неиспользуемое место на устройствах
+// добавляется к свободному месту файловой системы. Добавляет новые разделы, которые можно добавить в файловую систему
+// как новые устройства.
+func scanBtrfsDevices(scan *layerScan, fsIndex int) {
+	fs := &scan.Storage[fsIndex]
+	devices, err := btrfsDevices(fs.Path)
+	if err != nil {
+		log.Printf("Can't read devices of btrfs: %v (%v)\n", fs.Path, err)
+		return
+	}
+	for _, btrfsDev := range devices {
+		dev, err := blockDeviceByPath(btrfsDev.Path)
+		if err != nil {
+			log.Printf("Can't read device of btrfs: %v (%v)\n", btrfsDev.Path, err)
+			continue
+		}
+		if dev.Size > btrfsDev.Size {
+			fs.FreeSpace += dev.Size - btrfsDev.Size
+		}
+		scan.AddParent(dev.DevPath(), dev.Major, dev.Minor, fsIndex)
+	}
+
+	for _, part := range getNewPartitions() {
+		deviceAdd := scan.Add(storageItem{Child: fsIndex, Path: part.Path, Type: type_BTRFS_DEVICE_NEW})
+		scan.Add(storageItem{Child: deviceAdd, Path: part.Path, Type: type_PARTITION_NEW, FreeSpace: part.Size(),
+			Partition: part})
+	}
+}
+
+// Every device resized separately
+// Размер каждого устройства изменяется отдельно
+func (btrfsLayer) Apply(step *layerStep) error {
+	return applyFS(step, func(item storageItem, newFSSize uint64) (newSize uint64, stdout, stderr string, err error) {
+		mountPoint, tmpMountPoint, err := fsMount(item.Path, item.FSType)
+		if err != nil {
+			return 0, "", "", fsFatalError{err}
+		}
+		defer fsUmountTmp(tmpMountPoint)
+
+		devices, err := btrfsDevices(mountPoint)
+		if err != nil {
+			log.Println("Can't read devices of btrfs: ", item.Path, err)
+		}
+		// Growth for limited resize, devices extend by order
+		// Рост при ограниченном расширении, устройства расширяются по порядку
+		var remaining uint64
+		if newFSSize != 0 {
+			var currentSize uint64
+			for _, dev := range devices {
+				currentSize += dev.Size
+			}
+			if newFSSize > currentSize {
+				remaining = newFSSize - currentSize
+			}
+		}
+		for _, dev := range devices {
+			devSize := "max"
+			if newFSSize != 0 {
+				devMaxSize := getDiskSize(dev.Path)
+				if remaining == 0 || devMaxSize <= dev.Size {
+					continue
+				}
+				newDevSize := minUint64(devMaxSize, dev.Size+remaining)
+				remaining -= newDevSize - dev.Size
+				devSize = formatUInt(newDevSize)
+			}
+			devStdout, devStderr, _ := cmd("btrfs", "filesystem", "resize", strconv.FormatUint(dev.DevID, 10)+":"+devSize, mountPoint)
+			stdout += devStdout
+			stderr += devStderr
+		}
+		newSize, err = fsGetSizeBtrfs(mountPoint)
+		return newSize, stdout, stderr, err
+	})
+}
+
+// Add new partition to btrfs as new device
+// Добавление нового раздела в btrfs как нового устройства
+type btrfsDeviceNewLayer struct {
+	addOnlyScan
+	noFreeSpace
+	defaultDescribe
+}
+
+func (btrfsDeviceNewLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	fs := step.Plan[item.Child]
+	mountPoint, tmpMountPoint, err := fsMount(fs.Path, fs.FSType)
+	if err != nil {
+		return err
+	}
+	_, errString, err := cmd("btrfs", "device", "add", item.Path, mountPoint)
+	fsUmountTmp(tmpMountPoint)
+	if err != nil {
+		return stepErrorf("Can't add device to btrfs: %v -> %v (%v) %v\n", item.Path, fs.Path, err, errString)
+	}
+	log.Printf("Add device to btrfs: %v -> %v\n", item.Path, fs.Path)
+	step.GrowChild(getDiskSize(item.Path))
+	return nil
+}
+
+/*
+Return mount point of filesystem. If the filesystem isn't mounted - mount it to temporary directory. Then tmpMountPoint
+isn't empty and it have to be unmount by fsUmountTmp after use.
+
+Возвращает точку монтирования файловой системы. Если файловая система не смонтирована - монтирует её во временную
+папку. В этом случае tmpMountPoint не пустой и после использования её надо отмонтировать через fsUmountTmp.
+*/
+func fsMount(path, fsType string) (mountPoint, tmpMountPoint string, err error) {
+	if mountPoint, _ = getMountPoint(path); mountPoint != "" {
+		return mountPoint, "", nil
+	}
+	tmpMountPoint, err = ioutil.TempDir("", "")
+	if err != nil {
+		return "", "", fmt.Errorf("Can't create tmp mount point for %v: %v", fsType, err)
+	}
+	var errString string
+	for retry := 0; retry < TRY_COUNT; retry++ {
+		if retry > 0 {
+			log.Printf("Retry mount %v volume\n", fsType)
+			time.Sleep(time.Second)
+		}
+		if _, errString, err = cmd("mount", "-t", fsType, path, tmpMountPoint); err == nil {
+			return tmpMountPoint, tmpMountPoint, nil
+		}
+		log.Printf("Can't %v mount: %v (%v) ('%v' -> '%v')", fsType, err, errString, path, tmpMountPoint)
+	}
+	// При последней попытке - удаляем временную точку монтирования
+	os.Remove(tmpMountPoint)
+	return "", "", fmt.Errorf("Can't mount %v: %v", path, err)
+}
+
+// Unmount and remove temporary mount point. Do nothing for empty tmpMountPoint.
+// Отмонтирует и удаляет временную точку монтирования. Для пустого tmpMountPoint ничего не делает.
+func fsUmountTmp(tmpMountPoint string) {
+	if tmpMountPoint == "" {
+		return
+	}
+	cmd("umount", tmpMountPoint)
+	os.Remove(tmpMountPoint)
+}
@@ -0,0 +1,243 @@
+package fsextender
+
+import (
+	"log"
+	"time"
+)
+
+func init() {
+	registerLayer(type_LVM_LV, lvmLVLayer{})
+	registerLayer(type_LVM_GROUP, lvmGroupLayer{})
+	registerLayer(type_LVM_PV, lvmPVLayer{})
+	registerLayer(type_LVM_PV_ADD, lvmPVAddLayer{})
+	registerLayer(type_LVM_PV_NEW, lvmPVNewLayer{})
+}
+
+type lvmLVLayer struct {
+	noFreeSpace
+	defaultDescribe
+}
+
+func (lvmLVLayer) Scan(scan *layerScan, item storageItem) error {
+	// Normalize path to LVM LV
+	// Если был передан полный путь к LVM - заменяем его описанием из кеша, заполненного при сканировании LVM
+	if major, minor := getMajorMinor(item.Path); major != 0 {
+		item.Path = majorMinorDeviceTypeCache[[2]int{major, minor}].Path
+	}
+	item.Size = lvmLVGetSize(item.Path)
+	index := scan.Add(item)
+	scan.ToScan = append(scan.ToScan, storageItem{
+		Type:  type_LVM_GROUP,
+		Path:  lvmLVGroupName(item.Path),
+		Child: index,
+	})
+	return nil
+}
+
+func (lvmLVLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	for retry := 0; ; retry++ {
+		if retry == TRY_COUNT {
+			return stepError("Can't extend LVM LV:", item.Path)
+		}
+		if retry > 0 {
+			log.Println("Try extend LVM LV once more:", item.Path)
+			time.Sleep(time.Second)
+		}
+		var vgReserve uint64
+		for _, vg := range step.Plan {
+			if vg.Type == type_LVM_GROUP && vg.Path == lvmLVGroupName(item.Path) {
+				vgReserve = vg.LVMReserve
+			}
+		}
+		args := []string{"-l", "+100%FREE", item.Path}
+		if item.Limit != 0 || vgReserve != 0 {
+			// Limited growth, but not more then free space in volume group without reserve
+			// Ограниченный рост, но не больше свободного места в группе томов без резерва
+			_, vgFree, extentSize := lvmVGGetSize(lvmLVGroupName(item.Path))
+			if vgFree > vgReserve {
+				vgFree -= vgReserve
+			} else {
+				vgFree = 0
+			}
+			grow := minUint64(item.FreeSpace, vgFree)
+			if extentSize != 0 {
+				grow -= grow % extentSize
+			}
+			if grow == 0 {
+				log.Println("LVM LV doesn't need extend:", item.Path)
+				return nil
+			}
+			args = []string{"-L", "+" + formatUInt(grow) + "b", item.Path}
+		}
+		cmd("lvresize", args...)
+		newSize := lvmLVGetSize(item.Path)
+		addSpace := newSize - item.Size
+		if item.FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
+			continue
+		}
+		log.Printf("Resize LVM_LV %v to %v(+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+		item.Size = newSize
+		item.FreeSpace = 0
+		step.GrowChild(addSpace)
+		return nil
+	}
+}
+
+type lvmGroupLayer struct {
+	noFreeSpace
+	defaultDescribe
+}
+
+func (lvmGroupLayer) Scan(scan *layerScan, item storageItem) error {
+	item.Size, item.FreeSpace, item.LVMExtentSize = lvmVGGetSize(item.Path)
+	lvmGroupIndex := scan.Add(item)
+
+	// Find my and free pvs
+	for _, pv := range getLvmPV() {
+		if pv.VolumeGroup == "" {
+			// Can use free LVM PV
+			// Незанятые PV, можно использовать
+			parent := storageItem{Path: pv.Path, Type: type_LVM_PV_ADD, Child: lvmGroupIndex, LVMExtentSize: item.LVMExtentSize}
+
+			// Calc usable PV size
+			// для свободных pv  система выдает размер равный размеру раздела, так что испольузем расчетный размер
+			parent.Size = lvmPVCalcSize(pv.Size, item.LVMExtentSize)
+			parent.FreeSpace = parent.Size
+			scan.ToScan = append(scan.ToScan, parent)
+		} else if pv.VolumeGroup == item.Path {
+			// LVM PV in the LV group
+			// PV, входящие в эту группу
+			parent := storageItem{Path: pv.Path, Size: pv.Size, Type: type_LVM_PV, Child: lvmGroupIndex, LVMExtentSize: item.LVMExtentSize}
+			scan.ToScan = append(scan.ToScan, parent)
+		} else {
+			// nothing
+		}
+	}
+
+	// Find free space for create new partition
+	for _, part := range getNewPartitions() {
+		pvCreate := scan.Add(storageItem{Child: lvmGroupIndex, Path: part.Path, Type: type_LVM_PV_NEW, LVMExtentSize: item.LVMExtentSize})
+		scan.Add(storageItem{Child: pvCreate, Path: part.Path, Type: type_PARTITION_NEW, FreeSpace: part.Size(),
+			Partition: part})
+	}
+	return nil
+}
+
+func (lvmGroupLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	// Every LV of the group can use all free space, LVs limited by their shares.
+	// Каждый LV группы может использовать всё свободное место, LV ограничены своими долями.
+	for _, lv := range planChildren(step.Plan, step.Index) {
+		step.Plan[lv].FreeSpace = item.FreeSpace
+	}
+	log.Printf("Free space on LVM_GROUP '%v' %v\n", item.Path, formatSize(item.FreeSpace))
+	return nil
+}
+
+// PV of volume group. It can be extended to size of underliing device.
+// PV группы томов. Может быть расширен до размера нижележащего устройства.
+type lvmPVLayer struct {
+	defaultDescribe
+}
+
+func (lvmPVLayer) Scan(scan *layerScan, item storageItem) error {
+	return scanPV(scan, item)
+}
+
+func scanPV(scan *layerScan, item storageItem) error {
+	item.Size = lvmPVGetSize(item.Path)
+	index := scan.Add(item)
+	major, minor := getMajorMinor(item.Path)
+	scan.AddParent(item.Path, major, minor, index)
+	return nil
+}
+
+func (lvmPVLayer) FreeSpace(item *storageItem, parent storageItem) {
+	newSize := lvmPVCalcSize(parent.Size, item.LVMExtentSize)
+	if newSize > item.Size {
+		item.FreeSpace = newSize - item.Size
+	}
+}
+
+func (lvmPVLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	for retry := 0; ; retry++ {
+		if retry == TRY_COUNT {
+			return stepError("Can't resize LVM PV:", item.Path)
+		}
+		if retry > 0 {
+			log.Println("Try to resize LVM PV once more:", item.Path)
+		}
+		cmd("pvresize", item.Path)
+		newSize := lvmPVGetSize(item.Path)
+		addSpace := newSize - item.Size
+		if step.Plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
+			continue
+		}
+		step.GrowChild(addSpace)
+		log.Printf("LVM PV Resized: %v to %v (+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+		item.FreeSpace -= addSpace
+		item.Size = newSize
+		return nil
+	}
+}
+
+// Free PV, which can be added to volume group. Free space of it calculated while scan group.
+// Свободный PV, который можно добавить в группу томов. Его свободное место вычислено при сканировании группы.
+type lvmPVAddLayer struct {
+	noFreeSpace
+	defaultDescribe
+}
+
+func (lvmPVAddLayer) Scan(scan *layerScan, item storageItem) error {
+	return scanPV(scan, item)
+}
+
+func (lvmPVAddLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	vg := step.Plan[item.Child].Path
+	oldSize, _, _ := lvmVGGetSize(vg)
+	for retry := 0; ; retry++ {
+		if retry == TRY_COUNT {
+			return stepError("Can't add free PV to VG:", item.Path, vg)
+		}
+		cmd("vgextend", vg, item.Path)
+		newSize, _, _ := lvmVGGetSize(vg)
+		if newSize > oldSize {
+			log.Printf("Add free pv (%v) to vg(%v), new size: %v(+%v)\n", item.Path, vg,
+				formatSize(newSize), formatSize(newSize-oldSize))
+			return nil
+		}
+	}
+}
+
+// Create PV on new partition and add it to volume group
+// Создание PV на новом разделе и добавление его в группу томов
+type lvmPVNewLayer struct {
+	addOnlyScan
+	noFreeSpace
+	defaultDescribe
+}
+
+func (lvmPVNewLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	vg := step.Plan[item.Child].Path
+	oldSize, _, _ := lvmVGGetSize(vg)
+	for retry := 0; ; retry++ {
+		if retry == TRY_COUNT {
+			return stepError("Can't add new PV to VG:", item.Path, vg)
+		}
+		cmd("pvcreate", item.Path)
+		cmd("vgextend", vg, item.Path)
+		newSize, _, _ := lvmVGGetSize(vg) // Yes - create LVM PV, but check size of LVM VG. It is OK.
+		addSpace := newSize - oldSize
+		if step.Plan[item.Child].FreeSpace > 0 && (addSpace == 0 || newSize == 0) {
+			log.Println("Try extend VG once more: ", vg, item.Path)
+			time.Sleep(time.Second)
+			continue
+		}
+		log.Printf("Add PV %v (+%v)\n", item.Path, formatSize(newSize-oldSize))
+		return nil
+	}
+}
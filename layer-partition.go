@@ -0,0 +1,329 @@
+package fsextender
+
+import (
+	"fmt"
+	"github.com/rekby/gpt"
+	"github.com/rekby/mbr"
+	"log"
+)
+
+func init() {
+	registerLayer(type_PARTITION, partitionLayer{})
+	registerLayer(type_PARTITION_NEW, partitionNewLayer{})
+	registerLayer(type_PARTITION_EXTENDED, partitionExtendedLayer{})
+	registerLayer(type_SWAP_MOVE, swapMoveLayer{})
+	registerLayer(type_SWAP_CREATE, swapCreateLayer{})
+	registerLayer(type_DISK, diskLayer{})
+}
+
+/*
+Partition table of disk: msdos, gpt. It writes changes of partitions to disk, kernel doesn't inform.
+Таблица разделов диска: msdos, gpt. Записывает изменения разделов на диск, ядро не оповещается.
+*/
+type partitionTable interface {
+	// Change end of partition. newSize - bytes.
+	// Изменяет конец раздела. newSize - в байтах.
+	Resize(part partition, newSize uint64) error
+
+	// Create partition. Return size of created partition.
+	// Создаёт раздел. Возвращает размер созданного раздела.
+	Create(part partition, partType newPartitionType) (size uint64, err error)
+
+	// Move partition to newFirstByte without change of size. Data of partition doesn't move.
+	// Перемещает раздел на newFirstByte без изменения размера. Данные раздела не перемещаются.
+	Move(part partition, newFirstByte uint64) error
+}
+
+// Type of new partition for every partition table
+// Тип нового раздела для каждой таблицы разделов
+type newPartitionType struct {
+	MBR mbr.PartitionType
+	GPT gpt.PartType
+}
+
+var (
+	partType_LVM      = newPartitionType{MBR: mbr.PART_LVM, GPT: gpt.GUID_LVM}
+	partType_LINUX_FS = newPartitionType{MBR: mbr_PART_LINUX, GPT: gpt_GUID_LINUX_FS}
+)
+
+var partitionTables = make(map[string]partitionTable)
+
+func registerPartitionTable(name string, table partitionTable) {
+	if _, ok := partitionTables[name]; ok {
+		panic("Partition table registered twice: " + name)
+	}
+	partitionTables[name] = table
+}
+
+func partitionTableOf(part partition) (partitionTable, error) {
+	table, ok := partitionTables[part.Disk.PartTable]
+	if !ok {
+		return nil, fmt.Errorf("I don't know partition table: %v(%v)", part.Disk.PartTable, part.Path)
+	}
+	return table, nil
+}
+
+type partitionLayer struct {
+	noFreeSpace
+}
+
+func (partitionLayer) Scan(scan *layerScan, item storageItem) error {
+	diskPath, partNumber, err := partitionDisk(item.Path)
+	if err != nil {
+		return err
+	}
+	disk, err := readDiskInfo(diskPath)
+	if err != nil {
+		return fmt.Errorf("Error while scan partition: %v %v %v", item.Path, diskPath, err)
+	}
+	partitions := disk.Partitions
+	if disk.PartTable == "msdos" && partNumber > 4 {
+		// Logical partition inside extended partition
+		// Логический раздел внутри расширенного раздела
+		partitions = disk.LogicalPartitions
+	}
+	for i, partition := range partitions {
+		if partition.Number != partNumber {
+			continue
+		}
+		item.Size = partition.Size()
+		item.Partition = partition
+
+		// Check if can extend partition.
+		// Если можем расшириться за счет свободного места между разделами или до конца диска
+		if i+1 < len(partitions) && partitions[i+1].IsFreeSpace() {
+			freeSpace := partitions[i+1]
+			item.FreeSpace = uint64(freeSpace.LastByte - partition.LastByte)
+		}
+	}
+	// If partition has not fund
+	// Если раздел не найден
+	if item.Partition.Number == 0 {
+		item.Type = type_SKIP
+		item.SkipReason = "Coud not found partition in partition table"
+	}
+	partitionIndex := scan.Add(item)
+
+	// Last logical partition can be extended after extend of extended partition
+	// Последний логический раздел может расшириться после расширения расширенного раздела
+	if item.Partition.Logical {
+		last, _ := disk.lastPlacedLogicalPartition()
+		extended, freeSpace, ok := disk.extendedPartition()
+		if ok && freeSpace > 0 && last.Number == item.Partition.Number {
+			scan.Add(storageItem{
+				Type:      type_PARTITION_EXTENDED,
+				Path:      extended.Path,
+				Child:     partitionIndex,
+				Size:      extended.Size(),
+				FreeSpace: freeSpace,
+				Partition: extended,
+			})
+		}
+	}
+
+	// Swap partition after the partition can be moved to end of disk for extend the partition
+	// Раздел подкачки после раздела может быть перемещён в конец диска для расширения раздела
+	if item.Type == type_PARTITION && !item.Partition.Logical && item.FreeSpace == 0 {
+		if swap, newSwap, ok := findTrailingSwap(disk.Partitions, item.Partition); ok {
+			probe, _ := probeFS(swap.Path)
+			uuid := probe.UUID
+			scan.Add(storageItem{
+				Type:      type_SWAP_CREATE,
+				Path:      newSwap.Path,
+				Child:     -1,
+				Size:      newSwap.Size(),
+				Partition: newSwap,
+				UUID:      uuid,
+			})
+			scan.Add(storageItem{
+				Type:      type_SWAP_MOVE,
+				Path:      swap.Path,
+				Child:     partitionIndex,
+				Size:      swap.Size(),
+				FreeSpace: newSwap.FirstByte - swap.FirstByte,
+				Partition: swap,
+				UUID:      uuid,
+			})
+		}
+	}
+	return nil
+}
+
+func (partitionLayer) Describe(item storageItem) string {
+	return fmt.Sprint(item, " May need reboot")
+}
+
+func (partitionLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	table, err := partitionTableOf(item.Partition)
+	if err != nil {
+		return err
+	}
+	if err = step.BackupTable(item.Partition); err != nil {
+		return err
+	}
+	oldKernelSize := getDiskSize(item.Path)
+	oldFreeSpace := item.FreeSpace
+	if err = table.Resize(item.Partition, item.Size+item.FreeSpace); err != nil {
+		return err
+	}
+	// Partition without child (start point or shared in merged plan) grows too, kernel must see its new size.
+	// Раздел без потомка (точка старта или общий в объединённом плане) тоже растёт, ядро должно увидеть новый размер.
+	step.GrowChild(item.FreeSpace)
+	item.Size += item.FreeSpace
+	item.FreeSpace = 0
+	log.Printf("Partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size), formatSize(oldFreeSpace))
+
+	kernelPartitionUpdate(blkpg_RESIZE_PARTITION, item.Partition, item.Size)
+	newKernelSize := getDiskSize(item.Path)
+	if oldKernelSize == newKernelSize && oldFreeSpace != 0 {
+		log.Println("NEED REBOOT!")
+		step.NeedReboot = true
+	}
+	return nil
+}
+
+// Create partition on free space of disk
+// Создание раздела на свободном месте диска
+type partitionNewLayer struct {
+	addOnlyScan
+	noFreeSpace
+}
+
+func (partitionNewLayer) Describe(item storageItem) string {
+	if item.Partition.Disk.PartTable == "msdos" && item.Partition.Number > 4 && !item.Partition.Logical {
+		return fmt.Sprint("!!! ATTENTION, Can't create more then 4 partition in msdos table. Skip it. ", item)
+	}
+	return item.String()
+}
+
+func (partitionNewLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	partType := partType_LVM
+	if item.Child != -1 && step.Plan[item.Child].Type == type_BTRFS_DEVICE_NEW {
+		partType = partType_LINUX_FS
+	}
+	table, err := partitionTableOf(item.Partition)
+	if err != nil {
+		return stepError("Can't create partition in unknown partition table: ", item.Partition.Path, item.Partition.Disk.PartTable)
+	}
+	if err = step.BackupTable(item.Partition, item.Partition.EBRByte); err != nil {
+		return err
+	}
+	size, err := table.Create(item.Partition, partType)
+	if err != nil {
+		return err
+	}
+	kernelPartitionUpdate(blkpg_ADD_PARTITION, item.Partition, size)
+	log.Printf("Partition created: %v (%v)\n", item.Path, formatSize(size))
+	return nil
+}
+
+// Extended partition in msdos table, which contain last logical partition
+// Расширенный раздел в таблице msdos, который содержит последний логический раздел
+type partitionExtendedLayer struct {
+	addOnlyScan
+	noFreeSpace
+	defaultDescribe
+}
+
+func (partitionExtendedLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	if err := step.BackupTable(item.Partition); err != nil {
+		return err
+	}
+	err := mbrExtendedPartitionResize(item.Partition, item.Size+item.FreeSpace)
+	if err != nil {
+		return stepError("WARNING!!!!!! Can't resize extended partition. Disk partition table can be damaged check it.", item.Path, err)
+	}
+	step.GrowChild(item.FreeSpace)
+	log.Printf("Extended partition resized: %v to %v (+%v)\n", item.Path, formatSize(item.Size+item.FreeSpace), formatSize(item.FreeSpace))
+	item.Size += item.FreeSpace
+	item.FreeSpace = 0
+	return nil
+}
+
+type swapMoveLayer struct {
+	addOnlyScan
+	noFreeSpace
+}
+
+func (swapMoveLayer) Describe(item storageItem) string {
+	return fmt.Sprint(item, " Swap will be disabled while move to ", formatSize(item.Partition.FirstByte+item.FreeSpace))
+}
+
+func (swapMoveLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	table, err := partitionTableOf(item.Partition)
+	if err != nil {
+		return err
+	}
+	if err = step.BackupTable(item.Partition); err != nil {
+		return err
+	}
+	if isSwapActive(item.Path) {
+		_, stderr, err := cmd("swapoff", item.Path)
+		if err != nil {
+			return stepError("Can't disable swap: ", item.Path, err, stderr)
+		}
+		step.ActiveSwaps[item.Path] = true
+	}
+	newFirstByte := item.Partition.FirstByte + item.FreeSpace
+	if err = table.Move(item.Partition, newFirstByte); err != nil {
+		return stepError("WARNING!!!!!! Can't move swap partition. Disk partition table can be damaged check it.", item.Path, err)
+	}
+	step.GrowChild(item.FreeSpace)
+	log.Printf("Swap partition moved: %v to %v (+%v for previous partition)\n", item.Path, formatSize(newFirstByte), formatSize(item.FreeSpace))
+	item.FreeSpace = 0
+	return nil
+}
+
+type swapCreateLayer struct {
+	addOnlyScan
+	noFreeSpace
+}
+
+func (swapCreateLayer) Describe(item storageItem) string {
+	return fmt.Sprint(item, " Create swap on moved partition with same UUID")
+}
+
+func (swapCreateLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	// Swap partition isn't used while move, so kernel can see new place of it without reboot.
+	// Раздел подкачки не используется во время перемещения, поэтому ядро может увидеть его новое место без перезагрузки.
+	if dev, err := blockDeviceByPath(item.Path); err != nil || dev.Start != item.Partition.FirstByte {
+		kernelPartitionMove(item.Partition)
+	}
+	if dev, err := blockDeviceByPath(item.Path); err != nil || dev.Start != item.Partition.FirstByte ||
+		getDiskSize(item.Path) != item.Size {
+		log.Printf("Kernel doesn't see moved swap partition. After reboot run: mkswap -U '%v' '%v'\n", item.UUID, item.Path)
+		step.NeedReboot = true
+		return nil
+	}
+	args := []string{}
+	if item.UUID != "" {
+		args = append(args, "-U", item.UUID)
+	}
+	args = append(args, item.Path)
+	_, stderr, err := cmd("mkswap", args...)
+	if err != nil {
+		return stepError("Can't create swap: ", item.Path, err, stderr)
+	}
+	if step.ActiveSwaps[item.Path] {
+		cmd("swapon", item.Path)
+	}
+	log.Printf("Swap created: %v (%v) UUID: %v\n", item.Path, formatSize(item.Size), item.UUID)
+	return nil
+}
+
+// Whole disk, it can't be extended
+// Диск целиком, он не может быть расширен
+type diskLayer struct {
+	addOnlyScan
+	noFreeSpace
+	defaultDescribe
+}
+
+func (diskLayer) Apply(step *layerStep) error {
+	return stepError("I don't know way to resize type: ", step.Item().Type)
+}
@@ -0,0 +1,96 @@
+package fsextender
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+func init() {
+	registerLayer(type_RAID, raidLayer{})
+}
+
+// Linux software RAID. Free space of it calculated while make plan by free space of members.
+// Программный RAID linux. Его свободное место вычисляется при построении плана по свободному месту участников.
+type raidLayer struct {
+	noFreeSpace
+	defaultDescribe
+}
+
+func (raidLayer) Scan(scan *layerScan, item storageItem) error {
+	dev, err := blockDeviceByPath(item.Path)
+	if err != nil {
+		return fmt.Errorf("Can't read RAID device from sysfs: %v (%v)", item.Path, err)
+	}
+	item.Path = dev.DevPath()
+	item.Size = dev.Size
+	item.Raid, err = readRaidInfo(dev.Name)
+	if err != nil {
+		return fmt.Errorf("Can't read RAID info: %v (%v)", item.Path, err)
+	}
+	if _, err = item.Raid.ArraySize(item.Raid.ComponentSize); err != nil {
+		return fmt.Errorf("RAID can't be extended: %v (%v)", item.Path, err)
+	}
+	raidIndex := scan.Add(item)
+
+	// Members of the array. Free space of RAID calculated while make plan, after scan all of members.
+	// Участники массива. Свободное место RAID считается при построении плана, после сканирования всех участников.
+	for _, slave := range dev.Slaves {
+		member, err := readBlockDevice(slave)
+		if err != nil {
+			log.Printf("Can't read member of RAID: %v (%v)\n", slave, err)
+			continue
+		}
+		scan.AddParent(member.DevPath(), member.Major, member.Minor, raidIndex)
+	}
+	return nil
+}
+
+// Members of RAID grow by same size - array use same size on every member.
+// Участники RAID расширяются на одинаковый размер - массив использует одинаковый объем на каждом участнике.
+func (raidLayer) Plan(storage []storageItem, index int) {
+	raidPlanMembers(storage, index)
+}
+
+// All of members extended already - they are before RAID in plan
+// Все участники массива уже расширены - они расположены в плане перед RAID
+func (raidLayer) Apply(step *layerStep) error {
+	item := step.Item()
+	sizeArg := "--size=max"
+	if item.Limit != 0 {
+		// Limited growth, size of every member in KiB
+		// Ограниченный рост, объем на каждом участнике в КиБ
+		component, err := item.Raid.ComponentSizeFor(item.Limit)
+		if err != nil {
+			return stepError("Can't calc size of RAID members:", item.Path, err)
+		}
+		sizeArg = "--size=" + formatUInt(component/1024)
+	}
+	for retry := 0; ; retry++ {
+		if retry == TRY_COUNT {
+			if item.FreeSpace == 0 {
+				return nil
+			}
+			return stepError("Can't grow RAID:", item.Path)
+		}
+		if retry > 0 {
+			log.Println("Try to grow RAID once more:", item.Path)
+			time.Sleep(time.Second)
+		}
+		_, errString, err := cmd("mdadm", "--grow", item.Path, sizeArg)
+		if err != nil {
+			log.Printf("Can't grow RAID: %v (%v) %v\n", item.Path, err, errString)
+			continue
+		}
+		newSize := getDiskSize(item.Path)
+		if newSize <= item.Size {
+			continue
+		}
+		addSpace := newSize - item.Size
+		step.GrowChild(addSpace)
+		log.Printf("RAID resized: %v to %v (+%v)\n", item.Path, formatSize(newSize), formatSize(addSpace))
+		item.Size = newSize
+		item.FreeSpace = 0
+		return nil
+	}
+}
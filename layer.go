@@ -0,0 +1,177 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+/*
+Storage layer: filesystem, partition, LVM volume and so on. Layer knows how to scan device of its type and how to
+extend it. Type of device detects by registration of layer: filesystems by type from signature on device (probeFS),
+block devices by major:minor numbers.
+
+Слой хранения: файловая система, раздел, том LVM и т.п. Слой знает, как сканировать устройство своего типа и как
+его расширить. Тип устройства определяется по регистрации слоя: файловые системы по типу из сигнатуры на устройстве
+(probeFS), блочные устройства по номерам major:minor.
+*/
+type layer interface {
+	// Read size of item, add it to storage and add underliing devices to scan.
+	// Error mean, that item skipped.
+	// Читает размер элемента, добавляет его в storage и добавляет нижележащие устройства к сканированию.
+	// Ошибка означает, что элемент пропущен.
+	Scan(scan *layerScan, item storageItem) error
+
+	// Detect free space of item, which can be extended to size of underliing parent (for example PV to size of
+	// partition). Called after scan, when sizes of all items are known.
+	// Определяет свободное место элемента, который может быть расширен до размера нижележащего parent (например PV
+	// до размера раздела). Вызывается после сканирования, когда размеры всех элементов известны.
+	FreeSpace(item *storageItem, parent storageItem)
+
+	// Description of step for print plan. item.FreeSpace is planned growth of item.
+	// Описание шага для печати плана. item.FreeSpace - планируемый рост элемента.
+	Describe(item storageItem) string
+
+	// Extend step.Item() and add growth to free space of child.
+	// Расширяет step.Item() и добавляет рост к свободному месту потомка.
+	Apply(step *layerStep) error
+}
+
+/*
+Optional interface of layer, which need fix free space of items while make plan, after filters.
+Необязательный интерфейс слоя, которому нужно поправить свободное место элементов при построении плана, после фильтров.
+*/
+type layerPlanner interface {
+	Plan(storage []storageItem, index int)
+}
+
+var (
+	layers   = make(map[storageItemType]layer)
+	fsLayers = make(map[string]layer) // By filesystem type from probeFS. По типу файловой системы из probeFS
+)
+
+func registerLayer(itemType storageItemType, l layer) {
+	if _, ok := layers[itemType]; ok {
+		panic("Layer registered twice: " + itemType.String())
+	}
+	layers[itemType] = l
+}
+
+func registerFSLayer(fsType string, l layer) {
+	if _, ok := fsLayers[fsType]; ok {
+		panic("Filesystem layer registered twice: " + fsType)
+	}
+	fsLayers[fsType] = l
+}
+
+// Layer of item, nil if type of item hasn't layer (skipped or unknown items)
+// Слой элемента, nil если у типа элемента нет слоя (пропущенные или неизвестные элементы)
+func layerOf(item storageItem) layer {
+	if item.Type == type_FS {
+		return fsLayers[item.FSType]
+	}
+	return layers[item.Type]
+}
+
+// State of scan for layers
+// Состояние сканирования для слоёв
+type layerScan struct {
+	Storage []storageItem
+	ToScan  []storageItem
+}
+
+// Add item to storage. Return index of the item.
+// Добавляет элемент в storage. Возвращает индекс элемента.
+func (scan *layerScan) Add(item storageItem) int {
+	scan.Storage = append(scan.Storage, item)
+	return len(scan.Storage) - 1
+}
+
+// Add underliing device to scan, if type of the device is known
+// Добавляет нижележащее устройство к сканированию, если тип устройства известен
+func (scan *layerScan) AddParent(path string, major, minor int, child int) {
+	parent := storageItem{Path: path, Child: child, Type: getTypeByMajorMinor(major, minor)}
+	if parent.Type != type_UNKNOWN {
+		scan.ToScan = append(scan.ToScan, parent)
+	}
+}
+
+// Detect free space of items, which can be extended to size of underliing items
+// Определяет свободное место элементов, которые могут быть расширены до размера нижележащих элементов
+func scanFreeSpace(storage []storageItem) {
+	for _, parent := range storage {
+		if parent.Child == -1 {
+			continue
+		}
+		child := &storage[parent.Child]
+		if l := layerOf(*child); l != nil {
+			l.FreeSpace(child, parent)
+		}
+	}
+}
+
+// Step of execute plan for layers
+// Шаг выполнения плана для слоёв
+type layerStep struct {
+	Plan        []storageItem
+	Index       int
+	Options     doOptions
+	ActiveSwaps map[string]bool // Swaps, which disabled while move. Разделы подкачки, выключенные при перемещении
+	NeedReboot  bool            // Set by layer, if kernel doesn't see changes. Устанавливается слоем, если ядро не видит изменений
+}
+
+func (step *layerStep) Item() *storageItem {
+	return &step.Plan[step.Index]
+}
+
+// Add growth of item to free space of its child
+// Добавляет рост элемента к свободному месту его потомка
+func (step *layerStep) GrowChild(addSpace uint64) {
+	if child := step.Item().Child; child != -1 {
+		step.Plan[child].FreeSpace += addSpace
+	}
+}
+
+// Backup partition table before change it
+// Сохраняет резервную копию таблицы разделов перед её изменением
+func (step *layerStep) BackupTable(part partition, extraOffsets ...uint64) error {
+	backupPath, err := backupPartitionTable(part.Disk.Path, step.Options.BackupDir, extraOffsets...)
+	if err != nil {
+		return stepError("Can't backup partition table, skip change it: ", part.Disk.Path, err)
+	}
+	log.Printf("Partition table backup: %v. Restore: %v restore '%v'\n", backupPath, os.Args[0], backupPath)
+	return nil
+}
+
+// Error of step with message as log.Println
+// Ошибка шага с сообщением как у log.Println
+func stepError(args ...interface{}) error {
+	return errors.New(strings.TrimSpace(fmt.Sprintln(args...)))
+}
+
+func stepErrorf(format string, args ...interface{}) error {
+	return errors.New(strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+// Layer of items, which only added to plan and doesn't scan further
+// Слой элементов, которые только добавляются в план и не сканируются дальше
+type addOnlyScan struct{}
+
+func (addOnlyScan) Scan(scan *layerScan, item storageItem) error {
+	scan.Add(item)
+	return nil
+}
+
+// Layer without own free space and with default description
+// Слой без собственного свободного места и с описанием по умолчанию
+type noFreeSpace struct{}
+
+func (noFreeSpace) FreeSpace(item *storageItem, parent storageItem) {}
+
+type defaultDescribe struct{}
+
+func (defaultDescribe) Describe(item storageItem) string {
+	return item.String()
+}
@@ -1,12 +1,11 @@
 package fsextender
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"github.com/ogier/pflag"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -19,88 +18,226 @@ func Main() int {
 	showReadme := pflag.Bool("readme", false, "Show readme")
 	do := pflag.Bool("do", false, "Execute plan instead of print it")
 	filter := pflag.StringP("filter", "f", FILTER_LVM_ALREADY_PLACED, "filter of disks, which use for partition extends")
+	moveSwap := pflag.Bool("move-swap", false, "Move swap partition from end of disk for extend previous partition")
+	savePlan := pflag.String("save-plan", "", "Save plan to file for review and --apply-plan")
+	applyPlan := pflag.String("apply-plan", "", "Verify, that plan doesn't changed since --save-plan, before print or execute it")
+	sizeString := pflag.String("size", "max", "Target size: absolute (200G), relative (+50G) or percent of available growth (80%)")
+	vgReserveString := pflag.String("vg-reserve", "", "Free space of LVM volume group, which doesn't use for extend LV: size (10G) or percent of group (10%)")
+	format := pflag.String("format", "text", "Format of plan output: text or json")
+	btrfsAddDevice := pflag.Bool("btrfs-add-device", false, "Add new partitions to btrfs filesystem as new devices")
+	cryptKeyFile := pflag.String("crypt-key-file", "", "Key file for resize encrypted (LUKS) devices, if cryptsetup requires passphrase")
+	stateFile := pflag.String("state-file", "", "Write state journal of --do to the file for --resume after reboot")
+	resume := pflag.Bool("resume", false, "Continue extend from --state-file after reboot")
+	backupDir := pflag.String("backup-dir", "/var/backups/fsextender", "Directory for backups of partition tables, which saved before change the tables")
+	all := pflag.Bool("all", false, "Extend all mounted ext and xfs filesystems")
+	vgPolicyString := pflag.String("vg-policy", "equal", "Divide free space of LVM volume group between few LVs: equal, proportional or weights (/home=2,/var=1)")
+	xfsGrowRT := pflag.Bool("xfs-grow-rt", false, "Grow realtime section of xfs to size of its device (rtdev)")
+	xfsGrowLog := pflag.Bool("xfs-grow-log", false, "Grow external log of xfs to size of its device (logdev)")
+	recordCommands := pflag.String("record-commands", "", "Write external commands with output to the file, for replay in tests")
 	pflag.Parse()
 
 	if *showHelp {
 		txt, err := usageTxtBytes()
 		if err != nil {
 			log.Println(err)
-			return 11
+			return exit_ERROR
 		}
 		fmt.Println(string(txt))
-		return 0
+		return exit_OK
 	}
 
 	if *showReadme {
 		txt, err := readmeMdBytes()
 		if err != nil {
 			log.Println(err)
-			return 11
+			return exit_ERROR
 		}
 		fmt.Println(string(txt))
-		return 0
+		return exit_OK
 	}
 
-	if pflag.NArg() != 1 || !filepath.IsAbs(pflag.Arg(0)) {
-		printShortUsage()
-		return 11
+	if *format != "text" && *format != "json" {
+		return exitWithError(usageError{fmt.Errorf("Unknown format: %v", *format)})
 	}
 
-	startPoint := pflag.Arg(0)
-	storage, err := extendScanWays(startPoint)
-	//	fmt.Println("SCAN PLAN:")
-	//	extendPrint(storage)
-	//	fmt.Println()
-	//	fmt.Println()
-	//	fmt.Println()
+	size, err := parseSizeTarget(*sizeString)
 	if err != nil {
-		panic(err)
+		return exitWithError(usageError{fmt.Errorf("Bad target size: %v", err)})
 	}
-	plan, err := extendPlan(storage, *filter)
+
+	vgReserve, err := parseSizeReserve(*vgReserveString)
 	if err != nil {
-		log.Println("Error while make extend plan:", err)
-		return 11
+		return exitWithError(usageError{fmt.Errorf("Bad LVM volume group reserve: %v", err)})
+	}
+
+	policy, err := parseVGPolicy(*vgPolicyString)
+	if err != nil {
+		return exitWithError(usageError{fmt.Errorf("Bad LVM volume group policy: %v", err)})
+	}
+
+	if *recordCommands != "" {
+		runner = &recordRunner{Runner: runner, Path: *recordCommands}
+	}
+
+	if pflag.NArg() == 2 && pflag.Arg(0) == "restore" {
+		if err = restorePartitionTable(pflag.Arg(1)); err != nil {
+			return exitWithError(err)
+		}
+		fmt.Println("OK")
+		return exit_OK
+	}
+
+	if *resume {
+		return resumeDo(*stateFile, doOptions{CryptKeyFile: *cryptKeyFile, BackupDir: *backupDir, XFSGrowRT: *xfsGrowRT,
+			XFSGrowLog: *xfsGrowLog})
+	}
+
+	startPoints := pflag.Args()
+	if *all {
+		mounted, err := mountedStartPoints()
+		if err != nil {
+			return exitWithError(err)
+		}
+		startPoints = appendUniqueStrings(startPoints, mounted...)
+	}
+	if len(startPoints) == 0 {
+		printShortUsage()
+		return exit_USAGE
+	}
+	for _, startPoint := range startPoints {
+		if !filepath.IsAbs(startPoint) {
+			printShortUsage()
+			return exit_USAGE
+		}
+	}
+
+	startPoint := strings.Join(startPoints, ",")
+	plan, err := extendPlanAll(startPoints, planOptions{Filter: *filter, MoveSwap: *moveSwap,
+		BtrfsAddDevice: *btrfsAddDevice, Size: size, VGReserve: vgReserve}, policy)
+	if err != nil {
+		return exitWithError(err)
+	}
+
+	if *applyPlan != "" {
+		saved, err := readPlanFile(*applyPlan)
+		if err != nil {
+			return exitWithError(usageError{fmt.Errorf("Can't read saved plan: %v", err)})
+		}
+		if diffs := planDiff(saved, makePlanJSON(startPoint, plan)); len(diffs) > 0 {
+			log.Println("Plan changed since it was saved. Refuse to continue. Differences (saved -> current):")
+			for _, diff := range diffs {
+				log.Println("    " + diff)
+			}
+			return exit_PLAN
+		}
+		log.Println("Plan is same as saved:", *applyPlan)
+	}
+
+	if *savePlan != "" {
+		if err = writePlanFile(*savePlan, startPoint, plan); err != nil {
+			return exitWithError(usageError{fmt.Errorf("Can't save plan: %v", err)})
+		}
 	}
 
 	if *do {
-		if extendDo(plan) {
-			fmt.Println("NEED REBOOT AND START ME ONCE AGAIN.")
-			return 128
-		} else {
-			fmt.Println("OK")
-			return 0
+		if !planHasGrowth(plan) {
+			log.Println(errNothingToExtend)
+			fmt.Println("NOTHING TO EXTEND")
+			return exit_NOTHING_TO_EXTEND
+		}
+		options := doOptions{CryptKeyFile: *cryptKeyFile, BackupDir: *backupDir, XFSGrowRT: *xfsGrowRT,
+			XFSGrowLog: *xfsGrowLog}
+		if *stateFile != "" {
+			options.Journal = newJournal(*stateFile, startPoint, plan)
 		}
+		return printDoResult(extendDo(plan, options))
 	} else {
-		extendPrint(plan)
-		return 0
+		if *format == "json" {
+			if err = extendPrintJSON(os.Stdout, startPoint, plan); err != nil {
+				return exitWithError(fmt.Errorf("Can't print plan: %v", err))
+			}
+		} else {
+			extendPrint(plan)
+		}
+		// Print of plan is success even without growth, code 10 only for --do
+		// Печать плана - успешное завершение даже без роста, код 10 только для --do
+		if !planHasGrowth(plan) {
+			log.Println(errNothingToExtend)
+		}
+		return exit_OK
+	}
+}
+
+// Continue extend from state file after reboot
+// Продолжает расширение из файла состояния после перезагрузки
+func resumeDo(stateFile string, options doOptions) int {
+	if stateFile == "" {
+		return exitWithError(usageError{errors.New("Need --state-file for resume")})
+	}
+	j, err := readJournal(stateFile)
+	if err != nil {
+		return exitWithError(usageError{fmt.Errorf("Can't read state: %v", err)})
+	}
+	if err = j.CheckReboot(); err != nil {
+		var doErr doError
+		if errors.As(err, &doErr) {
+			return exitWithError(err)
+		}
+		log.Println(err)
+		fmt.Println("NEED REBOOT AND START ME ONCE AGAIN.")
+		return exit_NEED_REBOOT
 	}
+	if j.Complete {
+		log.Println("Extend was completed already:", j.StartPoint)
+		fmt.Println("OK")
+		return exit_OK
+	}
+	log.Printf("Resume extend %v from state file %v\n", j.StartPoint, stateFile)
+	options.Journal = j
+	return printDoResult(extendDo(j.Plan, options))
+}
+
+// Print result of extend and return exit code
+// Печатает результат расширения и возвращает код завершения
+func printDoResult(res doResult) int {
+	if err := res.Err(); err != nil {
+		log.Println("Extend failed:", err)
+		log.Println("Steps:")
+		res.LogSummary()
+		fmt.Println("FAILED")
+		return exitCode(err)
+	}
+	if res.NeedReboot {
+		fmt.Println("NEED REBOOT AND START ME ONCE AGAIN.")
+		return exit_NEED_REBOOT
+	}
+	fmt.Println("OK")
+	return exit_OK
 }
 
 func printShortUsage() {
-	fmt.Printf(`Short usage: %v [options] <start_point>
+	fmt.Printf(`Short usage: %v [options] <start_point> [<start_point>...]
+Extend all mounted ext and xfs filesystems: %v [options] --all
+Restore partition table: %v restore <backup_file>
 Detect result:
 OK - if extended compele. Return code 0.
 NEED REBOOT AND START ME ONCE AGAIN. - if need reboot and run command with same parameters. Return code 128.
-
-0 < Code < 128 mean error exit. (Now it print usages and panic only).
+FAILED - if some steps failed. Steps, which depend on failed steps, don't run, independent steps run. Return code 12.
+NOTHING TO EXTEND - if plan hasn't any growth, nothing changed. Return code 10 with --do, 0 without --do.
+Other exit codes: 1 - unexpected error, 11 - usage error, 13 - scan error, 14 - plan error or plan changed since saved.
 
 Options:
-`, os.Args[0])
+`, os.Args[0], os.Args[0], os.Args[0])
 	pflag.PrintDefaults()
 }
 
 func cmd(cmd string, args ...string) (stdout, errout string, err error) {
-	bufStd := &bytes.Buffer{}
-	bufErr := &bytes.Buffer{}
-	command := exec.Command(cmd, args...)
-	command.Stdout = bufStd
-	command.Stderr = bufErr
-	err = command.Run()
+	stdout, errout, err = runner.Run(cmd, args...)
 	if DEBUG {
 		log.Printf("CMD: '%v' '%v'\n", cmd, strings.Join(args, "' '"))
-		log.Printf("RES:\n%v\nERR:\n%v\nERROR:\n%v\n\n", bufStd.String(), bufErr.String(), err)
+		log.Printf("RES:\n%v\nERR:\n%v\nERROR:\n%v\n\n", stdout, errout, err)
 	}
-	return bufStd.String(), bufErr.String(), err
+	return stdout, errout, err
 }
 
 /*
@@ -1361,6 +1361,90 @@ func TestIssue13_ReadExtendedPartitions(t *testing.T) {
 	call("--do", part)
 }
 
+func TestExt4LogicalPartitionMSDOS(t *testing.T) {
+	disk, err := createTmpDevice("msdos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteTmpDevice(disk)
+
+	sudo("parted", "-s", disk, "unit", "b", "mkpart", "extended", s(MSDOS_START_BYTE), s(2*GB-1))
+	sudo("parted", "-s", disk, "unit", "b", "mkpart", "logical", s(GB), s(2*GB-1))
+	part := disk + "p5"
+	sudo("mkfs.ext4", part)
+	err = os.MkdirAll(TMP_MOUNT_DIR, 0700)
+	if err == nil {
+		defer os.Remove(TMP_MOUNT_DIR)
+	} else {
+		t.Fatal(err)
+	}
+
+	sudo("mount", part, TMP_MOUNT_DIR)
+	defer sudo("umount", part)
+	call(TMP_MOUNT_DIR, "--do")
+
+	if blocks := df(part); blocks != 98 {
+		t.Error("Bad filesystem size:", blocks)
+	}
+
+	needPartitions := []testPartition{
+		{1, MSDOS_START_BYTE, MSDOS_LAST_BYTE},
+		{5, GB, MSDOS_LAST_BYTE},
+	}
+	partDiff := pretty.Diff(readPartitions(disk), needPartitions)
+	if partDiff != nil {
+		t.Error(partDiff)
+	}
+}
+
+func TestExt4PartitionMoveSwapMSDOS(t *testing.T) {
+	disk, err := createTmpDevice("msdos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteTmpDevice(disk)
+
+	sudo("parted", "-s", disk, "unit", "b", "mkpart", "primary", s(MSDOS_START_BYTE), s(GB-1))
+	sudo("parted", "-s", disk, "unit", "b", "mkpart", "primary", s(GB), s(2*GB-1))
+	part := disk + "p1"
+	swap := disk + "p2"
+	sudo("mkfs.ext4", part)
+	sudo("mkswap", "-U", "8d6a4c2e-6f0c-4b47-9d8b-0a3a3e6b2f11", swap)
+	err = os.MkdirAll(TMP_MOUNT_DIR, 0700)
+	if err == nil {
+		defer os.Remove(TMP_MOUNT_DIR)
+	} else {
+		t.Fatal(err)
+	}
+
+	sudo("mount", part, TMP_MOUNT_DIR)
+	defer sudo("umount", part)
+
+	// Without --move-swap partitions mustn't change
+	call(TMP_MOUNT_DIR, "--do")
+	needPartitions := []testPartition{
+		{1, MSDOS_START_BYTE, GB - 1},
+		{2, GB, 2*GB - 1},
+	}
+	partDiff := pretty.Diff(readPartitions(disk), needPartitions)
+	if partDiff != nil {
+		t.Error(partDiff)
+	}
+
+	call(TMP_MOUNT_DIR, "--move-swap", "--do")
+	needPartitions = []testPartition{
+		{1, MSDOS_START_BYTE, TMP_DISK_SIZE - GB - 1},
+		{2, TMP_DISK_SIZE - GB, MSDOS_LAST_BYTE},
+	}
+	partDiff = pretty.Diff(readPartitions(disk), needPartitions)
+	if partDiff != nil {
+		t.Error(partDiff)
+	}
+	if probe, err := probeFS(swap); probe.UUID != "8d6a4c2e-6f0c-4b47-9d8b-0a3a3e6b2f11" {
+		t.Error("Bad swap UUID:", probe.UUID, err)
+	}
+}
+
 // https://github.com/rekby/fsextender/issues/14
 func TestIssue14_ExtractPartNumberFromLinks(t *testing.T) {
 	disk, err := createTmpDevice("msdos")
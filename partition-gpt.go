@@ -0,0 +1,133 @@
+package fsextender
+
+import (
+	"fmt"
+	"github.com/rekby/gpt"
+	"os"
+)
+
+func init() {
+	registerPartitionTable("gpt", gptPartitionTable{})
+}
+
+// GPT partition table. Primary table at start of disk, backup table at end of disk.
+// Таблица разделов GPT. Основная таблица в начале диска, резервная - в конце диска.
+type gptPartitionTable struct{}
+
+// Read gpt table of disk. Table for new disk size creates if partition lastLBA out of usable space of current table.
+// Читает таблицу gpt диска. Если lastLBA раздела за пределами используемого места текущей таблицы - создаётся
+// таблица для нового размера диска.
+func gptReadTable(diskIO *os.File, disk *diskInfo) (gpt.Table, error) {
+	if _, err := diskIO.Seek(int64(disk.SectorSizeLogical), 0); err != nil {
+		return gpt.Table{}, err
+	}
+	return gpt.ReadTable(diskIO, disk.SectorSizeLogical)
+}
+
+// Check, that partition fit in table. Create table for new disk size if need.
+// Проверяет, что раздел помещается в таблицу. При необходимости создаёт таблицу для нового размера диска.
+func gptFitPartition(table gpt.Table, disk *diskInfo, number uint32) (gpt.Table, bool) {
+	if table.Partitions[number-1].LastLBA <= table.Header.LastUsableLBA {
+		return table, true
+	}
+	table = table.CreateTableForNewDiskSize(disk.Size / disk.SectorSizeLogical)
+	return table, table.Partitions[number-1].LastLBA <= table.Header.LastUsableLBA
+}
+
+func (gptPartitionTable) Resize(part partition, newSize uint64) error {
+	disk := part.Disk
+	diskIO, err := os.OpenFile(disk.Path, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return stepError("Can't open disk: ", disk.Path, err)
+	}
+	defer diskIO.Close()
+	gptTable, err := gptReadTable(diskIO, disk)
+	if err != nil {
+		return stepError("Can't read gpt table: ", part.Path, err)
+	}
+	if uint32(len(gptTable.Partitions)) < part.Number {
+		return stepError("gpt bad partition number")
+	}
+	if newSize > part.Size() {
+		gptTable.Partitions[part.Number-1].LastLBA += (newSize - part.Size()) / disk.SectorSizeLogical
+	}
+	gptTable, ok := gptFitPartition(gptTable, disk, part.Number)
+	if !ok {
+		return stepError("ATTENTION!!! Error in calc of GPT partition size", part.Path)
+	}
+
+	// First write table at end of disk, becouse it can be empty after extend of phisical disk.
+	// Сначала записываем таблицу разделов в конец диска, т.к. она может отсутствовать на обычном месте после расширения диска
+	if err = gptTable.CreateOtherSideTable().Write(diskIO); err != nil {
+		return stepError("WARNING!!! Write GPT PRIMARY TABLE error. DATA MAY BE LOST.", part.Path, err)
+	}
+	if err = gptTable.Write(diskIO); err != nil {
+		return stepError("WARNING!!! Write GPT SECONDARY TABLE error. DATA MAY BE LOST.", part.Path, err)
+	}
+	return nil
+}
+
+func (gptPartitionTable) Create(part partition, partType newPartitionType) (size uint64, err error) {
+	disk := part.Disk
+	diskIO, err := os.OpenFile(disk.Path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, stepError("Can't open disk for new partition in gpt:", disk.Path, err)
+	}
+	defer diskIO.Close()
+	gptTable, err := gptReadTable(diskIO, disk)
+	if err != nil {
+		return 0, stepError("Can't read gpt table, new partition: ", disk.Path, err)
+	}
+	if int(part.Number) >= len(gptTable.Partitions) || part.Number < 1 {
+		return 0, stepError("Bad partition number for create partition in gpt: ", disk.Path, part.Number)
+	}
+	gptPart := &gptTable.Partitions[part.Number-1]
+	gptPart.FirstLBA = part.FirstByte / disk.SectorSizeLogical
+	gptPart.LastLBA = part.LastByte / disk.SectorSizeLogical
+	gptPart.Type = partType.GPT
+	size = (gptPart.LastLBA - gptPart.FirstLBA + 1) * disk.SectorSizeLogical
+
+	gptTable, ok := gptFitPartition(gptTable, disk, part.Number)
+	if !ok {
+		return 0, stepError("ATTENTION!!! Error in calc of GPT partition size2", part.Path)
+	}
+	if err = gptTable.Write(diskIO); err != nil {
+		return 0, stepError("WARNING ERROR WHILE WRITE PRIMARY GPT PARTITION TABLE: ", disk.Path, err)
+	}
+	if err = gptTable.CreateOtherSideTable().Write(diskIO); err != nil {
+		return 0, stepError("WARNING ERROR WHILE WRITE SECONDARY GPT PARTITION TABLE: ", disk.Path, err)
+	}
+	return size, nil
+}
+
+func (gptPartitionTable) Move(part partition, newFirstByte uint64) error {
+	disk := part.Disk
+	firstLBA := newFirstByte / disk.SectorSizeLogical
+	lbaLen := part.Size() / disk.SectorSizeLogical
+
+	diskIO, err := os.OpenFile(disk.Path, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return err
+	}
+	defer diskIO.Close()
+	gptTable, err := gptReadTable(diskIO, disk)
+	if err != nil {
+		return err
+	}
+	if uint32(len(gptTable.Partitions)) < part.Number {
+		return fmt.Errorf("gpt bad partition number")
+	}
+	gptTable.Partitions[part.Number-1].FirstLBA = firstLBA
+	gptTable.Partitions[part.Number-1].LastLBA = firstLBA + lbaLen - 1
+	gptTable, ok := gptFitPartition(gptTable, disk, part.Number)
+	if !ok {
+		return fmt.Errorf("Error in calc of GPT partition place")
+	}
+
+	// First write table at end of disk, becouse it can be empty after extend of phisical disk.
+	// Сначала записываем таблицу разделов в конец диска, т.к. она может отсутствовать на обычном месте после расширения диска
+	if err = gptTable.CreateOtherSideTable().Write(diskIO); err != nil {
+		return err
+	}
+	return gptTable.Write(diskIO)
+}
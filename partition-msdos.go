@@ -0,0 +1,273 @@
+package fsextender
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/rekby/mbr"
+	"os"
+)
+
+func init() {
+	registerPartitionTable("msdos", msdosPartitionTable{})
+}
+
+// msdos partition table: up to 4 primary partitions, logical partitions in extended partition
+// Таблица разделов msdos: до 4 основных разделов, логические разделы внутри расширенного раздела
+type msdosPartitionTable struct{}
+
+func (msdosPartitionTable) Resize(part partition, newSize uint64) error {
+	if part.Logical {
+		err := mbrLogicalPartitionResize(part, newSize)
+		if err != nil {
+			return stepError("WARNING!!!!!! Can't resize logical partition. Disk partition table can be damaged check it.", part.Path, err)
+		}
+		return nil
+	}
+	diskIO, err := os.OpenFile(part.Disk.Path, os.O_RDONLY|os.O_SYNC, 0)
+	if err != nil {
+		return stepError("Can't open disk: ", part.Disk.Path, err)
+	}
+	partTable, err := mbr.Read(diskIO)
+	diskIO.Close()
+	if err != nil {
+		return stepError("Can't read partition table: ", part.Disk.Path, err)
+	}
+	sectorSize := newSize / part.Disk.SectorSizeLogical
+	if sectorSize > MAX_UINT32 {
+		// Тут возможно окргление размера в меньшую сторону, но пока для простоты - просто пропускаем.
+		return stepErrorf("New partition size greater then can be in msdos table. SKIP IT.")
+	}
+	partTable.GetPartition(int(part.Number)).SetLBALen(uint32(sectorSize))
+
+	diskIO, err = os.OpenFile(part.Disk.Path, os.O_WRONLY|os.O_SYNC, 0)
+	if err != nil {
+		return stepError("Can't open disk (2): ", part.Disk.Path, err)
+	}
+	err = partTable.Write(diskIO)
+	diskIO.Close()
+	if err != nil {
+		return stepError("WARNING!!!!!! Can't write new partition table. Disk partition table can be damaged check it.")
+	}
+	return nil
+}
+
+func (msdosPartitionTable) Create(part partition, partType newPartitionType) (size uint64, err error) {
+	if part.Logical {
+		size, err = mbrLogicalPartitionCreate(part, partType.MBR)
+		if err != nil {
+			return 0, stepError("Can't create logical partition: ", part.Path, err)
+		}
+		return size, nil
+	}
+	if part.Number > 4 {
+		return 0, stepError("WARNING: Can't create partition with number > 4 in msdos partition table.")
+	}
+	diskIO, err := os.OpenFile(part.Disk.Path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, stepError("Can't create partition: ", part.Path, err)
+	}
+	defer diskIO.Close()
+	partTable, err := mbr.Read(diskIO)
+	if err != nil {
+		return 0, stepError("Can't read mbr partition table: ", part.Path, err)
+	}
+	mbrPart := partTable.GetPartition(int(part.Number))
+	if mbrPart == nil {
+		return 0, stepError("Can't get mbr partition: ", part.Path)
+	}
+	if !mbrPart.IsEmpty() {
+		return 0, stepError("Mbr partition isn't empty: ", part.Path)
+	}
+	mbrPart.SetType(partType.MBR)
+	lbaStart := part.FirstByte / part.Disk.SectorSizeLogical
+	if lbaStart >= MAX_UINT32 {
+		return 0, stepError("Can't create msdos partition - sector number overflow", part.Path)
+	}
+	mbrPart.SetLBAStart(uint32(lbaStart))
+	bytesLen := part.LastByte - part.FirstByte + 1
+	lbaLen := (bytesLen) / part.Disk.SectorSizeLogical
+	if bytesLen%part.Disk.SectorSizeLogical != 0 {
+		lbaLen += 1
+	}
+	if uint64(mbrPart.GetLBAStart())+lbaLen > MAX_UINT32 {
+		lbaLen = uint64(MAX_UINT32 - mbrPart.GetLBAStart())
+	}
+	mbrPart.SetLBALen(uint32(lbaLen))
+	if partTable.Check() != nil {
+		return 0, stepError("Bad partition table after virtual create partition ", part.Path, partTable.Check())
+	}
+	if _, err = diskIO.Seek(0, 0); err != nil {
+		return 0, stepError("Mbr, can't seek diskIO", err)
+	}
+	if err = partTable.Write(diskIO); err != nil {
+		return 0, stepError("Mbr, can't write", err)
+	}
+	return lbaLen * part.Disk.SectorSizeLogical, nil
+}
+
+func (msdosPartitionTable) Move(part partition, newFirstByte uint64) error {
+	if part.Logical {
+		return fmt.Errorf("Can't move logical partition")
+	}
+	disk := part.Disk
+	firstLBA := newFirstByte / disk.SectorSizeLogical
+	lbaLen := part.Size() / disk.SectorSizeLogical
+
+	diskIO, err := os.OpenFile(disk.Path, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return err
+	}
+	defer diskIO.Close()
+
+	partTable, err := mbr.Read(diskIO)
+	if err != nil {
+		return err
+	}
+	mbrPart := partTable.GetPartition(int(part.Number))
+	if mbrPart == nil || mbrPart.IsEmpty() {
+		return fmt.Errorf("Can't find partition %v", part.Number)
+	}
+	if firstLBA+lbaLen > MAX_UINT32 {
+		return fmt.Errorf("New partition place greater then can be in msdos table")
+	}
+	mbrPart.SetLBAStart(uint32(firstLBA))
+	mbrPart.SetLBALen(uint32(lbaLen))
+	if err = partTable.Check(); err != nil {
+		return err
+	}
+	if _, err = diskIO.Seek(0, 0); err != nil {
+		return err
+	}
+	return partTable.Write(diskIO)
+}
+
+// Change size of extended partition in msdos partition table
+// Изменяет размер расширенного раздела в таблице разделов msdos
+func mbrExtendedPartitionResize(extended partition, newSize uint64) error {
+	disk := extended.Disk
+	diskIO, err := os.OpenFile(disk.Path, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return err
+	}
+	defer diskIO.Close()
+
+	partTable, err := mbr.Read(diskIO)
+	if err != nil {
+		return err
+	}
+	part := partTable.GetPartition(int(extended.Number))
+	if part == nil || !isMbrExtended(part.GetType()) {
+		return fmt.Errorf("Partition %v isn't extended partition", extended.Number)
+	}
+	lbaLen := newSize / disk.SectorSizeLogical
+	if uint64(part.GetLBAStart())+lbaLen > MAX_UINT32 {
+		return fmt.Errorf("New partition size greater then can be in msdos table")
+	}
+	part.SetLBALen(uint32(lbaLen))
+	if err = partTable.Check(); err != nil {
+		return err
+	}
+	if _, err = diskIO.Seek(0, 0); err != nil {
+		return err
+	}
+	return partTable.Write(diskIO)
+}
+
+// Change size of logical partition in EBR, which describe the partition
+// Изменяет размер логического раздела в описывающем его EBR
+func mbrLogicalPartitionResize(logical partition, newSize uint64) error {
+	disk := logical.Disk
+	diskIO, err := os.OpenFile(disk.Path, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return err
+	}
+	defer diskIO.Close()
+
+	ebr, err := readEBR(diskIO, logical.EBRByte)
+	if err != nil {
+		return err
+	}
+	part := ebr.GetPartition(1)
+	if part.IsEmpty() || (logical.EBRByte/disk.SectorSizeLogical+uint64(part.GetLBAStart()))*disk.SectorSizeLogical != logical.FirstByte {
+		return fmt.Errorf("EBR doesn't describe the logical partition: %v", logical.Path)
+	}
+	lbaLen := newSize / disk.SectorSizeLogical
+	if logical.FirstByte/disk.SectorSizeLogical+lbaLen > MAX_UINT32 {
+		return fmt.Errorf("New partition size greater then can be in msdos table")
+	}
+	part.SetLBALen(uint32(lbaLen))
+	if _, err = diskIO.Seek(int64(logical.EBRByte), 0); err != nil {
+		return err
+	}
+	return ebr.Write(diskIO)
+}
+
+// Create logical partition after last logical partition. Extend extended partition if new partition out of it.
+// Создаёт логический раздел после последнего логического раздела. Если новый раздел выходит за границы расширенного
+// раздела - расширенный раздел увеличивается.
+func mbrLogicalPartitionCreate(logical partition, partType mbr.PartitionType) (size uint64, err error) {
+	disk := logical.Disk
+	sectorSize := disk.SectorSizeLogical
+	extended, _, ok := disk.extendedPartition()
+	if !ok {
+		return 0, fmt.Errorf("Disk hasn't extended partition: %v", disk.Path)
+	}
+	extendedStartLBA := extended.FirstByte / sectorSize
+	ebrLBA := logical.EBRByte / sectorSize
+	firstLBA := logical.FirstByte / sectorSize
+	lastLBA := logical.LastByte / sectorSize
+	if lastLBA >= MAX_UINT32 {
+		lastLBA = MAX_UINT32 - 1
+	}
+	if firstLBA > lastLBA {
+		return 0, fmt.Errorf("Bad size of new logical partition")
+	}
+
+	if logical.LastByte > extended.LastByte {
+		err = mbrExtendedPartitionResize(extended, (lastLBA+1)*sectorSize-extended.FirstByte)
+		if err != nil {
+			return 0, fmt.Errorf("Can't extend extended partition: %v", err)
+		}
+	}
+
+	diskIO, err := os.OpenFile(disk.Path, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer diskIO.Close()
+
+	// Create new EBR from empty sector
+	// Создаём новый EBR из пустого сектора
+	ebr, _ := mbr.Read(bytes.NewReader(make([]byte, sectorSize)))
+	ebr.FixSignature()
+	part := ebr.GetPartition(1)
+	part.SetType(partType)
+	part.SetLBAStart(uint32(firstLBA - ebrLBA))
+	part.SetLBALen(uint32(lastLBA - firstLBA + 1))
+	if _, err = diskIO.Seek(int64(logical.EBRByte), 0); err != nil {
+		return 0, err
+	}
+	if err = ebr.Write(diskIO); err != nil {
+		return 0, err
+	}
+
+	// Link new EBR to chain. First EBR at start of extended partition hasn't link.
+	// Добавляем новый EBR в цепочку. На первый EBR, в начале расширенного раздела, ссылки нет.
+	if last, hasLogical := disk.lastLogicalPartition(); hasLogical {
+		prevEBR, err := readEBR(diskIO, last.EBRByte)
+		if err != nil {
+			return 0, err
+		}
+		link := prevEBR.GetPartition(2)
+		link.SetType(mbr_PART_EXTENDED)
+		link.SetLBAStart(uint32(ebrLBA - extendedStartLBA))
+		link.SetLBALen(uint32(lastLBA - ebrLBA + 1))
+		if _, err = diskIO.Seek(int64(last.EBRByte), 0); err != nil {
+			return 0, err
+		}
+		if err = prevEBR.Write(diskIO); err != nil {
+			return 0, err
+		}
+	}
+	return (lastLBA - firstLBA + 1) * sectorSize, nil
+}
@@ -0,0 +1,295 @@
+package fsextender
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/*
+Signature of filesystem, volume or partition table on device. Type named as in blkid: ext4, xfs, btrfs, swap,
+LVM2_member, crypto_LUKS, linux_raid_member. PTType - type of partition table: gpt, dos.
+
+Сигнатура файловой системы, тома или таблицы разделов на устройстве. Тип называется как в blkid: ext4, xfs, btrfs,
+swap, LVM2_member, crypto_LUKS, linux_raid_member. PTType - тип таблицы разделов: gpt, dos.
+*/
+type probeResult struct {
+	Type   string
+	UUID   string
+	Label  string
+	PTType string
+}
+
+// Size of device start, which read once for probe all signatures at start of device
+// Размер начала устройства, которое читается один раз для проверки всех сигнатур в начале устройства
+const probe_HEAD_SIZE = 128 * 1024
+
+// Probe of signature. Return false if signature not found.
+// Проверка сигнатуры. Возвращает false, если сигнатура не найдена.
+type probeFunc func(dev *probeDevice) (res probeResult, ok bool)
+
+// Signatures in order of check. Filesystems before partition tables, because MBR signature can be in boot sector of
+// filesystem.
+// Сигнатуры в порядке проверки. Файловые системы перед таблицами разделов, т.к. сигнатура MBR может быть в загрузочном
+// секторе файловой системы.
+var probes = []probeFunc{
+	probeLUKS,
+	probeLVM2,
+	probeRaid,
+	probeExt,
+	probeXFS,
+	probeBtrfs,
+	probeSwap,
+	probePartitionTable,
+}
+
+// Device or image file for probe
+// Устройство или файл образа для проверки
+type probeDevice struct {
+	Head []byte // Start of device, shorter if device is small. Начало устройства, короче для маленьких устройств
+	Size uint64
+	r    io.ReaderAt
+}
+
+// Read length bytes from offset. Return nil if device is shorter.
+// Читает length байт со смещения offset. Возвращает nil, если устройство короче.
+func (dev *probeDevice) Read(offset, length uint64) []byte {
+	if offset+length <= uint64(len(dev.Head)) {
+		return dev.Head[offset : offset+length]
+	}
+	if offset+length > dev.Size {
+		return nil
+	}
+	buf := make([]byte, length)
+	if _, err := dev.r.ReadAt(buf, int64(offset)); err != nil {
+		return nil
+	}
+	return buf
+}
+
+/*
+Detect signature on device without external tools. Return empty result without error if signature is unknown.
+Определяет сигнатуру на устройстве без внешних утилит. Возвращает пустой результат без ошибки, если сигнатура неизвестна.
+*/
+func probeFS(path string) (res probeResult, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return res, err
+	}
+	defer f.Close()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return res, fmt.Errorf("Can't get size for probe: %v (%v)", path, err)
+	}
+	return probeReader(f, uint64(size))
+}
+
+func probeReader(r io.ReaderAt, size uint64) (res probeResult, err error) {
+	dev := &probeDevice{Head: make([]byte, minUint64(size, probe_HEAD_SIZE)), Size: size, r: r}
+	if _, err = r.ReadAt(dev.Head, 0); err != nil && err != io.EOF {
+		return res, fmt.Errorf("Can't read device for probe: %v", err)
+	}
+	for _, probe := range probes {
+		if res, ok := probe(dev); ok {
+			return res, nil
+		}
+	}
+	return probeResult{}, nil
+}
+
+func probeExt(dev *probeDevice) (res probeResult, ok bool) {
+	buf := dev.Read(ext_SUPERBLOCK_OFFSET, ext_SUPERBLOCK_SIZE)
+	if buf == nil {
+		return res, false
+	}
+	sb, err := parseExtSuperblock(buf)
+	if err != nil {
+		return res, false
+	}
+	// Features, which ext3 supports. Other features mean ext4, as in blkid.
+	// Возможности, которые поддерживает ext3. Другие возможности означают ext4, как в blkid.
+	const ext3IncompatSupported = 0x0002 | 0x0004 | ext_FEATURE_INCOMPAT_META_BG // filetype, recover, meta_bg
+	const ext3ROCompatSupported = 0x0001 | 0x0002 | 0x0004                       // sparse_super, large_file, btree_dir
+	switch {
+	case sb.FeatureIncompat&^ext3IncompatSupported != 0 || sb.FeatureROCompat&^ext3ROCompatSupported != 0:
+		res.Type = "ext4"
+	case sb.HasJournal():
+		res.Type = "ext3"
+	default:
+		res.Type = "ext2"
+	}
+	if sb.UUID != [16]byte{} {
+		res.UUID = sb.UUIDString()
+	}
+	res.Label = sb.Label
+	return res, true
+}
+
+func probeXFS(dev *probeDevice) (res probeResult, ok bool) {
+	buf := dev.Read(0, xfs_SUPERBLOCK_SIZE)
+	if buf == nil {
+		return res, false
+	}
+	sb, err := parseXFSSuperblock(buf)
+	if err != nil {
+		return res, false
+	}
+	return probeResult{Type: "xfs", UUID: sb.UUIDString(), Label: sb.Label}, true
+}
+
+// Primary superblock of btrfs at 64KiB
+// Основной суперблок btrfs на 64КиБ
+func probeBtrfs(dev *probeDevice) (res probeResult, ok bool) {
+	const offset = 64 * 1024
+	buf := dev.Read(offset, 0x12b+256)
+	if buf == nil || string(buf[0x40:0x48]) != "_BHRfS_M" {
+		return res, false
+	}
+	var uuid [16]byte
+	copy(uuid[:], buf[0x20:])
+	return probeResult{Type: "btrfs", UUID: uuidString(uuid), Label: cString(buf[0x12b:])}, true
+}
+
+// Swap signature at end of first page, size of page is unknown
+// Сигнатура swap в конце первой страницы, размер страницы неизвестен
+func probeSwap(dev *probeDevice) (res probeResult, ok bool) {
+	for _, pageSize := range []uint64{4096, 8192, 16384, 65536} {
+		signature := dev.Read(pageSize-10, 10)
+		if signature == nil {
+			return res, false
+		}
+		if string(signature) != "SWAPSPACE2" && string(signature) != "SWAP-SPACE" {
+			continue
+		}
+		res.Type = "swap"
+		// Header of swap v1 after 1024 bytes of boot block: version, last_page, nr_badpages, uuid[16], volume_name[16]
+		// Заголовок swap v1 после 1024 байт загрузочного блока: version, last_page, nr_badpages, uuid[16], volume_name[16]
+		if string(signature) == "SWAPSPACE2" {
+			var uuid [16]byte
+			copy(uuid[:], dev.Head[1024+12:])
+			if uuid != [16]byte{} {
+				res.UUID = uuidString(uuid)
+			}
+			res.Label = cString(dev.Head[1024+28 : 1024+44])
+		}
+		return res, true
+	}
+	return res, false
+}
+
+// Label of LVM2 PV in one of first 4 sectors
+// Метка LVM2 PV в одном из первых 4 секторов
+func probeLVM2(dev *probeDevice) (res probeResult, ok bool) {
+	for sector := uint64(0); sector < 4; sector++ {
+		label := dev.Read(sector*512, 512)
+		if label == nil {
+			return res, false
+		}
+		if string(label[0:8]) != "LABELONE" || string(label[24:32]) != "LVM2 001" {
+			continue
+		}
+		// pv_header with uuid placed at offset_xl from start of label
+		// pv_header с uuid расположен по смещению offset_xl от начала метки
+		offset := uint64(binary.LittleEndian.Uint32(label[20:]))
+		if offset+32 > uint64(len(label)) {
+			return res, false
+		}
+		uuid := string(label[offset : offset+32])
+		// LVM prints uuid by groups: 6-4-4-4-4-4-6
+		// LVM печатает uuid группами: 6-4-4-4-4-4-6
+		parts := []string{uuid[0:6], uuid[6:10], uuid[10:14], uuid[14:18], uuid[18:22], uuid[22:26], uuid[26:32]}
+		return probeResult{Type: "LVM2_member", UUID: strings.Join(parts, "-")}, true
+	}
+	return res, false
+}
+
+// LUKS1 and LUKS2 headers at start of device
+// Заголовки LUKS1 и LUKS2 в начале устройства
+func probeLUKS(dev *probeDevice) (res probeResult, ok bool) {
+	buf := dev.Read(0, 208)
+	if buf == nil || !bytes.Equal(buf[0:6], []byte("LUKS\xba\xbe")) {
+		return res, false
+	}
+	res = probeResult{Type: "crypto_LUKS", UUID: cString(buf[168:208])}
+	if binary.BigEndian.Uint16(buf[6:]) == 2 {
+		res.Label = cString(buf[24:72])
+	}
+	return res, true
+}
+
+/*
+Offsets of raid 1.x superblock: 1.1 and 1.2 at start of device, 1.0 at 8KiB before end of device (4KiB aligned).
+Device less then 8KiB hasn't place for 1.0.
+Смещения суперблока raid 1.x: 1.1 и 1.2 в начале устройства, 1.0 за 8КиБ до конца устройства (с выравниванием 4КиБ).
+У устройства меньше 8КиБ нет места для 1.0.
+*/
+func raidV1Offsets(size uint64) []uint64 {
+	offsets := []uint64{0, 4096}
+	if size >= 16*512 {
+		offsets = append(offsets, (size/512-16)&^7*512)
+	}
+	return offsets
+}
+
+// Superblock of md array: 1.1 at start, 1.2 at 4KiB, 1.0 and 0.90 at end of device. Offsets from end of device are
+// checked only on devices, which have place for the superblock, else the offset would wrap around.
+// Суперблок md массива: 1.1 в начале, 1.2 на 4КиБ, 1.0 и 0.90 в конце устройства. Смещения от конца устройства
+// проверяются только на устройствах, где есть место для суперблока, иначе смещение стало бы отрицательным.
+func probeRaid(dev *probeDevice) (res probeResult, ok bool) {
+	const magic = 0xa92b4efc
+	le := binary.LittleEndian
+	for _, offset := range raidV1Offsets(dev.Size) {
+		buf := dev.Read(offset, 64)
+		if buf == nil || le.Uint32(buf) != magic || le.Uint32(buf[4:]) != 1 {
+			continue
+		}
+		var uuid [16]byte
+		copy(uuid[:], buf[16:32])
+		return probeResult{Type: "linux_raid_member", UUID: uuidString(uuid), Label: cString(buf[32:64])}, true
+	}
+
+	// 0.90: last 64KiB aligned block of device. Device less then 128KiB hasn't the block.
+	// 0.90: последний выровненный по 64КиБ блок устройства. У устройства меньше 128КиБ нет такого блока.
+	const block = 64 * 1024
+	if dev.Size < 2*block {
+		return res, false
+	}
+	buf := dev.Read(dev.Size&^(block-1)-block, 64)
+	if buf == nil || le.Uint32(buf) != magic || le.Uint32(buf[4:]) != 0 {
+		return res, false
+	}
+	var uuid [16]byte
+	copy(uuid[0:4], buf[5*4:])
+	copy(uuid[4:16], buf[13*4:])
+	return probeResult{Type: "linux_raid_member", UUID: uuidString(uuid)}, true
+}
+
+// GPT header at second sector (512 or 4096 bytes sectors), MBR signature at end of first sector
+// Заголовок GPT во втором секторе (секторы 512 или 4096 байт), сигнатура MBR в конце первого сектора
+func probePartitionTable(dev *probeDevice) (res probeResult, ok bool) {
+	for _, sectorSize := range []uint64{512, 4096} {
+		header := dev.Read(sectorSize, 92)
+		if header == nil || string(header[0:8]) != "EFI PART" {
+			continue
+		}
+		var guid [16]byte
+		copy(guid[:], header[56:72])
+		return probeResult{PTType: "gpt", UUID: gptGUIDString(guid)}, true
+	}
+	mbrSector := dev.Read(0, 512)
+	if mbrSector != nil && mbrSector[510] == 0x55 && mbrSector[511] == 0xAA {
+		return probeResult{PTType: "dos"}, true
+	}
+	return res, false
+}
+
+// GUID of GPT in text form, first three groups stored little endian
+// GUID GPT в текстовом виде, первые три группы хранятся little endian
+func gptGUIDString(g [16]byte) string {
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x", binary.LittleEndian.Uint32(g[0:4]), binary.LittleEndian.Uint16(g[4:6]),
+		binary.LittleEndian.Uint16(g[6:8]), g[8:10], g[10:16])
+}
@@ -0,0 +1,98 @@
+package fsextender
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Description of linux software RAID (md) array
+// Описание программного RAID-массива linux (md)
+type raidInfo struct {
+	Level         string // raid1, raid5, ... from md/level. Уровень RAID из md/level
+	Disks         uint64 // Count of devices in the array. Количество устройств в массиве
+	Layout        uint64 // Layout of data, need for raid10. Расположение данных, нужно для raid10
+	ComponentSize uint64 // Bytes, used on every member device. Байт, используемых на каждом из устройств массива
+}
+
+// Read description of md array from sysfs by kernel name
+// Читает описание md массива из sysfs по имени в ядре
+func readRaidInfo(name string) (raid raidInfo, err error) {
+	mdDir := filepath.Join(sysfsRoot, "class", "block", name, "md")
+	if raid.Level, err = readSysfsString(filepath.Join(mdDir, "level")); err != nil {
+		return raid, err
+	}
+	if raid.Disks, err = readSysfsUint(filepath.Join(mdDir, "raid_disks")); err != nil {
+		return raid, err
+	}
+	if raid.Layout, err = readSysfsUint(filepath.Join(mdDir, "layout")); err != nil {
+		// Levels without layout (raid1) may have not the attribute
+		// Для уровней без разных расположений данных (raid1) атрибута может не быть
+		raid.Layout = 0
+	}
+	componentSizeKiB, err := readSysfsUint(filepath.Join(mdDir, "component_size"))
+	if err != nil {
+		return raid, err
+	}
+	raid.ComponentSize = componentSizeKiB * 1024
+	return raid, nil
+}
+
+// Usable size of the array if every member device use componentSize bytes.
+// Return error for levels, which can't grow by mdadm --grow --size (raid0, linear).
+// Полезный объем массива, если на каждом устройстве используется componentSize байт.
+// Возвращает ошибку для уровней, которые не могут расти через mdadm --grow --size (raid0, linear).
+func (raid raidInfo) ArraySize(componentSize uint64) (uint64, error) {
+	switch raid.Level {
+	case "raid1":
+		return componentSize, nil
+	case "raid4", "raid5":
+		if raid.Disks < 2 {
+			return 0, fmt.Errorf("Bad count of disks for %v: %v", raid.Level, raid.Disks)
+		}
+		return componentSize * (raid.Disks - 1), nil
+	case "raid6":
+		if raid.Disks < 3 {
+			return 0, fmt.Errorf("Bad count of disks for %v: %v", raid.Level, raid.Disks)
+		}
+		return componentSize * (raid.Disks - 2), nil
+	case "raid10":
+		// Layout: near copies in low byte, far copies in next byte
+		// Layout: количество ближних копий в младшем байте, дальних копий - в следующем байте
+		copies := (raid.Layout & 0xFF) * ((raid.Layout >> 8) & 0xFF)
+		if copies == 0 {
+			return 0, fmt.Errorf("Bad layout of raid10: %v", raid.Layout)
+		}
+		return componentSize * raid.Disks / copies, nil
+	default:
+		return 0, fmt.Errorf("Can't grow component size of RAID level: %v", raid.Level)
+	}
+}
+
+// Min size of every member device, which gives array of arraySize bytes or more. Rounded up to KiB - unit of mdadm.
+// Минимальный объем на каждом устройстве, дающий массив из arraySize байт или больше. Округляется вверх до КиБ - единицы
+// mdadm.
+func (raid raidInfo) ComponentSizeFor(arraySize uint64) (uint64, error) {
+	// Size of array grows with size of component, search the component by bisection
+	// Объем массива растёт с объемом участника, ищем объем участника делением пополам
+	low, high := uint64(0), uint64(1024)
+	for {
+		size, err := raid.ArraySize(high)
+		if err != nil {
+			return 0, err
+		}
+		if size >= arraySize {
+			break
+		}
+		low = high
+		high *= 2
+	}
+	for high-low > 1024 {
+		middle := alignUp(low+(high-low)/2, 1024)
+		if size, _ := raid.ArraySize(middle); size >= arraySize {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+	return high, nil
+}
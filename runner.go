@@ -0,0 +1,157 @@
+package fsextender
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+)
+
+/*
+Runner of external commands. All commands executed through runner, so tests can replace it by replay of recorded
+commands.
+Исполнитель внешних команд. Все команды выполняются через runner, поэтому тесты могут заменить его воспроизведением
+записанных команд.
+*/
+type commandRunner interface {
+	Run(name string, args ...string) (stdout, stderr string, err error)
+}
+
+var runner commandRunner = execRunner{}
+
+// Run commands in system
+// Выполняет команды в системе
+type execRunner struct{}
+
+func (execRunner) Run(name string, args ...string) (stdout, stderr string, err error) {
+	bufStd := &bytes.Buffer{}
+	bufErr := &bytes.Buffer{}
+	command := exec.Command(name, args...)
+	command.Stdout = bufStd
+	command.Stderr = bufErr
+	err = command.Run()
+	return bufStd.String(), bufErr.String(), err
+}
+
+// Executed command with result. Error is text of error, empty string if command finished without error.
+// Выполненная команда с результатом. Error - текст ошибки, пустая строка если команда завершилась без ошибки.
+type commandRecord struct {
+	Name   string
+	Args   []string
+	Stdout string
+	Stderr string
+	Error  string
+}
+
+func (rec commandRecord) match(name string, args []string) bool {
+	if rec.Name != name || len(rec.Args) != len(args) {
+		return false
+	}
+	for i := range args {
+		if rec.Args[i] != args[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (rec commandRecord) result() (stdout, stderr string, err error) {
+	if rec.Error != "" {
+		err = errors.New(rec.Error)
+	}
+	return rec.Stdout, rec.Stderr, err
+}
+
+/*
+Run commands by Runner and write every command with result to fixture file Path. The file rewrites after every command,
+so it is complete even if program exits in the middle.
+Выполняет команды через Runner и записывает каждую команду с результатом в файл Path. Файл перезаписывается после каждой
+команды, поэтому он полный даже если программа завершится посередине.
+*/
+type recordRunner struct {
+	Runner  commandRunner
+	Path    string
+	Records []commandRecord
+}
+
+func (r *recordRunner) Run(name string, args ...string) (stdout, stderr string, err error) {
+	stdout, stderr, err = r.Runner.Run(name, args...)
+	rec := commandRecord{Name: name, Args: append([]string{}, args...), Stdout: stdout, Stderr: stderr}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	r.Records = append(r.Records, rec)
+	if saveErr := saveCommandRecords(r.Path, r.Records); saveErr != nil {
+		return stdout, stderr, fmt.Errorf("Can't record command '%v': %v", name, saveErr)
+	}
+	return stdout, stderr, err
+}
+
+func saveCommandRecords(path string, records []commandRecord) error {
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0600)
+}
+
+func loadCommandRecords(path string) ([]commandRecord, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []commandRecord
+	if err = json.Unmarshal(content, &records); err != nil {
+		return nil, fmt.Errorf("Can't parse command records %v: %v", path, err)
+	}
+	return records, nil
+}
+
+/*
+Replay recorded commands instead of execute them. Same command return recorded results in order of record, after last
+result it repeats last result (for example size of LV before and after lvresize). Commands without record return error
+and saved in Missed.
+Воспроизводит записанные команды вместо их выполнения. Одна и та же команда возвращает записанные результаты по порядку
+записи, после последнего результата повторяется последний (например размер LV до и после lvresize). Команды без записи
+возвращают ошибку и сохраняются в Missed.
+*/
+type replayRunner struct {
+	Records []commandRecord
+	Missed  []string
+
+	used []bool
+}
+
+func newReplayRunner(path string) (*replayRunner, error) {
+	records, err := loadCommandRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	return &replayRunner{Records: records}, nil
+}
+
+func (r *replayRunner) Run(name string, args ...string) (stdout, stderr string, err error) {
+	if len(r.used) != len(r.Records) {
+		r.used = make([]bool, len(r.Records))
+	}
+	last := -1
+	for i, rec := range r.Records {
+		if !rec.match(name, args) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return rec.result()
+		}
+		last = i
+	}
+	if last != -1 {
+		return r.Records[last].result()
+	}
+	command := strings.TrimSpace(name + " " + strings.Join(args, " "))
+	r.Missed = append(r.Missed, command)
+	return "", "", fmt.Errorf("Command hasn't record: %v", command)
+}
@@ -1,15 +1,14 @@
 package fsextender
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"github.com/rekby/gpt"
 	"github.com/rekby/mbr"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -33,6 +32,34 @@ const min_SIZE_NEW_PARTITION = 100 * 1024 * 1024
 // Количество блоков LVM PV, резервируемых под метаданные (при расчетах).
 const lvm_PV_METADATA_RESERVED = 2
 
+// Max count of logical partitions in msdos extended partition. Used for loop detection in EBR chain.
+// Максимальное количество логических разделов в расширенном разделе msdos. Используется для защиты от зацикливания
+// цепочки EBR.
+const max_MBR_LOGICAL_PARTITIONS = 128
+
+// Alignment of new logical partitions from start of EBR (in bytes).
+// Выравнивание новых логических разделов относительно начала EBR (в байтах).
+const mbr_LOGICAL_ALIGN = 1024 * 1024
+
+// Alignment of moved swap partition (in bytes).
+// Выравнивание перемещаемого раздела подкачки (в байтах).
+const swap_ALIGN = 1024 * 1024
+
+// Types of msdos extended partition
+// Типы расширенных разделов msdos
+const (
+	mbr_PART_EXTENDED       = mbr.PartitionType(0x05)
+	mbr_PART_EXTENDED_LBA   = mbr.PartitionType(0x0F)
+	mbr_PART_EXTENDED_LINUX = mbr.PartitionType(0x85)
+)
+
+// Types of new partition for linux filesystem (btrfs device)
+// Типы нового раздела для файловой системы linux (устройства btrfs)
+var (
+	mbr_PART_LINUX    = mbr.PartitionType(0x83)
+	gpt_GUID_LINUX_FS = gpt.PartType([16]byte{0xaf, 0x3d, 0xc6, 0x0f, 0x83, 0x84, 0x72, 0x47, 0x8e, 0x79, 0x3d, 0x69, 0xd8, 0x47, 0x7d, 0xe4}) // 0FC63DAF-8483-4772-8E79-3D69D8477DE4
+)
+
 const (
 	type_UNKNOWN storageItemType = iota
 	type_FS
@@ -51,45 +78,33 @@ const (
 	type_PARTITION
 	type_PARTITION_NEW
 
-	type_SKIP
-	type_LAST // Doesn't use in work - for tests only.
-)
+	// Extended partition in msdos table - container of logical partitions
+	// Расширенный раздел в таблице msdos - контейнер для логических разделов
+	type_PARTITION_EXTENDED
 
-var (
-	dynamicMajorIdVirtblk = -1
-)
+	// Move swap partition to end of disk. It free space for extend previous partition.
+	// Перемещение раздела подкачки в конец диска. Освобождает место для расширения предыдущего раздела.
+	type_SWAP_MOVE
 
-func init() {
-	devicesBytes, err := ioutil.ReadFile("/proc/devices")
-	if err == nil {
-		dynamicMajorIdVirtblk = parseVirtBlkMajorID(devicesBytes)
-	} else {
-		log.Printf("Can't read /proc/devices: %v\n", err)
-	}
-}
+	// Create swap on moved swap partition
+	// Создание swap на перемещённом разделе подкачки
+	type_SWAP_CREATE
 
-func parseVirtBlkMajorID(devicesB []byte) int {
-	devices := string(devicesB)
-	lines := strings.Split(devices, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		parts := strings.Split(line, " ")
-		if len(parts) != 2 {
-			continue
-		}
-		name := parts[1]
-		if name != "virtblk" {
-			continue
-		}
-		id, err := strconv.Atoi(parts[0])
-		if err != nil {
-			log.Printf("Can't parse virtblk major id from string: %q\n", line)
-			return -1
-		}
-		return id
-	}
-	return -1
-}
+	// Encrypted device (dm-crypt, LUKS)
+	// Шифрованное устройство (dm-crypt, LUKS)
+	type_CRYPT
+
+	// Linux software RAID (md)
+	// Программный RAID linux (md)
+	type_RAID
+
+	// Add new device to btrfs filesystem
+	// Добавление нового устройства в файловую систему btrfs
+	type_BTRFS_DEVICE_NEW
+
+	type_SKIP
+	type_LAST // Doesn't use in work - for tests only.
+)
 
 type storageItem struct {
 	Type      storageItemType // Storage type. Тип устройства
@@ -103,6 +118,11 @@ type storageItem struct {
 	FSType        string    // Type of file system (for type type_FS) тип файловой системы (для типа type_FS)
 	Partition     partition // For types type_PARTITION and type_PARTITION_NEW. Описание раздела диска - для типов (type_PARTITION, type_PARTITION_NEW)
 	LVMExtentSize uint64    // Extent size for type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW. Размер экстента для типа type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW
+	UUID          string    // UUID of swap for type_SWAP_MOVE, type_SWAP_CREATE. UUID раздела подкачки для type_SWAP_MOVE, type_SWAP_CREATE
+	CryptOffset   uint64    // Offset of data in backing device for type_CRYPT. Смещение данных на нижележащем устройстве для type_CRYPT
+	Raid          raidInfo  // For type_RAID. Описание RAID-массива для type_RAID
+	Limit         uint64    // Max size after extend, 0 - without limit. Максимальный размер после расширения, 0 - без ограничения
+	LVMReserve    uint64    // Free space of type_LVM_GROUP, which doesn't use for extend LV. Свободное место type_LVM_GROUP, не используемое для расширения LV
 
 	SkipReason string
 	OldType    storageItemType // Type of item before skip
@@ -114,13 +134,26 @@ func (this storageItem) String() string {
 	switch this.Type {
 	case type_FS:
 		base += ", FS: " + this.FSType
-	case type_PARTITION, type_PARTITION_NEW:
+	case type_PARTITION, type_PARTITION_NEW, type_PARTITION_EXTENDED:
 		base += ", PartNum=" + strconv.FormatUint(uint64(this.Partition.Number), 10)
 	case type_LVM_GROUP, type_LVM_PV, type_LVM_PV_ADD, type_LVM_PV_NEW:
 		base += ", ExtentSize: " + formatSize(this.LVMExtentSize)
+		if this.LVMReserve != 0 {
+			base += ", Reserve: " + formatSize(this.LVMReserve)
+		}
+	case type_CRYPT:
+		base += ", Offset: " + formatSize(this.CryptOffset)
+	case type_RAID:
+		base += ", Level: " + this.Raid.Level + ", Disks: " + strconv.FormatUint(this.Raid.Disks, 10) +
+			", Component: " + formatSize(this.Raid.ComponentSize)
+	case type_SWAP_MOVE, type_SWAP_CREATE:
+		base += ", PartNum=" + strconv.FormatUint(uint64(this.Partition.Number), 10) + ", UUID: " + this.UUID
 	case type_SKIP:
 		base += ", Reason: " + this.SkipReason
 	}
+	if this.Limit != 0 {
+		base += ", Limit: " + formatSize(this.Limit)
+	}
 	return base + "]"
 }
 
@@ -133,6 +166,14 @@ type diskInfo struct {
 	SectorSizeLogical uint64 // Logical size of sector - for operation with partition table (in bytes). Логический размер сектора диска, в байтах
 	Partitions        []partition
 	MaxPartitionCount uint32
+
+	// Number of extended partition in msdos table. 0 - if disk hasn't extended partition.
+	// Номер расширенного раздела в таблице msdos. 0 - если расширенного раздела нет.
+	ExtendedNumber uint32
+
+	// Logical partitions and free space inside extended partition (msdos only).
+	// Логические разделы и свободное место внутри расширенного раздела (только для msdos).
+	LogicalPartitions []partition
 }
 
 type partition struct {
@@ -141,6 +182,9 @@ type partition struct {
 	Number    uint32 // Partition numbers start start from 1. Value 0 mean free space
 	FirstByte uint64
 	LastByte  uint64
+
+	Logical bool   // Logical partition inside msdos extended partition. Логический раздел внутри расширенного раздела msdos
+	EBRByte uint64 // Position of EBR, which describe the logical partition. Положение EBR, описывающего логический раздел
 }
 type partitionSortByFirstByte []partition
 
@@ -163,6 +207,15 @@ func (p partition) Size() uint64 {
 func (p partition) IsFreeSpace() bool {
 	return p.Number == 0
 }
+
+// First byte of disk space, which occupied by partition. For logical partitions it include EBR before partition.
+// Первый байт дискового пространства, занятого разделом. Для логических разделов включает EBR перед разделом.
+func (p partition) occupiedFirstByte() uint64 {
+	if p.Logical {
+		return p.EBRByte
+	}
+	return p.FirstByte
+}
 func (p partition) makePath() string {
 	// Drive path ends with number, for example /dev/loop0
 	if len(p.Disk.Path) > 0 {
@@ -182,25 +235,6 @@ type lvmPV struct {
 
 var majorMinorDeviceTypeCache = make(map[[2]int]storageItem)
 
-func blkid(path string) string {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("blkid", path)
-	cmd.Stdout = buf
-	cmd.Run()
-	out := buf.Bytes()
-	start := bytes.Index(out, []byte(`TYPE="`))
-	if start == -1 {
-		return ""
-	}
-	typeBytes := out[start+len(`TYPE="`):]
-	end := bytes.Index(typeBytes, []byte(`"`))
-	if end == -1 {
-		return ""
-	}
-
-	return string(typeBytes[:end])
-}
-
 var diskNewPartitionNumLastGeneratedNum = make(map[[2]int]uint32)
 
 func diskNewPartitionNum(disk diskInfo) uint32 {
@@ -225,62 +259,47 @@ func extendScanWays(startPoint string) (storage []storageItem, err error) {
 	startPoint = filepath.Clean(startPoint)
 	startPoint, err = filepath.Abs(startPoint)
 	if err != nil {
-		log.Println("Can't abs of startpoint: ", startPoint)
-		return
+		return nil, usageError{fmt.Errorf("Can't abs of startpoint %v: %v", startPoint, err)}
 	}
 	startPoint, err = readLink(startPoint)
 	if err != nil {
-		log.Println("Can't readlink of startpoint: ", startPoint)
-		return
+		return nil, usageError{fmt.Errorf("Can't readlink of startpoint %v: %v", startPoint, err)}
 	}
 	scanLVM()
 
-	// Check if startPoint is mount point of file system. If yes - find mounted device. Take last mount line.
-	// проверяем является ли startPoint точкой монтирования. Если да - находим смонтированное устройство.
-	// Если подходящих строк монтирования несколько - берём последнюю строку.
-	mountsBytes, err := ioutil.ReadFile("/proc/mounts")
+	// Check if startPoint is mount point of file system. If yes - find mounted device by major:minor numbers.
+	// If the path over-mounted - take top mount.
+	// проверяем является ли startPoint точкой монтирования. Если да - находим смонтированное устройство по major:minor.
+	// Если в путь смонтировано несколько файловых систем - берём верхнюю.
+	mounts, err := readMountInfo()
 	if err != nil {
-		return
+		return nil, scanError{fmt.Errorf("Can't read mountinfo: %v", err)}
 	}
-	mountFrom := ""
-	for _, lineBytes := range bytes.Split(mountsBytes, []byte("\n")) {
-		parts := bytes.Split(lineBytes, []byte(" "))
-		if len(parts) < 3 {
-			// empty line
-			continue
-		}
-		from := parts[0]
-		to := parts[1]
-		if string(to) == startPoint {
-			mountFrom = string(from)
-		}
-	}
-	if mountFrom != "" {
-		startPoint = mountFrom
+	if mount, ok := findMountByPath(mounts, startPoint); ok {
+		startPoint = mountDevicePath(mount)
 	}
 	startPoint, err = readLink(startPoint)
 	if err != nil {
-		log.Println("Can't read symlink for mountpoint: ", startPoint, err)
-		return
+		return nil, scanError{fmt.Errorf("Can't read symlink for mountpoint %v: %v", startPoint, err)}
 	}
-	storage = make([]storageItem, 0)
-
-	toScan := []storageItem{storageItem{Path: startPoint, Child: -1}}
-toScanLoop:
-	for len(toScan) > 0 {
-		if len(storage) > max_STORAGE_DEEP {
-			return storage, errors.New("Struct is cicle or very large")
+	scan := layerScan{Storage: make([]storageItem, 0), ToScan: []storageItem{{Path: startPoint, Child: -1}}}
+	for len(scan.ToScan) > 0 {
+		if len(scan.Storage) > max_STORAGE_DEEP {
+			return scan.Storage, scanError{errors.New("Struct is cicle or very large")}
 		}
 		// pop item
-		item := toScan[len(toScan)-1]
-		toScan = toScan[:len(toScan)-1]
+		item := scan.ToScan[len(scan.ToScan)-1]
+		scan.ToScan = scan.ToScan[:len(scan.ToScan)-1]
 
-		switch item.Type {
-		case type_UNKNOWN:
-			blk := blkid(item.Path)
+		if item.Type == type_UNKNOWN {
+			probe, err := probeFS(item.Path)
+			if err != nil {
+				log.Printf("Can't probe signature of device: %v\n", err)
+			}
+			blk := probe.Type
 			major, minor := getMajorMinor(item.Path)
 			switch {
-			case blk == "ext2", blk == "ext3", blk == "ext4", blk == "xfs":
+			case fsLayers[blk] != nil:
 				item.Type = type_FS
 				item.FSType = blk
 			case getTypeByMajorMinor(major, minor) != type_UNKNOWN:
@@ -289,173 +308,57 @@ toScanLoop:
 				// Skip unknown devices
 				// не получилось понять что за устройство - пропускаем
 				log.Printf("Can't detect device type. Path: '%v' Blk: '%v', major: %v, minor: %v", item.Path, blk, major, minor)
-				continue toScanLoop
+				continue
 			}
 			// Scan once more with right type of device
 			// тип устройства определился - будем сканировать подробнее на следующем проходе
-			toScan = append(toScan, item)
-
-		case type_FS:
-			switch item.FSType {
-			case "ext2", "ext3", "ext4":
-				item.Size, err = fsGetSizeExt(item.Path)
-				if err != nil {
-					log.Printf("Can't get size of filesystem: %v (%v). Skip it.\n", item.Path, err)
-					continue toScanLoop
-				}
-			case "xfs":
-				item.Size, err = fsGetSizeXFS(item.Path)
-				if err != nil {
-					log.Printf("Can't get size of filesystem: %v (%v). Skip it.\n", item.Path, err)
-					continue toScanLoop
-				}
-			default:
-				log.Printf("I don't khow method to detect size of filesystem %v (%v). Skip it.", item.Path, item.FSType)
-				continue toScanLoop
-			}
-
-			storage = append(storage, item)
-			major, minor := getMajorMinor(item.Path)
-			underLevelType := getTypeByMajorMinor(major, minor)
-			if underLevelType != type_UNKNOWN {
-				newItem := storageItem{
-					Path:  item.Path,
-					Type:  underLevelType,
-					Child: len(storage) - 1,
-				}
-				toScan = append(toScan, newItem)
-			}
-
-		case type_PARTITION:
-			diskPath, partNumber, err := extractPartNumber(item.Path)
-			if err != nil {
-				log.Println(err.Error())
-				continue toScanLoop
-			}
-			disk, err := readDiskInfo(diskPath)
-			if err != nil {
-				log.Println("Error while scan partition. Skip it: ", item.Path, diskPath, err)
-				continue toScanLoop
-			}
-			for i, partition := range disk.Partitions {
-				if partition.Number != partNumber {
-					continue
-				}
-				item.Size = partition.Size()
-				item.Partition = partition
-
-				// Check if can extend partition.
-				// Если можем расшириться за счет свободного места между разделами или до конца диска
-				if i+1 < len(disk.Partitions) && disk.Partitions[i+1].IsFreeSpace() {
-					freeSpace := disk.Partitions[i+1]
-					item.FreeSpace = uint64(freeSpace.LastByte - partition.LastByte)
-				}
-			}
-			// If partition has not fund, example: extended partition in mbr
-			// Если раздел не найден, например расширенный раздел mbr
-			if item.Partition.Number == 0 {
-				item.Type = type_SKIP
-				if disk.PartTable == "msdos" && partNumber > 4 {
-					item.SkipReason = "Extended partition on mbr"
-				} else {
-					item.SkipReason = "Coud not found partition in partition table"
-				}
-			}
-			storage = append(storage, item)
-
-			// LVM_PV free space detection
-			if item.Child != -1 && storage[item.Child].Type == type_LVM_PV {
-				child := &storage[item.Child]
-				newSize := lvmPVCalcSize(item.Size, child.LVMExtentSize)
-				if newSize > child.Size {
-					child.FreeSpace = newSize - child.Size
-				}
-			}
-		case type_DISK:
-			storage = append(storage, item)
+			scan.ToScan = append(scan.ToScan, item)
 			continue
-		case type_LVM_LV:
-			// Normalize path to LVM LV
-			// Если был передан полный путь к LVM - заменяем его описанием из кеша, заполненного при сканировании LVM
-			if major, minor := getMajorMinor(item.Path); major != 0 {
-				item.Path = majorMinorDeviceTypeCache[[2]int{major, minor}].Path
-			}
-			item.Size = lvmLVGetSize(item.Path)
-			storage = append(storage, item)
+		}
 
-			lvm_group := storageItem{
-				Type:  type_LVM_GROUP,
-				Path:  item.Path[:strings.Index(item.Path, "/")],
-				Child: len(storage) - 1,
-			}
-			toScan = append(toScan, lvm_group)
-		case type_LVM_PV, type_LVM_PV_ADD:
-			item.Size = lvmPVGetSize(item.Path)
-			storage = append(storage, item)
+		l := layerOf(item)
+		if l == nil {
+			log.Printf("I don't khow method to scan %v %v (%v). Skip it.\n", item.Type, item.Path, item.FSType)
+			continue
+		}
+		if err = l.Scan(&scan, item); err != nil {
+			log.Printf("%v. Skip it.\n", err)
+		}
+	}
 
-			major, minor := getMajorMinor(item.Path)
-			parent := storageItem{}
-			parent.Path = item.Path
-			parent.Child = len(storage) - 1
-			parent.Type = getTypeByMajorMinor(major, minor)
-			if parent.Type != type_UNKNOWN {
-				toScan = append(toScan, parent)
-			}
-		case type_LVM_GROUP:
-			item.Size, item.FreeSpace, item.LVMExtentSize = lvmVGGetSize(item.Path)
-			storage = append(storage, item)
-			lvmGroupIndex := len(storage) - 1
-
-			// Find my and free pvs
-			for _, pv := range getLvmPV() {
-				if pv.VolumeGroup == "" {
-					// Can use free LVM PV
-					// Незанятые PV, можно использовать
-					parent := storageItem{Path: pv.Path, Type: type_LVM_PV_ADD, Child: len(storage) - 1, LVMExtentSize: item.LVMExtentSize}
-
-					// Calc usable PV size
-					// для свободных pv  система выдает размер равный размеру раздела, так что испольузем расчетный размер
-					parent.Size = lvmPVCalcSize(pv.Size, item.LVMExtentSize)
-					parent.FreeSpace = parent.Size
-					toScan = append(toScan, parent)
-				} else if pv.VolumeGroup == item.Path {
-					// LVM PV in the LV group
-					// PV, входящие в эту группу
-					parent := storageItem{Path: pv.Path, Size: pv.Size, Type: type_LVM_PV, Child: len(storage) - 1, LVMExtentSize: item.LVMExtentSize}
-					toScan = append(toScan, parent)
-				} else {
-					// nothing
-				}
-			}
+	// Fix free space of items, which can be extended to size of underliing layer. We can't detect it while scan - on
+	// the step the program doesn't know partition/LVM size.
+	// Поправить свободное место элементов, которые могут быть расширены до размера нижележащего слоя - оно не может
+	// быть определено во время сканирования, т.к. на этом шаге программа еще не знает размера нижележащего раздела/LVM.
+	scanFreeSpace(scan.Storage)
+	return scan.Storage, nil
+}
 
-			// Find free space for create new partition
-			for _, part := range getNewPartitions() {
-				pvCreate := storageItem{Child: lvmGroupIndex, Path: part.Path, Type: type_LVM_PV_NEW, LVMExtentSize: item.LVMExtentSize}
-				storage = append(storage, pvCreate)
-				partCreate := storageItem{Child: len(storage) - 1, Path: part.Path, Type: type_PARTITION_NEW, FreeSpace: part.Size(),
-					Partition: part}
-				storage = append(storage, partCreate)
-			}
-		}
+// Return offset of data on underliing device (in bytes)
+// Возвращает смещение данных на нижележащем устройстве (в байтах)
+func cryptGetOffset(path string) (offset uint64, err error) {
+	res, errString, err := cmd("cryptsetup", "status", filepath.Base(path))
+	if err != nil {
+		return 0, fmt.Errorf("%v (%v)", err, strings.TrimSpace(errString))
 	}
+	return parseCryptStatusOffset(res)
+}
 
-	// Fix free space for extend filesystem. We can't detect it while scan - on the step the program doesn't know partition/LVM size
-	// Поправить свободной место файловой системы - оно не может быть определено просто во время, т.к. на этом шаге программа еще не знает размера нижележащего раздела/LVM
-	for _, item := range storage {
-		if item.Child != -1 && storage[item.Child].Type == type_FS {
-			fs := &storage[item.Child]
-			switch {
-			case item.Size > fs.Size:
-				fs.FreeSpace += item.Size - fs.Size
-			case item.Size < fs.Size:
-				log.Printf("WARNING: Filesystem size (%v) more then underliing layer (%v, %v)\n", fs.Path, item.Type, item.Path)
-			case item.Size == fs.Size:
-				// do nothing
-			}
+// Parse offset from cryptsetup status output (line "offset:  32768 sectors"). Offset always in 512-byte sectors.
+// Разбирает смещение из вывода cryptsetup status. Смещение всегда в 512-байтных секторах.
+func parseCryptStatusOffset(status string) (offset uint64, err error) {
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "offset:" {
+			continue
 		}
+		sectors, err := parseUint(fields[1])
+		if err != nil {
+			return 0, err
+		}
+		return sectors * 512, nil
 	}
-
-	return storage, err
+	return 0, errors.New("Can't find offset in cryptsetup status")
 }
 
 func extractPartNumber(path string) (diskPath string, partNumber uint32, err error) {
@@ -479,76 +382,26 @@ func extractPartNumber(path string) (diskPath string, partNumber uint32, err err
 	return diskPath, uint32(partNumber64), nil
 }
 
+// Size of ext2/3/4 filesystem from its superblock
+// Размер файловой системы ext2/3/4 из её суперблока
 func fsGetSizeExt(path string) (size uint64, err error) {
-	var blockCount, blockSize uint64
-	var blockCountPresent, blockSizePresent bool
-	res := cmdTrimLines("tune2fs", "-l", path)
-	for _, line := range res {
-		if strings.HasPrefix(line, "Block size:") {
-			blockSizePresent = true
-			blockSizeString := strings.TrimSpace(line[len("Block size:"):])
-			blockSize, err = parseUint(blockSizeString)
-			if err != nil {
-				return
-			}
-		}
-		if strings.HasPrefix(line, "Block count:") {
-			blockCountString := strings.TrimSpace(line[len("Block count:"):])
-			blockCountPresent = true
-			blockCount, err = parseUint(blockCountString)
-			if err != nil {
-				return
-			}
-		}
-	}
-	if !blockCountPresent || !blockSizePresent {
-		return 0, fmt.Errorf("Can't get filesistem size: %v", path)
+	sb, err := readExtSuperblock(path)
+	if err != nil {
+		return 0, err
 	}
-	size = blockCount * blockSize
-	return
+	return sb.Size(), nil
 }
 
 /*
+Size of xfs from its superblock, without mount.
 path - пусть к блочному устройству, на котором расположена xfs
 */
 func fsGetSizeXFS(path string) (size uint64, err error) {
-	var tmpMountPath string
-	if _, err = getMountPoint(path); err != nil {
-		tmpMountPath, err = ioutil.TempDir("", "")
-		if _, _, err = cmd("mount", "-t", "xfs", path, tmpMountPath); err != nil {
-			return 0, fmt.Errorf("(fsGetSizeXFS) Can't xfs mount: %v", err)
-		}
-	}
-
-	res := cmdTrimLines("xfs_info", path)
-	if tmpMountPath != "" {
-		cmd("umount", tmpMountPath)
-		os.Remove(tmpMountPath)
-	}
-
-	for _, line := range res {
-		if !strings.HasPrefix(line, "data ") {
-			continue
-		}
-		// data     =                       bsize=4096   blocks=26240000, imaxpct=25
-		// field 0  field 1                 field 2      field 3          field 4
-		fields := strings.Fields(line)
-		if !strings.HasPrefix(fields[2], "bsize=") || !strings.HasPrefix(fields[3], "blocks=") {
-			continue
-		}
-		blockSize, err := parseUint(fields[2][len("bsize="):])
-		if err != nil {
-			return 0, err
-		}
-		blockCount, err := parseUint(fields[3][len("blocks=") : len(fields[3])-1]) // cut "blocks=" from start and "," from end.
-		if err != nil {
-			return 0, err
-		}
-
-		size = blockSize * blockCount
-		return size, nil
+	sb, err := readXFSSuperblock(path)
+	if err != nil {
+		return 0, err
 	}
-	return 0, fmt.Errorf("I can't find size of xfs filesystem: %v", path)
+	return sb.Size(), nil
 }
 
 // Return size of block device as it showed by kernel (in bytes)
@@ -576,13 +429,10 @@ func getDiskSize(path string) uint64 {
 // return slice if all finded LVM PV
 // Возвращает список всех известных lvmPV
 func getLvmPV() []lvmPV {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("pvs", "-o", "pv_name,vg_name,pv_size", "--units", "B", "--separator", "|", "--noheading")
-	cmd.Stdout = buf
-	cmd.Run()
+	out, _, _ := cmd("pvs", "-o", "pv_name,vg_name,pv_size", "--units", "B", "--separator", "|", "--noheading")
 
 	res := make([]lvmPV, 0)
-	for _, line := range strings.Split(buf.String(), "\n") {
+	for _, line := range strings.Split(out, "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -599,6 +449,65 @@ func getLvmPV() []lvmPV {
 	return res
 }
 
+// Find swap partition just after the part, if it is last partition on disk and has free space after it.
+// Return swap partition and its new place at end of disk.
+// Находит раздел подкачки сразу после part, если он последний на диске и после него есть свободное место.
+// Возвращает раздел подкачки и его новое положение в конце диска.
+func findTrailingSwap(partitions []partition, part partition) (swap, newSwap partition, ok bool) {
+	for i := range partitions {
+		if partitions[i].Number != part.Number {
+			continue
+		}
+		if i+2 != len(partitions)-1 || partitions[i+1].IsFreeSpace() || !partitions[i+2].IsFreeSpace() {
+			return swap, newSwap, false
+		}
+		swap = partitions[i+1]
+		if probe, _ := probeFS(swap.Path); probe.Type != "swap" {
+			return swap, newSwap, false
+		}
+		newSwap, ok = swapNewPlace(swap, partitions[i+2].LastByte)
+		return swap, newSwap, ok
+	}
+	return swap, newSwap, false
+}
+
+// Place of swap partition at end of usable disk space.
+// Положение раздела подкачки в конце доступного пространства диска.
+func swapNewPlace(swap partition, lastUsableByte uint64) (newSwap partition, ok bool) {
+	if lastUsableByte+1 < swap.Size() {
+		return newSwap, false
+	}
+	newSwap = swap
+	newSwap.FirstByte = (lastUsableByte + 1 - swap.Size()) / swap_ALIGN * swap_ALIGN
+	newSwap.LastByte = newSwap.FirstByte + swap.Size() - 1
+	if newSwap.FirstByte < swap.FirstByte+min_SIZE_NEW_PARTITION {
+		return newSwap, false
+	}
+	return newSwap, true
+}
+
+// Check if swap is in use now
+// Проверяет, используется ли сейчас раздел подкачки
+func isSwapActive(path string) bool {
+	swapsBytes, err := ioutil.ReadFile(filepath.Join(procRoot, "swaps"))
+	if err != nil {
+		log.Println("Can't read /proc/swaps: ", err)
+		return false
+	}
+	major, minor := getMajorMinor(path)
+	for _, line := range strings.Split(string(swapsBytes), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+			continue
+		}
+		swapMajor, swapMinor := getMajorMinor(fields[0])
+		if swapMajor == major && swapMinor == minor {
+			return true
+		}
+	}
+	return false
+}
+
 func getMajorMinor(path string) (major, minor int) {
 	for {
 		linkDest, err := os.Readlink(path)
@@ -613,11 +522,8 @@ func getMajorMinor(path string) (major, minor int) {
 		}
 	}
 
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("stat", "-c", "%t:%T", path)
-	cmd.Stdout = buf
-	cmd.Run()
-	bufParts := strings.Split(buf.String(), ":")
+	out, _, _ := cmd("stat", "-c", "%t:%T", path)
+	bufParts := strings.Split(out, ":")
 	if len(bufParts) != 2 {
 		return 0, 0
 	}
@@ -632,59 +538,199 @@ func getMountPoint(devPath string) (res string, err error) {
 		return "", fmt.Errorf("Can't get original major/minor numbers: %v", devPath)
 	}
 
-	mountsBytes, err := ioutil.ReadFile("/proc/mounts")
+	mounts, err := readMountInfo()
 	if err != nil {
 		return
 	}
-	// Find mount point of the partition
-	// Ищем точку монтирования указанного устройства
-	mounts := string(mountsBytes)
-	for _, line := range strings.Split(mounts, "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
+	// Find mount point of the partition. Skip mount points, which over-mounted by other filesystem.
+	// Ищем точку монтирования указанного устройства. Пропускаем точки монтирования, перекрытые другой файловой системой.
+	for _, mount := range mounts {
+		if mount.Major != originalMajor || mount.Minor != originalMinor {
 			continue
 		}
-		fields := strings.Fields(line)
-		major, minor := getMajorMinor(fields[0])
-		if major == originalMajor && minor == originalMinor {
-			return fields[1], nil
+		if top, _ := findMountByPath(mounts, mount.MountPoint); top.MountID == mount.MountID {
+			return mount.MountPoint, nil
 		}
 	}
 	return "", fmt.Errorf("Can't find mountpoint of: %v", devPath)
 }
 
+// Line of /proc/self/mountinfo
+// Строка /proc/self/mountinfo
+type mountInfo struct {
+	MountID    int
+	ParentID   int
+	Major      int
+	Minor      int
+	Root       string
+	MountPoint string
+	FSType     string
+	Source     string
+	Options    string // Super options of filesystem: rw,logdev=/dev/sdb1. Опции файловой системы
+}
+
+// Value of filesystem option name=value, empty string if the option absent
+// Значение опции файловой системы name=value, пустая строка если опции нет
+func (mount mountInfo) Option(name string) string {
+	for _, option := range strings.Split(mount.Options, ",") {
+		if strings.HasPrefix(option, name+"=") {
+			return option[len(name)+1:]
+		}
+	}
+	return ""
+}
+
+func readMountInfo() ([]mountInfo, error) {
+	mountInfoBytes, err := ioutil.ReadFile(filepath.Join(procRoot, "self", "mountinfo"))
+	if err != nil {
+		return nil, err
+	}
+	return parseMountInfo(mountInfoBytes), nil
+}
+
+// Parse /proc/self/mountinfo. Format described in man 5 proc:
+// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+// Разбирает /proc/self/mountinfo. Формат описан в man 5 proc.
+func parseMountInfo(mountInfoBytes []byte) (res []mountInfo) {
+	for _, line := range strings.Split(string(mountInfoBytes), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 7 {
+			// empty or bad line
+			continue
+		}
+		separator := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator == -1 || separator+2 >= len(fields) {
+			log.Printf("Can't parse mountinfo line: %q\n", line)
+			continue
+		}
+		var mount mountInfo
+		var err1, err2 error
+		mount.MountID, err1 = strconv.Atoi(fields[0])
+		mount.ParentID, err2 = strconv.Atoi(fields[1])
+		majorMinor := strings.Split(fields[2], ":")
+		if err1 != nil || err2 != nil || len(majorMinor) != 2 {
+			log.Printf("Can't parse mountinfo line: %q\n", line)
+			continue
+		}
+		mount.Major, err1 = strconv.Atoi(majorMinor[0])
+		mount.Minor, err2 = strconv.Atoi(majorMinor[1])
+		if err1 != nil || err2 != nil {
+			log.Printf("Can't parse mountinfo major:minor: %q\n", line)
+			continue
+		}
+		mount.Root = unescapeMountPath(fields[3])
+		mount.MountPoint = unescapeMountPath(fields[4])
+		mount.FSType = unescapeMountPath(fields[separator+1])
+		mount.Source = unescapeMountPath(fields[separator+2])
+		if separator+3 < len(fields) {
+			mount.Options = unescapeMountPath(fields[separator+3])
+		}
+		res = append(res, mount)
+	}
+	return res
+}
+
+// Decode octal escapes (\040 - space, \011 - tab, \012 - new line, \134 - backslash) from mount paths.
+// Декодирует восьмеричные последовательности (\040 - пробел и т.п.) в путях монтирования.
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+	res := make([]byte, 0, len(path))
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) && isOctal(path[i+1]) && isOctal(path[i+2]) && isOctal(path[i+3]) {
+			res = append(res, (path[i+1]-'0')<<6|(path[i+2]-'0')<<3|(path[i+3]-'0'))
+			i += 3
+			continue
+		}
+		res = append(res, path[i])
+	}
+	return string(res)
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
+
+// Find top mount for path: the path can be over-mounted few times, take mount, which isn't parent of other mount with
+// same mount point.
+// Находит верхнее монтирование для пути: в один путь может быть смонтировано несколько файловых систем, берём ту,
+// которая не является родителем для другого монтирования в этот же путь.
+func findMountByPath(mounts []mountInfo, path string) (res mountInfo, ok bool) {
+mountLoop:
+	for _, mount := range mounts {
+		if mount.MountPoint != path {
+			continue
+		}
+		for _, over := range mounts {
+			if over.MountPoint == path && over.ParentID == mount.MountID && over.MountID != mount.MountID {
+				continue mountLoop
+			}
+		}
+		// If there are few top mounts (for example from mount propagation) - take last.
+		// Если верхних монтирований несколько (например при распространении монтирований) - берём последнее.
+		res = mount
+		ok = true
+	}
+	return res, ok
+}
+
+// Path to mounted block device. Detect it by major:minor numbers, because source of mount can be /dev/root, UUID=...
+// and so on. If can't detect by numbers (for example btrfs with virtual device number) - return source of mount.
+// Путь к смонтированному блочному устройству. Определяется по номерам major:minor, т.к. источник монтирования может
+// быть /dev/root, UUID=... и т.п. Если по номерам определить не удалось - возвращается источник монтирования.
+func mountDevicePath(mount mountInfo) string {
+	if mount.Major != 0 {
+		if path := devicePathByMajorMinor(mount.Major, mount.Minor); path != "" {
+			return path
+		}
+	}
+	return mount.Source
+}
+
+// Find block device in /dev by major:minor numbers. Use DEVNAME from sysfs uevent.
+// Находит блочное устройство в /dev по номерам major:minor. Использует DEVNAME из uevent в sysfs.
+func devicePathByMajorMinor(major, minor int) string {
+	dev, err := blockDeviceByMajorMinor(major, minor)
+	if err != nil {
+		return ""
+	}
+	path := dev.DevPath()
+	if devMajor, devMinor := getMajorMinor(path); devMajor != major || devMinor != minor {
+		return ""
+	}
+	return path
+}
+
 // Find and return partitions for create.
 // Находит и возвращает описания разделов, которые можно создать на свободном дисковом пространстве.
 func getNewPartitions() (res []partition) {
 	disks := make(map[string]diskInfo)
-	majorMinorCache := make(map[[2]int]string)
 
 	// Scan disks
 	// Сканируем диски
-	filepath.Walk("/dev", func(path string, info os.FileInfo, err error) error {
-		// If it isn't block device - skip
-		// Если это не файл блочного устройства - пропускаем
-		if !(info.Mode()&os.ModeDevice == os.ModeDevice && info.Mode()&os.ModeCharDevice == 0) {
-			return nil
-		}
-		major, minor := getMajorMinor(path)
-		if getTypeByMajorMinor(major, minor) != type_DISK {
-			return nil
+	devices, err := readBlockDevices()
+	if err != nil {
+		log.Println("Can't read block devices: ", err)
+		return nil
+	}
+	for _, dev := range devices {
+		if dev.StorageType() != type_DISK || dev.Size == 0 {
+			continue
 		}
-
-		disk, err := readDiskInfo(path)
+		disk, err := readDiskInfo(dev.DevPath())
 		if err != nil {
-			return err
-		}
-		if _, ok := majorMinorCache[[2]int{disk.Major, disk.Minor}]; ok {
-			//log.Printf("Skip '%v' by major-minor cache (%v,%v). Prev path: %v\n", path, disk.Major, disk.Minor, cachedPath)
-			return nil
+			log.Printf("Can't read disk info. Skip it: %v (%v)\n", dev.DevPath(), err)
+			continue
 		}
-
 		disks[disk.Path] = disk
-		majorMinorCache[[2]int{disk.Major, disk.Minor}] = path
-		return nil
-	})
+	}
 
 	// For every disk find places for create partition
 	// Для каждого диска смотрим какие новые разделы можно создать.
@@ -695,10 +741,23 @@ diskLoop:
 			continue diskLoop
 		}
 
+		// Free space at end of extended partition and free space just after it use for new logical partition.
+		// Свободное место в конце расширенного раздела и сразу после него используется для нового логического раздела.
+		extended, freeSpaceAfterExtended, hasExtended := disk.extendedPartition()
+		if hasExtended {
+			disk_copy := disk
+			if newPartition, ok := newLogicalPartition(&disk_copy, extended, freeSpaceAfterExtended); ok {
+				res = append(res, newPartition)
+			}
+		}
+
 		for _, part := range disk.Partitions {
 			if !part.IsFreeSpace() {
 				continue
 			}
+			if hasExtended && part.FirstByte == extended.LastByte+1 {
+				continue
+			}
 			if part.Size() >= min_SIZE_NEW_PARTITION {
 				// Need store point to copy of current item state.
 				// В for _, disk := range ... меняется сам экземпляр disk, а нам нужно сохранить ссылку на копию
@@ -722,48 +781,72 @@ diskLoop:
 	return res
 }
 
-// from https://git.kernel.org/cgit/linux/kernel/git/stable/linux-stable.git/tree/Documentation/devices.txt?id=v4.2
-// and cat /proc/devices
-// and call pvs,lvs for detection lvm device
+// Describe new logical partition after last logical partition in extended partition. It can take free space after
+// extended partition too, then extended partition will be extended while create the partition.
+// Описывает новый логический раздел после последнего логического раздела. Он может занимать и свободное место после
+// расширенного раздела, тогда расширенный раздел будет расширен при создании нового раздела.
+func newLogicalPartition(disk *diskInfo, extended partition, freeSpaceAfterExtended uint64) (res partition, ok bool) {
+	firstByte := extended.LastByte + 1
+	lastByte := extended.LastByte + freeSpaceAfterExtended
+	number := uint32(5)
+	last, hasLogical := disk.lastLogicalPartition()
+	if hasLogical {
+		number = last.Number + 1
+		firstByte = last.LastByte + 1
+		if firstByte > lastByte {
+			return res, false
+		}
+	} else {
+		// First EBR always placed at start of extended partition
+		// Первый EBR всегда находится в начале расширенного раздела
+		firstByte = extended.FirstByte
+	}
+
+	// Order of EBR chain can differ from order on disk. Can't create partition if other logical partition placed after it.
+	// Порядок цепочки EBR может отличаться от порядка на диске. Нельзя создать раздел, если после него есть другой
+	// логический раздел.
+	for _, part := range disk.LogicalPartitions {
+		if !part.IsFreeSpace() && part.occupiedFirstByte() >= firstByte {
+			return res, false
+		}
+	}
+
+	res = partition{
+		Disk:    disk,
+		Number:  number,
+		Logical: true,
+		EBRByte: firstByte,
+	}
+	res.FirstByte = (firstByte/mbr_LOGICAL_ALIGN + 1) * mbr_LOGICAL_ALIGN
+	res.LastByte = lastByte
+	if res.FirstByte > res.LastByte || res.Size() < min_SIZE_NEW_PARTITION {
+		return res, false
+	}
+	res.Path = res.makePath()
+	return res, true
+}
+
+// Detect type of block device by sysfs topology.
+// LVM devices detected by cache, filled by pvs,lvs calls.
+// Определяет тип блочного устройства по топологии в sysfs.
+// Устройства LVM определяются по кешу, заполненному при вызовах pvs,lvs.
 func getTypeByMajorMinor(major, minor int) storageItemType {
 	if res, ok := majorMinorDeviceTypeCache[[2]int{major, minor}]; ok {
 		return res.Type
 	}
 
-	switch major {
-	case dynamicMajorIdVirtblk:
-		if minor == 0 {
-			return type_DISK
-		}
-		return type_PARTITION
-	case 7:
-		return type_DISK
-	case 3, 22, 33, 34, 56, 57, 88, 89, 90, 91:
-		if minor%64 == 0 {
-			return type_DISK
-		} else {
-			return type_PARTITION
-		}
-	case 8, 65, 66, 67, 68, 69, 70, 71, 128, 129, 130, 131, 132, 133, 134, 135:
-		if minor%16 == 0 {
-			return type_DISK
-		} else {
-			return type_PARTITION
-		}
-	case 259:
-		return type_PARTITION
+	dev, err := blockDeviceByMajorMinor(major, minor)
+	if err != nil {
+		return type_UNKNOWN
 	}
-	return type_UNKNOWN
+	return dev.StorageType()
 }
 
 // Path - VolumeGroup/VolumeName
 func lvmLVGetSize(path string) uint64 {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("lvs", "-o", "vg_name,lv_name,lv_size", "--units", "B", "--separator", "/", "--noheading")
-	cmd.Stdout = buf
-	cmd.Run()
+	out, _, _ := cmd("lvs", "-o", "vg_name,lv_name,lv_size", "--units", "B", "--separator", "/", "--noheading")
 	needPrefix := path + "/"
-	for _, line := range strings.Split(buf.String(), "\n") {
+	for _, line := range strings.Split(out, "\n") {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, needPrefix) {
 			sizeString := line[len(needPrefix) : len(line)-1]
@@ -800,19 +883,14 @@ func lvmPVGetSize(path string) uint64 {
 }
 
 func lvmPVGetSizeTry(path string) uint64 {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("pvs", "-o", "pv_size", "--units", "B", "--separator", "|", "--noheading", path)
-	cmd.Stdout = buf
-	cmd.Run()
-	line := strings.TrimSuffix(strings.TrimSpace(buf.String()), "B")
+	out, _, _ := cmd("pvs", "-o", "pv_size", "--units", "B", "--separator", "|", "--noheading", path)
+	line := strings.TrimSuffix(strings.TrimSpace(out), "B")
 	pvSize, err := strconv.ParseUint(line, 10, 64)
 	if err != nil {
 		log.Println("Can't parse pv size: ", path, line, err)
 		return 0
 	}
 	return pvSize
-	log.Println("Can't find pv size: ", path, ":\n", buf.String())
-	return 0
 }
 
 func lvmVGGetSize(vgName string) (size, freeSize, extentSize uint64) {
@@ -838,11 +916,15 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 	disk.Path = path
 	disk.Major, disk.Minor = getMajorMinor(path)
 
-	blockSizeString, _, _ := cmd("blockdev", "--getss", disk.Path)
-	disk.SectorSizeLogical, err = strconv.ParseUint(strings.TrimSpace(blockSizeString), 10, 64)
-	if err != nil {
-		log.Println("Can't get block size:", disk.Path, err)
-		return
+	if dev, devErr := blockDeviceByMajorMinor(disk.Major, disk.Minor); devErr == nil && dev.LogicalBlockSize != 0 {
+		disk.SectorSizeLogical = dev.LogicalBlockSize
+	} else {
+		blockSizeString, _, _ := cmd("blockdev", "--getss", disk.Path)
+		disk.SectorSizeLogical, err = strconv.ParseUint(strings.TrimSpace(blockSizeString), 10, 64)
+		if err != nil {
+			log.Println("Can't get block size:", disk.Path, err)
+			return
+		}
 	}
 
 	disk.Size = getDiskSize(disk.Path)
@@ -861,6 +943,7 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 
 	var firstUsableDiskByte uint64
 	var lastUsableDiskByte uint64
+	var extended partition
 
 	// Try read mbr
 	mbrTable, err := mbr.Read(diskFile)
@@ -910,6 +993,17 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 			}
 			part.Path = part.makePath()
 			disk.Partitions = append(disk.Partitions, part)
+			if isMbrExtended(mbrPart.GetType()) {
+				disk.ExtendedNumber = part.Number
+				extended = part
+			}
+		}
+		if disk.ExtendedNumber != 0 {
+			disk.LogicalPartitions, err = readLogicalPartitions(diskFile, &disk, extended)
+			if err != nil {
+				log.Println("Can't read logical partitions: ", disk.Path, err)
+				return
+			}
 		}
 	}
 
@@ -927,50 +1021,166 @@ func readDiskInfo(path string) (disk diskInfo, err error) {
 	}
 
 	// make free space pseudo partitions
-	newPartitions := make([]partition, 0)
-	var lastByte = firstUsableDiskByte - 1
-	for i, part := range disk.Partitions {
+	disk.Partitions, err = addFreeSpacePartitions(&disk, disk.Partitions, firstUsableDiskByte, lastUsableDiskByte)
+	if err != nil {
+		return
+	}
+	if disk.ExtendedNumber != 0 {
+		sort.Sort(partitionSortByFirstByte(disk.LogicalPartitions))
+		disk.LogicalPartitions, err = addFreeSpacePartitions(&disk, disk.LogicalPartitions, extended.FirstByte, extended.LastByte)
+	}
+	return
+}
+
+// Add free space pseudo partitions between sorted partitions
+// Добавляет псевдо-разделы свободного места между отсортированными разделами
+func addFreeSpacePartitions(disk *diskInfo, partitions []partition, firstUsableByte, lastUsableByte uint64) (res []partition, err error) {
+	res = make([]partition, 0)
+	var lastByte = firstUsableByte - 1
+	for i, part := range partitions {
 		switch {
-		case lastByte == part.FirstByte-1:
-			newPartitions = append(newPartitions, part)
-		case lastByte < part.FirstByte-1:
+		case lastByte == part.occupiedFirstByte()-1:
+			res = append(res, part)
+		case lastByte < part.occupiedFirstByte()-1:
 			newPart := partition{
-				Disk:      &disk,
+				Disk:      disk,
 				Number:    0,
 				FirstByte: lastByte + 1,
-				LastByte:  part.FirstByte - 1,
+				LastByte:  part.occupiedFirstByte() - 1,
 			}
-			newPartitions = append(newPartitions, newPart, part)
+			res = append(res, newPart, part)
 		default:
-			log.Printf("ERROR!!!! Have overlap partitions!!!!\n%v - %v\n%#v", disk.Path, i, disk.Partitions)
-			err = fmt.Errorf("OVERLAP PARTITIONS")
-			return
+			log.Printf("ERROR!!!! Have overlap partitions!!!!\n%v - %v\n%#v", disk.Path, i, partitions)
+			return nil, fmt.Errorf("OVERLAP PARTITIONS")
 		}
 		lastByte = part.LastByte
 	}
 
-	if lastByte < lastUsableDiskByte {
+	if lastByte < lastUsableByte {
 		newPart := partition{
-			Disk:      &disk,
+			Disk:      disk,
 			Number:    0,
 			FirstByte: lastByte + 1,
-			LastByte:  lastUsableDiskByte,
+			LastByte:  lastUsableByte,
 		}
-		newPartitions = append(newPartitions, newPart)
+		res = append(res, newPart)
 	}
-	disk.Partitions = newPartitions
-	return
+	return res, nil
+}
+
+// Extended partition of msdos disk and free space after it.
+// Расширенный раздел диска msdos и свободное место после него.
+func (disk diskInfo) extendedPartition() (extended partition, freeSpace uint64, ok bool) {
+	if disk.ExtendedNumber == 0 {
+		return extended, 0, false
+	}
+	for i, part := range disk.Partitions {
+		if part.Number != disk.ExtendedNumber {
+			continue
+		}
+		if i+1 < len(disk.Partitions) && disk.Partitions[i+1].IsFreeSpace() {
+			freeSpace = disk.Partitions[i+1].LastByte - part.LastByte
+		}
+		return part, freeSpace, true
+	}
+	return extended, 0, false
+}
+
+// Last logical partition in the EBR chain. New logical partition links after it.
+// Последний логический раздел в цепочке EBR. Новый логический раздел добавляется в цепочку после него.
+func (disk diskInfo) lastLogicalPartition() (last partition, ok bool) {
+	for _, part := range disk.LogicalPartitions {
+		if part.IsFreeSpace() {
+			continue
+		}
+		if !ok || part.Number > last.Number {
+			last = part
+			ok = true
+		}
+	}
+	return last, ok
+}
+
+// Logical partition, which placed last on disk. Order of EBR chain can differ from order on disk, so only this
+// partition grows with extended partition.
+// Логический раздел, расположенный последним на диске. Порядок цепочки EBR может отличаться от порядка на диске,
+// поэтому только этот раздел растёт вместе с расширенным разделом.
+func (disk diskInfo) lastPlacedLogicalPartition() (last partition, ok bool) {
+	for _, part := range disk.LogicalPartitions {
+		if part.IsFreeSpace() {
+			continue
+		}
+		if !ok || part.LastByte > last.LastByte {
+			last = part
+			ok = true
+		}
+	}
+	return last, ok
+}
+
+func isMbrExtended(partType mbr.PartitionType) bool {
+	switch partType {
+	case mbr_PART_EXTENDED, mbr_PART_EXTENDED_LBA, mbr_PART_EXTENDED_LINUX:
+		return true
+	default:
+		return false
+	}
+}
+
+// Read EBR sector. EBR has relative addresses, so check of partitions intersection isn't actual for it.
+// Читает сектор EBR. В EBR адреса относительные, поэтому проверка пересечения разделов для него не актуальна.
+func readEBR(diskFile io.ReadSeeker, pos uint64) (ebr *mbr.MBR, err error) {
+	if _, err = diskFile.Seek(int64(pos), 0); err != nil {
+		return nil, err
+	}
+	ebr, err = mbr.Read(diskFile)
+	if err == mbr.ErrorPartitionsIntersection || err == mbr.ErrorPartitionLastSectorHigh {
+		err = nil
+	}
+	return ebr, err
+}
+
+// Walk by EBR chain and return logical partitions (numbers start from 5).
+// Проходит по цепочке EBR и возвращает логические разделы (номера начинаются с 5).
+func readLogicalPartitions(diskFile io.ReadSeeker, disk *diskInfo, extended partition) (res []partition, err error) {
+	sectorSize := disk.SectorSizeLogical
+	extendedStartLBA := extended.FirstByte / sectorSize
+	ebrLBA := extendedStartLBA
+	number := uint32(5)
+	for i := 0; i < max_MBR_LOGICAL_PARTITIONS; i++ {
+		ebr, err := readEBR(diskFile, ebrLBA*sectorSize)
+		if err != nil {
+			return nil, fmt.Errorf("Can't read EBR at sector %v: %v", ebrLBA, err)
+		}
+		data := ebr.GetPartition(1)
+		if !data.IsEmpty() {
+			part := partition{
+				Disk:      disk,
+				Number:    number,
+				FirstByte: (ebrLBA + uint64(data.GetLBAStart())) * sectorSize,
+				LastByte:  (ebrLBA+uint64(data.GetLBAStart())+uint64(data.GetLBALen()))*sectorSize - 1,
+				Logical:   true,
+				EBRByte:   ebrLBA * sectorSize,
+			}
+			part.Path = part.makePath()
+			res = append(res, part)
+			number++
+		}
+		next := ebr.GetPartition(2)
+		if next.IsEmpty() || next.GetLBAStart() == 0 {
+			return res, nil
+		}
+		ebrLBA = extendedStartLBA + uint64(next.GetLBAStart())
+	}
+	return nil, errors.New("Too many logical partitions or loop in EBR chain")
 }
 
 // Scan LVM logical volumes and store major,minor numbers of them for strong detection of LVM/non LVM block device.
 // Сканирует LVM_LV, запоминает их major,minor номера устройств. Для надёжного определения что блочное устройство это
 // LVM/не LVM.
 func scanLVM() {
-	buf := &bytes.Buffer{}
-	cmd := exec.Command("lvs", "-a", "-o", "vg_name,lv_name,lv_kernel_major,lv_kernel_minor,lv_size", "--units", "B", "--separator", "/", "--noheading")
-	cmd.Stdout = buf
-	cmd.Run()
-	for _, line := range strings.Split(buf.String(), "\n") {
+	out, _, _ := cmd("lvs", "-a", "-o", "vg_name,lv_name,lv_kernel_major,lv_kernel_minor,lv_size", "--units", "B", "--separator", "/", "--noheading")
+	for _, line := range strings.Split(out, "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
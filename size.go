@@ -0,0 +1,165 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Target size of extend: absolute (200G), relative (+50G) or percent of available growth (80%).
+// Zero value - extend to max size.
+// Целевой размер расширения: абсолютный (200G), относительный (+50G) или процент от доступного роста (80%).
+// Нулевое значение - расширение до максимального размера.
+type sizeTarget struct {
+	Value    uint64 // Bytes or percents. Байты или проценты
+	Relative bool   // Value - growth from current size. Value - рост от текущего размера
+	Percent  bool   // Value - percents of available growth. Value - проценты от доступного роста
+}
+
+func (target sizeTarget) IsMax() bool {
+	return target == sizeTarget{}
+}
+
+func (target sizeTarget) String() string {
+	switch {
+	case target.IsMax():
+		return "max"
+	case target.Percent:
+		return fmt.Sprintf("%v%%", target.Value)
+	case target.Relative:
+		return "+" + formatSize(target.Value)
+	default:
+		return formatSize(target.Value)
+	}
+}
+
+/*
+Growth, which need for reach the target.
+size - current size, available - max growth.
+
+Рост, необходимый для достижения цели.
+size - текущий размер, available - максимальный рост.
+*/
+func (target sizeTarget) Need(size, available uint64) (need uint64, err error) {
+	switch {
+	case target.IsMax():
+		return available, nil
+	case target.Percent:
+		need = available / 100 * target.Value
+		need += available % 100 * target.Value / 100
+		return need, nil
+	case target.Relative:
+		need = target.Value
+	default:
+		if target.Value <= size {
+			return 0, nil
+		}
+		need = target.Value - size
+	}
+	if need > available {
+		return 0, fmt.Errorf("Can't extend to %v: current size %v, max growth %v", target, formatSize(size),
+			formatSize(available))
+	}
+	return need, nil
+}
+
+/*
+Parse target size: 200G, +50G, 80%.
+Разбирает целевой размер: 200G, +50G, 80%.
+*/
+func parseSizeTarget(s string) (target sizeTarget, err error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || s == "max":
+		return target, nil
+	case strings.HasSuffix(s, "%"):
+		target.Percent = true
+		target.Value, err = parseUint(strings.TrimSuffix(s, "%"))
+		if err != nil {
+			return target, fmt.Errorf("Bad percent: %v", s)
+		}
+		if target.Value == 0 || target.Value > 100 {
+			return target, fmt.Errorf("Percent must be from 1 to 100: %v", s)
+		}
+		return target, nil
+	case strings.HasPrefix(s, "+"):
+		target.Relative = true
+		s = s[1:]
+	}
+	target.Value, err = parseSize(s)
+	if err == nil && target.Value == 0 {
+		err = errors.New("Target size must be more then zero")
+	}
+	return target, err
+}
+
+// Reserved free space: size (10G) or percent of total size (10%). Zero value - without reserve.
+// Резервируемое свободное место: размер (10G) или процент от общего размера (10%). Нулевое значение - без резерва.
+type sizeReserve struct {
+	Value   uint64 // Bytes or percents. Байты или проценты
+	Percent bool   // Value - percents of total size. Value - проценты от общего размера
+}
+
+func (reserve sizeReserve) String() string {
+	if reserve.Percent {
+		return fmt.Sprintf("%v%%", reserve.Value)
+	}
+	return formatSize(reserve.Value)
+}
+
+// Reserved bytes for total size.
+// Зарезервированные байты для общего размера.
+func (reserve sizeReserve) Bytes(total uint64) uint64 {
+	if reserve.Percent {
+		return total/100*reserve.Value + total%100*reserve.Value/100
+	}
+	return reserve.Value
+}
+
+/*
+Parse reserve: 10G, 10%. Empty string - without reserve.
+Разбирает резерв: 10G, 10%. Пустая строка - без резерва.
+*/
+func parseSizeReserve(s string) (reserve sizeReserve, err error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return reserve, nil
+	case strings.HasSuffix(s, "%"):
+		reserve.Percent = true
+		reserve.Value, err = parseUint(strings.TrimSuffix(s, "%"))
+		if err != nil || reserve.Value > 100 {
+			return reserve, fmt.Errorf("Bad percent: %v", s)
+		}
+		return reserve, nil
+	default:
+		reserve.Value, err = parseSize(s)
+		return reserve, err
+	}
+}
+
+/*
+Parse size with binary units: 1024, 10K, 20M, 30G, 1T, 1P. Units may be with suffix B or iB: 30GB, 30GiB.
+Разбирает размер с двоичными единицами измерения: 1024, 10K, 20M, 30G, 1T, 1P. После единиц может быть B или iB.
+*/
+func parseSize(s string) (size uint64, err error) {
+	number := strings.ToUpper(strings.TrimSpace(s))
+	number = strings.TrimSuffix(strings.TrimSuffix(number, "B"), "I")
+	multiplier := uint64(1)
+	if len(number) > 0 {
+		if pos := strings.IndexByte("KMGTP", number[len(number)-1]); pos != -1 {
+			for i := 0; i <= pos; i++ {
+				multiplier *= 1024
+			}
+			number = number[:len(number)-1]
+		}
+	}
+	size, err = parseUint(number)
+	if err != nil {
+		return 0, fmt.Errorf("Bad size: %v", s)
+	}
+	if size > ^uint64(0)/multiplier {
+		return 0, fmt.Errorf("Too big size: %v", s)
+	}
+	return size * multiplier, nil
+}
@@ -0,0 +1,230 @@
+package fsextender
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Root of sysfs. It can be changed for tests with fixture tree.
+// Корень sysfs. Может быть изменен для тестов на подготовленном дереве файлов.
+var sysfsRoot = "/sys"
+
+// Roots of device files and procfs. They can be changed for tests with fixture tree, like sysfsRoot.
+// Корни файлов устройств и procfs. Могут быть изменены для тестов на подготовленном дереве файлов, как sysfsRoot.
+var (
+	devRoot  = "/dev"
+	procRoot = "/proc"
+)
+
+// Size of sector in sysfs "size" attribute. It doesn't depend from logical block size of device.
+// Размер сектора в атрибуте sysfs "size". Не зависит от размера логического блока устройства.
+const sysfs_SECTOR_SIZE = 512
+
+// Description of block device from sysfs
+// Описание блочного устройства из sysfs
+type blockDevice struct {
+	Name             string // Kernel name of device, for example sda1. Имя устройства в ядре, например sda1
+	DevName          string // Name of device file in /dev. Имя файла устройства в /dev
+	Major            int
+	Minor            int
+	Partition        uint32   // Number of partition. 0 - if device isn't partition. Номер раздела. 0 - если устройство не раздел
+	Start            uint64   // Bytes, start of partition on disk. Байты, начало раздела на диске
+	Parent           string   // Kernel name of disk for partitions. Имя диска, на котором расположен раздел
+	Holders          []string // Devices, placed over the device. Устройства, расположенные поверх устройства
+	Slaves           []string // Devices, under the device. Устройства, на которых расположено устройство
+	Size             uint64   // Bytes
+	LogicalBlockSize uint64   // Bytes. For partitions - logical block size of parent disk.
+	DMName           string   // Name of device mapper device. Имя устройства device mapper
+	DMUUID           string   // UUID of device mapper device, it contains target subsystem. UUID устройства device mapper, содержит подсистему
+	MDLevel          string   // RAID level of md device. Уровень RAID для md устройства
+}
+
+func (dev blockDevice) IsPartition() bool {
+	return dev.Partition != 0
+}
+
+// Path to device file in /dev
+// Путь к файлу устройства в /dev
+func (dev blockDevice) DevPath() string {
+	return filepath.Join(devRoot, dev.DevName)
+}
+
+// Type of storage item by the device position in topology
+// Тип устройства в зависимости от его положения в топологии устройств
+func (dev blockDevice) StorageType() storageItemType {
+	switch {
+	case dev.IsPartition():
+		return type_PARTITION
+	case strings.HasPrefix(dev.DMUUID, "CRYPT-"):
+		return type_CRYPT
+	case dev.MDLevel != "":
+		return type_RAID
+	case len(dev.Slaves) > 0:
+		// Virtual device over other devices (device mapper, md raid and so on). It have own type detection.
+		// Виртуальное устройство поверх других устройств (device mapper, md raid и т.п.). Определяется отдельно.
+		return type_UNKNOWN
+	default:
+		return type_DISK
+	}
+}
+
+// Read block device description by kernel name
+// Читает описание блочного устройства по имени в ядре
+func readBlockDevice(name string) (dev blockDevice, err error) {
+	devDir, err := filepath.EvalSymlinks(filepath.Join(sysfsRoot, "class", "block", name))
+	if err != nil {
+		return dev, err
+	}
+	dev.Name = name
+	dev.DevName = strings.Replace(name, "!", "/", -1)
+	if uevent, err := ioutil.ReadFile(filepath.Join(devDir, "uevent")); err == nil {
+		for _, line := range strings.Split(string(uevent), "\n") {
+			if strings.HasPrefix(line, "DEVNAME=") {
+				dev.DevName = strings.TrimPrefix(line, "DEVNAME=")
+			}
+		}
+	}
+
+	majorMinor, err := readSysfsString(filepath.Join(devDir, "dev"))
+	if err != nil {
+		return dev, err
+	}
+	if _, err = fmt.Sscanf(majorMinor, "%d:%d", &dev.Major, &dev.Minor); err != nil {
+		return dev, fmt.Errorf("Can't parse major:minor of %v: %v", name, err)
+	}
+
+	sectors, err := readSysfsUint(filepath.Join(devDir, "size"))
+	if err != nil {
+		return dev, err
+	}
+	dev.Size = sectors * sysfs_SECTOR_SIZE
+
+	// Partition have "partition" attribute and placed in directory of parent disk
+	// Раздел имеет атрибут "partition" и расположен в директории диска
+	blockSizeDir := devDir
+	if partNumber, err := readSysfsUint(filepath.Join(devDir, "partition")); err == nil {
+		dev.Partition = uint32(partNumber)
+		if start, err := readSysfsUint(filepath.Join(devDir, "start")); err == nil {
+			dev.Start = start * sysfs_SECTOR_SIZE
+		}
+		dev.Parent = filepath.Base(filepath.Dir(devDir))
+		blockSizeDir = filepath.Dir(devDir)
+	}
+	dev.LogicalBlockSize, err = readSysfsUint(filepath.Join(blockSizeDir, "queue", "logical_block_size"))
+	if err != nil {
+		dev.LogicalBlockSize = 0
+	}
+
+	dev.DMName, _ = readSysfsString(filepath.Join(devDir, "dm", "name"))
+	dev.DMUUID, _ = readSysfsString(filepath.Join(devDir, "dm", "uuid"))
+	dev.MDLevel, _ = readSysfsString(filepath.Join(devDir, "md", "level"))
+	dev.Holders = readSysfsDirNames(filepath.Join(devDir, "holders"))
+	dev.Slaves = readSysfsDirNames(filepath.Join(devDir, "slaves"))
+	return dev, nil
+}
+
+// Read block device description by major:minor numbers
+// Читает описание блочного устройства по номерам major:minor
+func blockDeviceByMajorMinor(major, minor int) (dev blockDevice, err error) {
+	if major == 0 {
+		return dev, errors.New("Device hasn't major number")
+	}
+	devDir, err := filepath.EvalSymlinks(filepath.Join(sysfsRoot, "dev", "block", fmt.Sprintf("%v:%v", major, minor)))
+	if err != nil {
+		return dev, err
+	}
+	return readBlockDevice(filepath.Base(devDir))
+}
+
+// Read block device description by path of device file. If device file doesn't exist - find device by name.
+// Читает описание блочного устройства по пути к файлу устройства. Если файла устройства нет - ищет устройство по имени.
+func blockDeviceByPath(path string) (dev blockDevice, err error) {
+	if major, minor := getMajorMinor(path); major != 0 {
+		return blockDeviceByMajorMinor(major, minor)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	name := strings.Replace(strings.TrimPrefix(filepath.Clean(path), devRoot+"/"), "/", "!", -1)
+	return readBlockDevice(name)
+}
+
+// Disk path and number of partition. Partition names differ for different disks (sda1, nvme0n1p1, mmcblk0p1, ...) so
+// detect it by sysfs and guess by name only if sysfs doesn't know the device.
+// Путь к диску и номер раздела. Имена разделов различаются для разных дисков (sda1, nvme0n1p1, mmcblk0p1, ...),
+// поэтому они определяются по sysfs и угадываются по имени, только если sysfs не знает устройство.
+func partitionDisk(path string) (diskPath string, partNumber uint32, err error) {
+	dev, err := blockDeviceByPath(path)
+	if err != nil {
+		return extractPartNumber(path)
+	}
+	if !dev.IsPartition() {
+		return "", 0, fmt.Errorf("Device isn't partition: %v", path)
+	}
+	parent, err := readBlockDevice(dev.Parent)
+	if err != nil {
+		return "", 0, fmt.Errorf("Can't read disk of partition %v: %v", path, err)
+	}
+	return parent.DevPath(), dev.Partition, nil
+}
+
+// Path of disk, which contains the device. For disks - path of the disk.
+// Путь к диску, на котором находится устройство. Для дисков - путь к самому диску.
+func deviceDisk(path string) (diskPath string, err error) {
+	dev, err := blockDeviceByPath(path)
+	if err == nil && dev.StorageType() == type_DISK {
+		return dev.DevPath(), nil
+	}
+	diskPath, _, err = partitionDisk(path)
+	return diskPath, err
+}
+
+// Return descriptions of all block devices in system
+// Возвращает описания всех блочных устройств системы
+func readBlockDevices() (res []blockDevice, err error) {
+	names := readSysfsDirNames(filepath.Join(sysfsRoot, "class", "block"))
+	if len(names) == 0 {
+		return nil, errors.New("Can't find block devices in sysfs")
+	}
+	for _, name := range names {
+		dev, err := readBlockDevice(name)
+		if err != nil {
+			continue
+		}
+		res = append(res, dev)
+	}
+	return res, nil
+}
+
+func readSysfsString(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func readSysfsUint(path string) (uint64, error) {
+	content, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(content, 10, 64)
+}
+
+func readSysfsDirNames(path string) (res []string) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer dir.Close()
+	res, _ = dir.Readdirnames(-1)
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,252 @@
+package fsextender
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Layout of xfs superblock (big endian), offsets from start of device. Described in linux fs/xfs/libxfs/xfs_format.h.
+// Расположение суперблока xfs (big endian), смещения от начала устройства. Описано в linux fs/xfs/libxfs/xfs_format.h.
+const (
+	xfs_SUPERBLOCK_SIZE = 512
+	xfs_MAGIC           = 0x58465342 // XFSB
+
+	xfs_OFFSET_MAGIC             = 0x00
+	xfs_OFFSET_BLOCK_SIZE        = 0x04
+	xfs_OFFSET_DATA_BLOCKS       = 0x08
+	xfs_OFFSET_UUID              = 0x20
+	xfs_OFFSET_AG_BLOCKS         = 0x54
+	xfs_OFFSET_AG_COUNT          = 0x58
+	xfs_OFFSET_VERSION           = 0x64
+	xfs_OFFSET_SECTOR_SIZE       = 0x66
+	xfs_OFFSET_FNAME             = 0x6C
+	xfs_OFFSET_IN_PROGRESS       = 0x7E
+	xfs_OFFSET_FEATURES2         = 0xC8
+	xfs_OFFSET_FEATURES_COMPAT   = 0xD0
+	xfs_OFFSET_FEATURES_RO       = 0xD4
+	xfs_OFFSET_FEATURES_INCOMPAT = 0xD8
+	xfs_OFFSET_FEATURES_LOG      = 0xDC
+
+	xfs_VERSION_NUM_MASK = 0x000f
+	xfs_VERSION_5        = 5 // Version with CRC and feature fields. Версия с CRC и полями возможностей
+)
+
+// Fields of xfs superblock, which used for resize
+// Поля суперблока xfs, которые используются для изменения размера
+type xfsSuperblock struct {
+	BlockSize        uint64
+	DataBlocks       uint64
+	AGBlocks         uint32 // Blocks in allocation group. Блоков в группе размещения
+	AGCount          uint32
+	SectorSize       uint16
+	Version          uint16 // Full sb_versionnum with feature bits. Полный sb_versionnum с битами возможностей
+	Features2        uint32
+	FeaturesCompat   uint32 // Fields of version 5 only. Поля только версии 5
+	FeaturesROCompat uint32
+	FeaturesIncompat uint32
+	FeaturesLog      uint32
+	UUID             [16]byte
+	Label            string
+	InProgress       bool // mkfs didn't finish. mkfs не завершился
+}
+
+// Read primary superblock of xfs from device or image file. It doesn't need mount.
+// Читает основной суперблок xfs с устройства или из файла образа. Монтирование не требуется.
+func readXFSSuperblock(path string) (sb xfsSuperblock, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return sb, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, xfs_SUPERBLOCK_SIZE)
+	if _, err = f.ReadAt(buf, 0); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return sb, fmt.Errorf("Can't read xfs superblock: %v (%v)", path, err)
+	}
+	sb, err = parseXFSSuperblock(buf)
+	if err != nil {
+		return sb, fmt.Errorf("%v: %v", path, err)
+	}
+	return sb, nil
+}
+
+func parseXFSSuperblock(buf []byte) (sb xfsSuperblock, err error) {
+	if len(buf) < xfs_SUPERBLOCK_SIZE {
+		return sb, fmt.Errorf("Short xfs superblock: %v bytes", len(buf))
+	}
+	be := binary.BigEndian
+	if magic := be.Uint32(buf[xfs_OFFSET_MAGIC:]); magic != xfs_MAGIC {
+		return sb, fmt.Errorf("Bad magic of xfs superblock: %#x", magic)
+	}
+	sb.BlockSize = uint64(be.Uint32(buf[xfs_OFFSET_BLOCK_SIZE:]))
+	if sb.BlockSize < 512 || sb.BlockSize > 65536 || sb.BlockSize&(sb.BlockSize-1) != 0 {
+		return sb, fmt.Errorf("Bad block size of xfs superblock: %v", sb.BlockSize)
+	}
+	sb.DataBlocks = be.Uint64(buf[xfs_OFFSET_DATA_BLOCKS:])
+	copy(sb.UUID[:], buf[xfs_OFFSET_UUID:])
+	sb.AGBlocks = be.Uint32(buf[xfs_OFFSET_AG_BLOCKS:])
+	sb.AGCount = be.Uint32(buf[xfs_OFFSET_AG_COUNT:])
+	sb.Version = be.Uint16(buf[xfs_OFFSET_VERSION:])
+	sb.SectorSize = be.Uint16(buf[xfs_OFFSET_SECTOR_SIZE:])
+	sb.Label = cString(buf[xfs_OFFSET_FNAME : xfs_OFFSET_FNAME+12])
+	sb.InProgress = buf[xfs_OFFSET_IN_PROGRESS] != 0
+	sb.Features2 = be.Uint32(buf[xfs_OFFSET_FEATURES2:])
+	if sb.VersionNum() >= xfs_VERSION_5 {
+		sb.FeaturesCompat = be.Uint32(buf[xfs_OFFSET_FEATURES_COMPAT:])
+		sb.FeaturesROCompat = be.Uint32(buf[xfs_OFFSET_FEATURES_RO:])
+		sb.FeaturesIncompat = be.Uint32(buf[xfs_OFFSET_FEATURES_INCOMPAT:])
+		sb.FeaturesLog = be.Uint32(buf[xfs_OFFSET_FEATURES_LOG:])
+	}
+	return sb, nil
+}
+
+// Size of data section in bytes
+// Размер раздела данных в байтах
+func (sb xfsSuperblock) Size() uint64 {
+	return sb.DataBlocks * sb.BlockSize
+}
+
+// Version of superblock: 4 or 5
+// Версия суперблока: 4 или 5
+func (sb xfsSuperblock) VersionNum() uint16 {
+	return sb.Version & xfs_VERSION_NUM_MASK
+}
+
+func (sb xfsSuperblock) UUIDString() string {
+	return uuidString(sb.UUID)
+}
+
+// ioctl requests from linux fs/xfs/libxfs/xfs_fs.h
+// Запросы ioctl из linux fs/xfs/libxfs/xfs_fs.h
+const (
+	ioctl_XFS_FSGEOMETRY_V4 = 0x8070587c // _IOR('X', 124, struct xfs_fsop_geom_v4)
+	ioctl_XFS_FSGEOMETRY    = 0x8100587e // _IOR('X', 126, struct xfs_fsop_geom), linux 5.2+
+	ioctl_XFS_FSGROWFSDATA  = 0x4010586e // _IOW('X', 110, struct xfs_growfs_data)
+	ioctl_XFS_FSGROWFSLOG   = 0x4008586f // _IOW('X', 111, struct xfs_growfs_log)
+	ioctl_XFS_FSGROWFSRT    = 0x40105870 // _IOW('X', 112, struct xfs_growfs_rt)
+	xfs_FSGEOMETRY_V4_SIZE  = 112
+	xfs_FSGEOMETRY_SIZE     = 256
+)
+
+// struct xfs_fsop_geom from linux fs/xfs/libxfs/xfs_fs.h, first 112 bytes are struct xfs_fsop_geom_v4. Sizes in blocks.
+// struct xfs_fsop_geom из linux fs/xfs/libxfs/xfs_fs.h, первые 112 байт - struct xfs_fsop_geom_v4. Размеры в блоках.
+type xfsGeometry struct {
+	BlockSize    uint32
+	RTExtSize    uint32 // Blocks in realtime extent. Блоков в realtime экстенте
+	AGBlocks     uint32
+	AGCount      uint32
+	LogBlocks    uint32
+	SectSize     uint32
+	InodeSize    uint32
+	IMaxPct      uint32
+	DataBlocks   uint64
+	RTBlocks     uint64
+	RTExtents    uint64
+	LogStart     uint64 // 0 for external log. 0 для внешнего журнала
+	UUID         [16]byte
+	SUnit        uint32
+	SWidth       uint32
+	Version      uint32
+	Flags        uint32
+	LogSectSize  uint32
+	RTSectSize   uint32
+	DirBlockSize uint32
+	LogSUnit     uint32
+	Sick         uint32
+	Checked      uint32
+	Reserved     [17]uint64
+}
+
+// struct xfs_growfs_data
+type xfsGrowFSData struct {
+	NewBlocks uint64
+	IMaxPct   uint32
+	_         uint32
+}
+
+// struct xfs_growfs_log
+type xfsGrowFSLog struct {
+	NewBlocks uint32
+	IsInt     uint32
+}
+
+// struct xfs_growfs_rt
+type xfsGrowFSRT struct {
+	NewBlocks uint64
+	ExtSize   uint32
+	_         uint32
+}
+
+// Error of xfs ioctl with errno of kernel
+// Ошибка ioctl xfs с errno ядра
+type xfsIoctlError struct {
+	Op         string
+	MountPoint string
+	Errno      syscall.Errno
+}
+
+func (err *xfsIoctlError) Error() string {
+	return fmt.Sprintf("%v %v: %v", err.Op, err.MountPoint, err.Errno.Error())
+}
+
+func (err *xfsIoctlError) Unwrap() error { return err.Errno }
+
+func xfsIoctl(op string, mountPoint string, request uintptr, arg unsafe.Pointer) error {
+	f, err := os.Open(mountPoint)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err = ioctl(f.Fd(), request, arg); err != nil {
+		return &xfsIoctlError{Op: op, MountPoint: mountPoint, Errno: err.(syscall.Errno)}
+	}
+	return nil
+}
+
+/*
+Geometry of mounted xfs from kernel. It is actual right after grow, unlike superblock on disk.
+mountPoint - any path inside of the filesystem.
+Геометрия смонтированной xfs от ядра. Она актуальна сразу после расширения, в отличие от суперблока на диске.
+mountPoint - любой путь внутри файловой системы.
+*/
+func xfsFSGeometry(mountPoint string) (geo xfsGeometry, err error) {
+	err = xfsIoctl("XFS_IOC_FSGEOMETRY", mountPoint, ioctl_XFS_FSGEOMETRY, unsafe.Pointer(&geo))
+	if errors.Is(err, syscall.ENOTTY) {
+		// Kernel older then 5.2
+		// Ядро старше 5.2
+		err = xfsIoctl("XFS_IOC_FSGEOMETRY_V4", mountPoint, ioctl_XFS_FSGEOMETRY_V4, unsafe.Pointer(&geo))
+	}
+	return geo, err
+}
+
+// Grow data section of mounted xfs to newBlocks, imaxpct - current max percent of inodes
+// Расширяет раздел данных смонтированной xfs до newBlocks, imaxpct - текущий максимальный процент inode
+func xfsGrowData(mountPoint string, newBlocks uint64, imaxpct uint32) error {
+	arg := xfsGrowFSData{NewBlocks: newBlocks, IMaxPct: imaxpct}
+	return xfsIoctl("XFS_IOC_FSGROWFSDATA", mountPoint, ioctl_XFS_FSGROWFSDATA, unsafe.Pointer(&arg))
+}
+
+// Grow realtime section to newBlocks, extSize - current size of realtime extent in blocks
+// Расширяет realtime раздел до newBlocks, extSize - текущий размер realtime экстента в блоках
+func xfsGrowRT(mountPoint string, newBlocks uint64, extSize uint32) error {
+	arg := xfsGrowFSRT{NewBlocks: newBlocks, ExtSize: extSize}
+	return xfsIoctl("XFS_IOC_FSGROWFSRT", mountPoint, ioctl_XFS_FSGROWFSRT, unsafe.Pointer(&arg))
+}
+
+// Grow log to newBlocks. Linux kernel now refuses change of log with ENOSYS.
+// Расширяет журнал до newBlocks. Ядро linux сейчас отказывает в изменении журнала с ENOSYS.
+func xfsGrowLog(mountPoint string, newBlocks uint32, internal bool) error {
+	arg := xfsGrowFSLog{NewBlocks: newBlocks}
+	if internal {
+		arg.IsInt = 1
+	}
+	return xfsIoctl("XFS_IOC_FSGROWFSLOG", mountPoint, ioctl_XFS_FSGROWFSLOG, unsafe.Pointer(&arg))
+}